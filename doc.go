@@ -3,4 +3,10 @@
 // 聚合能力：
 //   - slice_collcection：针对切片的泛型集合
 //   - map_collection：针对 map 的泛型集合
+//   - search：基于倒排索引的全文检索
+//   - set：哈希集合、有序集合与并发安全集合
+//   - sketch：Bloom、HyperLogLog、Count-Min 与 TopK 等概率数据结构
+//   - graph：基于边记录的有向图/无向图与常用图算法
+//   - tree：将带父 ID 的扁平记录构建为树并遍历、聚合
+//   - interval：区间映射 RangeMap 与区间树 IntervalCollection
 package collection
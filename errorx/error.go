@@ -19,3 +19,66 @@ var NotHaveKeyCompareFunc = errors.New("not have key compare func")
 var NotHaveValCompareFunc = errors.New("not have value compare func")
 
 var NilFunc = errors.New("func param is nil")
+
+// MergeConflictError 合并时出现键冲突
+var MergeConflictError = errors.New("merge key conflict")
+
+// InvalidPatchError 补丁格式错误或无法应用
+var InvalidPatchError = errors.New("invalid patch")
+
+// PatchTestFailedError JSON Patch 中的 test 操作未通过
+var PatchTestFailedError = errors.New("patch test operation failed")
+
+// IndexNotFoundError 索引不存在
+var IndexNotFoundError = errors.New("index not found")
+
+// IndexExistsError 索引已存在
+var IndexExistsError = errors.New("index already exists")
+
+// UniqueConstraintError 违反唯一索引约束，可通过 errors.Is 判断 *UniqueViolation
+var UniqueConstraintError = errors.New("unique constraint violation")
+
+// UniqueViolation 写入违反唯一索引约束时返回的错误
+type UniqueViolation struct {
+	Index       string // 索引名
+	Value       any    // 冲突的索引值
+	Key         any    // 被拒绝写入的 key
+	ConflictKey any    // 已占用该索引值的 key
+}
+
+// Error 实现 error 接口
+func (e *UniqueViolation) Error() string {
+	return fmt.Sprintf("unique index %q: value %v of key %v already used by key %v", e.Index, e.Value, e.Key, e.ConflictKey)
+}
+
+// Is 支持 errors.Is(err, UniqueConstraintError)
+func (e *UniqueViolation) Is(target error) bool {
+	return target == UniqueConstraintError
+}
+
+// FieldNotFoundError 结构体中不存在指定字段
+var FieldNotFoundError = errors.New("field not found")
+
+// InvalidOperatorError 不支持的操作符
+var InvalidOperatorError = errors.New("invalid operator")
+
+// InvalidExpressionError 表达式语法错误
+var InvalidExpressionError = errors.New("invalid expression")
+
+// InvalidCursorError 分页游标格式错误、签名不匹配或与排序条件不一致
+var InvalidCursorError = errors.New("invalid cursor")
+
+// TooLargeError 结果的规模超出限制
+var TooLargeError = errors.New("result too large")
+
+// InvalidArgumentError 参数不合法，如概率结构的精度或误差率超出范围
+var InvalidArgumentError = errors.New("invalid argument")
+
+// IncompatibleMergeError 合并的两个结构参数不一致
+var IncompatibleMergeError = errors.New("incompatible merge")
+
+// InvalidEncodingError 二进制数据格式错误或已损坏
+var InvalidEncodingError = errors.New("invalid encoding")
+
+// CycleError 图中存在环，可通过 errors.As 获取 graph.CycleError 中的环路
+var CycleError = errors.New("cycle detected")
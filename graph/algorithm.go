@@ -0,0 +1,361 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// TopologicalSort 拓扑排序（Kahn 算法），边 a -> b 表示 a 排在 b 之前；入度同为 0 的节点按首次出现的顺序排列
+// 存在环时返回 *CycleError，其中包含一个环路；无向图返回 errorx.InvalidArgumentError
+func (g *Graph[N, E]) TopologicalSort() ([]N, error) {
+	if !g.directed {
+		return nil, fmt.Errorf("%w: topological sort requires a directed graph", errorx.InvalidArgumentError)
+	}
+
+	indegree := make([]int, len(g.nodes))
+	for v := range g.nodes {
+		indegree[v] = len(g.in[v])
+	}
+	queue := make([]int, 0, len(g.nodes))
+	for v, d := range indegree {
+		if d == 0 {
+			queue = append(queue, v)
+		}
+	}
+
+	order := make([]N, 0, len(g.nodes))
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		order = append(order, g.nodes[u])
+		for _, he := range g.out[u] {
+			if indegree[he.to]--; indegree[he.to] == 0 {
+				queue = append(queue, he.to)
+			}
+		}
+	}
+
+	if len(order) < len(g.nodes) {
+		return nil, &CycleError[N]{Path: g.findCycle(indegree)}
+	}
+	return order, nil
+}
+
+// findCycle 在 Kahn 算法剩余的节点（入度大于 0）中找出一个环：
+// 剩余节点都至少有一个来自剩余节点的入边，沿入边回溯必然回到已访问的节点
+func (g *Graph[N, E]) findCycle(indegree []int) []N {
+	start := 0
+	for indegree[start] == 0 {
+		start++
+	}
+
+	pos := make(map[int]int)
+	path := make([]int, 0)
+	for u := start; ; {
+		if i, ok := pos[u]; ok {
+			path = append(path[i:], u)
+			break
+		}
+		pos[u] = len(path)
+		path = append(path, u)
+		for _, he := range g.in[u] {
+			if indegree[he.to] > 0 {
+				u = he.to
+				break
+			}
+		}
+	}
+
+	// 沿入边回溯得到的是逆序，反转后为边的方向
+	cycle := make([]N, len(path))
+	for i, v := range path {
+		cycle[len(path)-1-i] = g.nodes[v]
+	}
+	return cycle
+}
+
+// BFS 从 start 开始广度优先遍历，fn 接收节点与距 start 的边数，返回 false 时终止
+// 有向图沿出边遍历；start 不存在时返回 errorx.NotFoundError
+func (g *Graph[N, E]) BFS(start N, fn func(node N, depth int) bool) error {
+	s, err := g.lookup(start)
+	if err != nil {
+		return err
+	}
+
+	visited := make([]bool, len(g.nodes))
+	visited[s] = true
+	queue, depth := []int{s}, []int{0}
+	for len(queue) > 0 {
+		u, d := queue[0], depth[0]
+		queue, depth = queue[1:], depth[1:]
+		if !fn(g.nodes[u], d) {
+			return nil
+		}
+		for _, he := range g.out[u] {
+			if !visited[he.to] {
+				visited[he.to] = true
+				queue = append(queue, he.to)
+				depth = append(depth, d+1)
+			}
+		}
+	}
+	return nil
+}
+
+// DFS 从 start 开始深度优先遍历（先序），fn 接收节点与在遍历树中的深度，返回 false 时终止
+// 相邻节点按边的添加顺序访问，使用显式栈，不受递归深度限制；start 不存在时返回 errorx.NotFoundError
+func (g *Graph[N, E]) DFS(start N, fn func(node N, depth int) bool) error {
+	s, err := g.lookup(start)
+	if err != nil {
+		return err
+	}
+
+	type frame struct {
+		node, next int // next 为下一个待访问的邻接表下标
+	}
+	visited := make([]bool, len(g.nodes))
+	visited[s] = true
+	if !fn(g.nodes[s], 0) {
+		return nil
+	}
+	stack := []frame{{node: s}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == len(g.out[top.node]) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		v := g.out[top.node][top.next].to
+		top.next++
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+		if !fn(g.nodes[v], len(stack)) {
+			return nil
+		}
+		stack = append(stack, frame{node: v})
+	}
+	return nil
+}
+
+// Reachable 是否存在从 from 到 to 的路径，节点到自身总是可达
+func (g *Graph[N, E]) Reachable(from, to N) bool {
+	if !g.HasNode(to) {
+		return false
+	}
+	found := false
+	_ = g.BFS(from, func(node N, depth int) bool {
+		found = node == to
+		return !found
+	})
+	return found
+}
+
+// ReachableFrom 返回从 from 出发可达的所有节点（不含 from 自身），按 BFS 顺序排列；from 不存在时返回 errorx.NotFoundError
+func (g *Graph[N, E]) ReachableFrom(from N) ([]N, error) {
+	res := make([]N, 0)
+	err := g.BFS(from, func(node N, depth int) bool {
+		if depth > 0 {
+			res = append(res, node)
+		}
+		return true
+	})
+	return res, err
+}
+
+// ConnectedComponents 连通分量；有向图忽略边的方向，返回弱连通分量
+// 分量按其第一个节点首次出现的顺序排列，分量内的节点按首次出现的顺序排列
+func (g *Graph[N, E]) ConnectedComponents() [][]N {
+	comp := make([]int, len(g.nodes))
+	for i := range comp {
+		comp[i] = -1
+	}
+
+	count := 0
+	for s := range g.nodes {
+		if comp[s] >= 0 {
+			continue
+		}
+		comp[s] = count
+		stack := []int{s}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			visit := func(adj []halfEdge) {
+				for _, he := range adj {
+					if comp[he.to] < 0 {
+						comp[he.to] = count
+						stack = append(stack, he.to)
+					}
+				}
+			}
+			visit(g.out[u])
+			if g.directed {
+				visit(g.in[u])
+			}
+		}
+		count++
+	}
+
+	groups := make([][]N, count)
+	for v, c := range comp {
+		groups[c] = append(groups[c], g.nodes[v])
+	}
+	return groups
+}
+
+// StronglyConnectedComponents 强连通分量（Tarjan 算法），分量按缩点后的拓扑顺序排列：
+// 存在 a -> b 的边时，a 所在的分量排在 b 所在的分量之前（或相同）；分量内的节点按首次出现的顺序排列
+// 无向图的强连通分量即连通分量
+func (g *Graph[N, E]) StronglyConnectedComponents() [][]N {
+	if !g.directed {
+		return g.ConnectedComponents()
+	}
+
+	n := len(g.nodes)
+	index, low, comp := make([]int, n), make([]int, n), make([]int, n)
+	for i := range index {
+		index[i], comp[i] = -1, -1
+	}
+	onStack := make([]bool, n)
+	stack := make([]int, 0)
+	counter, count := 0, 0
+
+	type frame struct {
+		node, next int
+	}
+	for s := 0; s < n; s++ {
+		if index[s] >= 0 {
+			continue
+		}
+		// 使用显式栈模拟递归，避免深链导致的栈增长
+		calls := []frame{{node: s}}
+		index[s], low[s] = counter, counter
+		counter++
+		stack = append(stack, s)
+		onStack[s] = true
+
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			u := top.node
+			if top.next < len(g.out[u]) {
+				v := g.out[u][top.next].to
+				top.next++
+				if index[v] < 0 {
+					index[v], low[v] = counter, counter
+					counter++
+					stack = append(stack, v)
+					onStack[v] = true
+					calls = append(calls, frame{node: v})
+				} else if onStack[v] {
+					low[u] = min(low[u], index[v])
+				}
+				continue
+			}
+
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				low[parent] = min(low[parent], low[u])
+			}
+			if low[u] == index[u] {
+				for {
+					v := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[v] = false
+					comp[v] = count
+					if v == u {
+						break
+					}
+				}
+				count++
+			}
+		}
+	}
+
+	// Tarjan 产生的分量为逆拓扑顺序
+	for i := range comp {
+		comp[i] = count - 1 - comp[i]
+	}
+	groups := make([][]N, count)
+	for v, c := range comp {
+		groups[c] = append(groups[c], g.nodes[v])
+	}
+	return groups
+}
+
+// ShortestPath 使用 Dijkstra 算法求 from 到 to 的最短路径，返回路径上的节点（含两端）与总权重
+// 节点不存在或不可达时返回 errorx.NotFoundError，遇到负权重的边时返回 errorx.InvalidArgumentError
+func (g *Graph[N, E]) ShortestPath(from, to N) ([]N, float64, error) {
+	s, err := g.lookup(from)
+	if err != nil {
+		return nil, 0, err
+	}
+	t, err := g.lookup(to)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dist := make([]float64, len(g.nodes))
+	prev := make([]int, len(g.nodes))
+	for i := range dist {
+		dist[i], prev[i] = math.Inf(1), -1
+	}
+	dist[s] = 0
+	pq := &distHeap{{node: s}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(distItem)
+		u := item.node
+		if item.dist > dist[u] {
+			continue
+		}
+		if u == t {
+			break
+		}
+		for _, he := range g.out[u] {
+			w := g.edges[he.edge].Weight
+			if w < 0 {
+				return nil, 0, fmt.Errorf("%w: negative edge weight %v", errorx.InvalidArgumentError, w)
+			}
+			if d := dist[u] + w; d < dist[he.to] {
+				dist[he.to], prev[he.to] = d, u
+				heap.Push(pq, distItem{node: he.to, dist: d})
+			}
+		}
+	}
+
+	if math.IsInf(dist[t], 1) {
+		return nil, 0, fmt.Errorf("%w: no path from %v to %v", errorx.NotFoundError, from, to)
+	}
+	path := make([]N, 0)
+	for v := t; v >= 0; v = prev[v] {
+		path = append(path, g.nodes[v])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dist[t], nil
+}
+
+// distItem Dijkstra 优先队列中的项
+type distItem struct {
+	node int
+	dist float64
+}
+
+type distHeap []distItem
+
+func (h distHeap) Len() int           { return len(h) }
+func (h distHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h distHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *distHeap) Push(x any)        { *h = append(*h, x.(distItem)) }
+func (h *distHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,3 @@
+// Package graph 提供基于边记录构建的有向图与无向图，支持拓扑排序（报告环路）、BFS/DFS 遍历、
+// 连通分量、强连通分量、Dijkstra 最短路径与可达性查询，边可直接从 slice_collcection 中的记录构建。
+package graph
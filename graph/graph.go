@@ -0,0 +1,262 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// Graph 有向图或无向图，节点类型为 N，边携带原始记录 E
+// 节点按首次出现的顺序编号，邻接表按边的添加顺序排列，因此遍历与各算法的结果都是确定的。Graph 不是并发安全的
+type Graph[N comparable, E any] struct {
+	directed bool
+	nodes    []N
+	index    map[N]int
+	out      [][]halfEdge // 出边；无向图中每条边在两端各记录一次
+	in       [][]halfEdge // 入边，仅有向图使用
+	edges    []Edge[N, E]
+}
+
+// Edge 图中的边
+type Edge[N comparable, E any] struct {
+	From   N
+	To     N
+	Weight float64
+	Data   E // 构建该边的原始记录
+}
+
+// halfEdge 邻接表中的一项
+type halfEdge struct {
+	to   int // 相邻节点的编号
+	edge int // 在 edges 中的下标
+}
+
+// Option 构建选项
+type Option[E any] func(*options[E])
+
+type options[E any] struct {
+	weight func(item E) float64
+}
+
+// WithWeight 设置边权重的提取函数，未设置时每条边的权重为 1
+func WithWeight[E any](fn func(item E) float64) Option[E] {
+	return func(o *options[E]) {
+		o.weight = fn
+	}
+}
+
+// New 创建空图，directed 为 false 时为无向图
+func New[N comparable, E any](directed bool) *Graph[N, E] {
+	return &Graph[N, E]{directed: directed, index: make(map[N]int)}
+}
+
+// NewDirected 使用 Collection 中的边记录创建有向图，from、to 提取边的起点与终点
+//
+// 使用示例：
+//
+//	g := graph.NewDirected(deps, func(d Dep) string { return d.Task }, func(d Dep) string { return d.DependsOn })
+//	order, err := g.TopologicalSort()
+//	var cycle *graph.CycleError[string]
+//	if errors.As(err, &cycle) {
+//	    fmt.Println(cycle.Path) // [a b c a]
+//	}
+func NewDirected[E any, N comparable](c *slice_collcection.Collection[E], from, to func(item E) N, opts ...Option[E]) *Graph[N, E] {
+	return build(true, c, from, to, opts)
+}
+
+// NewUndirected 使用 Collection 中的边记录创建无向图，from、to 提取边的两个端点
+func NewUndirected[E any, N comparable](c *slice_collcection.Collection[E], from, to func(item E) N, opts ...Option[E]) *Graph[N, E] {
+	return build(false, c, from, to, opts)
+}
+
+func build[E any, N comparable](directed bool, c *slice_collcection.Collection[E], from, to func(item E) N, opts []Option[E]) *Graph[N, E] {
+	o := options[E]{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	g := New[N, E](directed)
+	if c == nil {
+		return g
+	}
+	c.Foreach(func(item E, key int) {
+		weight := 1.0
+		if o.weight != nil {
+			weight = o.weight(item)
+		}
+		g.AddEdge(from(item), to(item), weight, item)
+	})
+	return g
+}
+
+// AddNode 添加孤立节点，节点已存在时不做修改
+func (g *Graph[N, E]) AddNode(nodes ...N) *Graph[N, E] {
+	for _, n := range nodes {
+		g.nodeIndex(n)
+	}
+	return g
+}
+
+// AddEdge 添加一条边，端点不存在时自动添加；允许重复边与自环
+func (g *Graph[N, E]) AddEdge(from, to N, weight float64, data E) *Graph[N, E] {
+	u, v := g.nodeIndex(from), g.nodeIndex(to)
+	id := len(g.edges)
+	g.edges = append(g.edges, Edge[N, E]{From: from, To: to, Weight: weight, Data: data})
+
+	g.out[u] = append(g.out[u], halfEdge{to: v, edge: id})
+	if g.directed {
+		g.in[v] = append(g.in[v], halfEdge{to: u, edge: id})
+	} else if u != v {
+		g.out[v] = append(g.out[v], halfEdge{to: u, edge: id})
+	}
+	return g
+}
+
+// Directed 是否为有向图
+func (g *Graph[N, E]) Directed() bool {
+	return g.directed
+}
+
+// HasNode 是否包含节点
+func (g *Graph[N, E]) HasNode(n N) bool {
+	_, ok := g.index[n]
+	return ok
+}
+
+// HasEdge 是否存在 from 到 to 的边（无向图中不区分方向）
+func (g *Graph[N, E]) HasEdge(from, to N) bool {
+	u, ok := g.index[from]
+	if !ok {
+		return false
+	}
+	v, ok := g.index[to]
+	if !ok {
+		return false
+	}
+	for _, he := range g.out[u] {
+		if he.to == v {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeCount 节点数量
+func (g *Graph[N, E]) NodeCount() int {
+	return len(g.nodes)
+}
+
+// EdgeCount 边数量
+func (g *Graph[N, E]) EdgeCount() int {
+	return len(g.edges)
+}
+
+// Nodes 按首次出现的顺序返回所有节点
+func (g *Graph[N, E]) Nodes() []N {
+	return append(make([]N, 0, len(g.nodes)), g.nodes...)
+}
+
+// Edges 按添加顺序返回所有边
+func (g *Graph[N, E]) Edges() *slice_collcection.Collection[Edge[N, E]] {
+	return slice_collcection.NewCollection(append(make([]Edge[N, E], 0, len(g.edges)), g.edges...))
+}
+
+// Neighbors 返回 n 的后继节点（无向图为相邻节点），按边的添加顺序排列并去重；节点不存在时返回空切片
+func (g *Graph[N, E]) Neighbors(n N) []N {
+	u, ok := g.index[n]
+	if !ok {
+		return []N{}
+	}
+	return g.collect(g.out[u])
+}
+
+// Predecessors 返回 n 的前驱节点（无向图与 Neighbors 相同），按边的添加顺序排列并去重
+func (g *Graph[N, E]) Predecessors(n N) []N {
+	u, ok := g.index[n]
+	if !ok {
+		return []N{}
+	}
+	if !g.directed {
+		return g.collect(g.out[u])
+	}
+	return g.collect(g.in[u])
+}
+
+// OutDegree 出边数量（无向图为度数，自环计一次）
+func (g *Graph[N, E]) OutDegree(n N) int {
+	if u, ok := g.index[n]; ok {
+		return len(g.out[u])
+	}
+	return 0
+}
+
+// InDegree 入边数量（无向图与 OutDegree 相同）
+func (g *Graph[N, E]) InDegree(n N) int {
+	u, ok := g.index[n]
+	if !ok {
+		return 0
+	}
+	if !g.directed {
+		return len(g.out[u])
+	}
+	return len(g.in[u])
+}
+
+// CycleError 拓扑排序发现环时返回的错误，Path 为环上的节点，首尾相同
+// 支持 errors.Is(err, errorx.CycleError)
+type CycleError[N comparable] struct {
+	Path []N
+}
+
+// Error 实现 error 接口
+func (e *CycleError[N]) Error() string {
+	parts := make([]string, 0, len(e.Path))
+	for _, n := range e.Path {
+		parts = append(parts, fmt.Sprint(n))
+	}
+	return fmt.Sprintf("%v: %s", errorx.CycleError, strings.Join(parts, " -> "))
+}
+
+// Is 支持 errors.Is(err, errorx.CycleError)
+func (e *CycleError[N]) Is(target error) bool {
+	return target == errorx.CycleError
+}
+
+// nodeIndex 返回节点编号，不存在时添加
+func (g *Graph[N, E]) nodeIndex(n N) int {
+	if i, ok := g.index[n]; ok {
+		return i
+	}
+	i := len(g.nodes)
+	g.index[n] = i
+	g.nodes = append(g.nodes, n)
+	g.out = append(g.out, nil)
+	if g.directed {
+		g.in = append(g.in, nil)
+	}
+	return i
+}
+
+// collect 将邻接表转换为去重后的节点
+func (g *Graph[N, E]) collect(adj []halfEdge) []N {
+	res := make([]N, 0, len(adj))
+	seen := make(map[int]bool, len(adj))
+	for _, he := range adj {
+		if !seen[he.to] {
+			seen[he.to] = true
+			res = append(res, g.nodes[he.to])
+		}
+	}
+	return res
+}
+
+// lookup 返回节点编号，不存在时返回 errorx.NotFoundError
+func (g *Graph[N, E]) lookup(n N) (int, error) {
+	i, ok := g.index[n]
+	if !ok {
+		return -1, fmt.Errorf("%w: node %v", errorx.NotFoundError, n)
+	}
+	return i, nil
+}
@@ -0,0 +1,194 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+type dep struct {
+	Task      string
+	DependsOn string
+}
+
+type road struct {
+	From, To string
+	Km       float64
+}
+
+func taskGraph(edges ...dep) *Graph[string, dep] {
+	// DependsOn -> Task：被依赖的任务排在前面
+	return NewDirected(slice_collcection.NewCollection(edges),
+		func(d dep) string { return d.DependsOn },
+		func(d dep) string { return d.Task })
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := taskGraph(
+		dep{"build", "fetch"},
+		dep{"test", "build"},
+		dep{"lint", "fetch"},
+		dep{"release", "test"},
+		dep{"release", "lint"},
+	)
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort returned error: %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"fetch", "build", "lint", "test", "release"}) {
+		t.Errorf("Unexpected order %v", order)
+	}
+
+	cyclic := taskGraph(
+		dep{"b", "a"},
+		dep{"c", "b"},
+		dep{"a", "c"},
+		dep{"d", "c"},
+		dep{"a", "root"},
+	)
+	_, err = cyclic.TopologicalSort()
+	var cycle *CycleError[string]
+	if !errors.Is(err, errorx.CycleError) || !errors.As(err, &cycle) {
+		t.Fatalf("Expected CycleError, got %v", err)
+	}
+	if len(cycle.Path) != 4 || cycle.Path[0] != cycle.Path[3] {
+		t.Errorf("Unexpected cycle %v", cycle.Path)
+	}
+	for i := 0; i+1 < len(cycle.Path); i++ {
+		if !cyclic.HasEdge(cycle.Path[i], cycle.Path[i+1]) {
+			t.Errorf("Cycle %v does not follow edge directions", cycle.Path)
+		}
+	}
+
+	self := New[int, struct{}](true).AddEdge(1, 1, 1, struct{}{})
+	if _, err := self.TopologicalSort(); err == nil || err.Error() != "cycle detected: 1 -> 1" {
+		t.Errorf("Unexpected self loop error %v", err)
+	}
+
+	if _, err := New[int, struct{}](false).TopologicalSort(); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	g := New[int, struct{}](true)
+	for _, e := range [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}, {6, 1}} {
+		g.AddEdge(e[0], e[1], 1, struct{}{})
+	}
+
+	var bfs, bfsDepth []int
+	if err := g.BFS(1, func(node int, depth int) bool {
+		bfs = append(bfs, node)
+		bfsDepth = append(bfsDepth, depth)
+		return true
+	}); err != nil {
+		t.Fatalf("BFS returned error: %v", err)
+	}
+	if !reflect.DeepEqual(bfs, []int{1, 2, 3, 4, 5}) || !reflect.DeepEqual(bfsDepth, []int{0, 1, 1, 2, 3}) {
+		t.Errorf("Unexpected BFS %v %v", bfs, bfsDepth)
+	}
+
+	var dfs, dfsDepth []int
+	_ = g.DFS(1, func(node int, depth int) bool {
+		dfs = append(dfs, node)
+		dfsDepth = append(dfsDepth, depth)
+		return node != 5
+	})
+	if !reflect.DeepEqual(dfs, []int{1, 2, 4, 5}) || !reflect.DeepEqual(dfsDepth, []int{0, 1, 2, 3}) {
+		t.Errorf("Unexpected DFS %v %v", dfs, dfsDepth)
+	}
+
+	if err := g.BFS(42, func(int, int) bool { return true }); !errors.Is(err, errorx.NotFoundError) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+
+	if !g.Reachable(6, 5) || g.Reachable(5, 6) || !g.Reachable(3, 3) || g.Reachable(1, 42) {
+		t.Error("Unexpected Reachable result")
+	}
+	if got, err := g.ReachableFrom(2); err != nil || !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Errorf("Unexpected ReachableFrom %v (%v)", got, err)
+	}
+	if !reflect.DeepEqual(g.Neighbors(1), []int{2, 3}) || !reflect.DeepEqual(g.Predecessors(4), []int{2, 3}) {
+		t.Error("Unexpected neighbors")
+	}
+	if g.InDegree(4) != 2 || g.OutDegree(1) != 2 || g.NodeCount() != 6 || g.EdgeCount() != 6 {
+		t.Error("Unexpected degree or count")
+	}
+}
+
+func TestComponents(t *testing.T) {
+	g := New[string, struct{}](true)
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"d", "e"}, {"e", "d"}, {"x", "y"}} {
+		g.AddEdge(e[0], e[1], 1, struct{}{})
+	}
+	g.AddNode("z")
+
+	if got := g.ConnectedComponents(); !reflect.DeepEqual(got, [][]string{{"a", "b", "c", "d", "e"}, {"x", "y"}, {"z"}}) {
+		t.Errorf("Unexpected weakly connected components %v", got)
+	}
+
+	scc := g.StronglyConnectedComponents()
+	position := make(map[string]int)
+	for i, comp := range scc {
+		for _, n := range comp {
+			position[n] = i
+		}
+	}
+	if len(scc) != 5 || !reflect.DeepEqual(scc[position["a"]], []string{"a", "b", "c"}) || !reflect.DeepEqual(scc[position["d"]], []string{"d", "e"}) {
+		t.Errorf("Unexpected strongly connected components %v", scc)
+	}
+	for _, e := range g.Edges().Values() {
+		if position[e.From] > position[e.To] {
+			t.Errorf("Components %v are not in topological order", scc)
+		}
+	}
+
+	undirected := NewUndirected(slice_collcection.NewCollection([]road{{"a", "b", 1}, {"c", "d", 1}}),
+		func(r road) string { return r.From }, func(r road) string { return r.To })
+	if got := undirected.StronglyConnectedComponents(); !reflect.DeepEqual(got, [][]string{{"a", "b"}, {"c", "d"}}) {
+		t.Errorf("Unexpected undirected components %v", got)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	roads := slice_collcection.NewCollection([]road{
+		{"home", "a", 4},
+		{"home", "b", 1},
+		{"b", "a", 2},
+		{"a", "office", 1},
+		{"b", "office", 5},
+		{"island", "ferry", 1},
+	})
+	g := NewUndirected(roads, func(r road) string { return r.From }, func(r road) string { return r.To },
+		WithWeight(func(r road) float64 { return r.Km }))
+
+	path, dist, err := g.ShortestPath("office", "home")
+	if err != nil {
+		t.Fatalf("ShortestPath returned error: %v", err)
+	}
+	if !reflect.DeepEqual(path, []string{"office", "a", "b", "home"}) || dist != 4 {
+		t.Errorf("Unexpected path %v with distance %v", path, dist)
+	}
+	if path, dist, _ := g.ShortestPath("home", "home"); !reflect.DeepEqual(path, []string{"home"}) || dist != 0 {
+		t.Errorf("Unexpected trivial path %v %v", path, dist)
+	}
+	if _, _, err := g.ShortestPath("home", "island"); !errors.Is(err, errorx.NotFoundError) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+
+	hops := NewDirected(roads, func(r road) string { return r.From }, func(r road) string { return r.To })
+	if path, dist, _ := hops.ShortestPath("home", "office"); !reflect.DeepEqual(path, []string{"home", "a", "office"}) || dist != 2 {
+		t.Errorf("Unexpected unweighted path %v %v", path, dist)
+	}
+	if _, _, err := hops.ShortestPath("office", "home"); !errors.Is(err, errorx.NotFoundError) {
+		t.Errorf("Directed graph should not be traversed backwards, got %v", err)
+	}
+
+	negative := New[int, struct{}](true).AddEdge(1, 2, -1, struct{}{})
+	if _, _, err := negative.ShortestPath(1, 2); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+// Package interval 提供基于半开区间 [Start, End) 的数据结构：RangeMap 将互不重叠的区间映射到值并自动合并相邻的相同值区间，
+// IntervalCollection 使用区间树回答“哪些区间与某个点或区间重叠”，并支持合并、求空隙与区间相减。
+package interval
@@ -0,0 +1,202 @@
+package interval
+
+import (
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// IntervalCollection 基于区间树的区间集合，元素为记录 T，区间 [Start, End) 由 rangeFn 提取
+// 内部按 Start 排序并以隐式平衡二叉树保存子树的最大 End，重叠查询为 O(log n + k)；
+// Add 时立即重建（O(n + m log m)，m 为新增数量），查询不修改内部状态，没有 Add 时可以并发查询。Start 不小于 End 的空区间不参与查询。
+// Add 与查询之间不是并发安全的
+type IntervalCollection[K any, T any] struct {
+	items   []intervalItem[K, T] // 按 Start 排序，Start 相同时保持添加顺序
+	maxEnd  []K                  // 以 items[i] 为根的子树中最大的 End
+	rangeFn func(item T) Range[K]
+	compare func(a, b K) int
+}
+
+type intervalItem[K any, T any] struct {
+	Range[K]
+	item T
+}
+
+// NewIntervalCollection 使用 Collection 中的记录创建区间集合，rangeFn 提取记录的半开区间
+// compare 为 nil 时使用 K 的默认比较函数（见 utils.NewCompareFuncOf），没有默认比较函数时返回 errorx.NoComparableError
+//
+// 使用示例：
+//
+//	bookings, _ := interval.NewIntervalCollection(existing, func(b Booking) interval.Range[time.Time] {
+//	    return interval.Range[time.Time]{Start: b.From, End: b.To}
+//	}, nil)
+//	conflicts := bookings.Overlapping(interval.Range[time.Time]{Start: from, End: to})
+func NewIntervalCollection[K any, T any](c *slice_collcection.Collection[T], rangeFn func(item T) Range[K], compare func(a, b K) int) (*IntervalCollection[K, T], error) {
+	compare, err := resolveCompare(compare)
+	if err != nil {
+		return nil, err
+	}
+	ic := &IntervalCollection[K, T]{rangeFn: rangeFn, compare: compare}
+	if c != nil {
+		ic.Add(c.Values()...)
+	}
+	return ic, nil
+}
+
+// NewRanges 使用区间本身创建区间集合，适合只做 MergeOverlapping、Gaps、Subtract 等区间运算的场景
+func NewRanges[K any](compare func(a, b K) int, ranges ...Range[K]) (*IntervalCollection[K, Range[K]], error) {
+	return NewIntervalCollection(slice_collcection.NewCollection(ranges), func(r Range[K]) Range[K] { return r }, compare)
+}
+
+// Add 添加记录，空区间的记录会被忽略
+func (ic *IntervalCollection[K, T]) Add(items ...T) *IntervalCollection[K, T] {
+	added := make([]intervalItem[K, T], 0, len(items))
+	for _, item := range items {
+		r := ic.rangeFn(item)
+		if ic.compare(r.Start, r.End) >= 0 {
+			continue
+		}
+		added = append(added, intervalItem[K, T]{Range: r, item: item})
+	}
+	if len(added) > 0 {
+		ic.build(added)
+	}
+	return ic
+}
+
+// Count 区间数量（不含被忽略的空区间）
+func (ic *IntervalCollection[K, T]) Count() int {
+	return len(ic.items)
+}
+
+// IsEmpty 是否为空
+func (ic *IntervalCollection[K, T]) IsEmpty() bool {
+	return len(ic.items) == 0
+}
+
+// Values 按区间 Start 排序返回所有记录
+func (ic *IntervalCollection[K, T]) Values() []T {
+	res := make([]T, 0, len(ic.items))
+	for _, it := range ic.items {
+		res = append(res, it.item)
+	}
+	return res
+}
+
+// Containing 返回包含点 p 的所有记录（Start <= p < End），按 Start 排序
+func (ic *IntervalCollection[K, T]) Containing(p K) *slice_collcection.Collection[T] {
+	return ic.query(p, func(start K) bool {
+		return ic.compare(start, p) <= 0
+	})
+}
+
+// Overlapping 返回与 r 重叠的所有记录（a.Start < r.End && r.Start < a.End），按 Start 排序；r 为空区间时返回空 Collection
+func (ic *IntervalCollection[K, T]) Overlapping(r Range[K]) *slice_collcection.Collection[T] {
+	if ic.compare(r.Start, r.End) >= 0 {
+		return slice_collcection.NewCollection([]T{})
+	}
+	return ic.query(r.Start, func(start K) bool {
+		return ic.compare(start, r.End) < 0
+	})
+}
+
+// AnyOverlapping 是否存在与 r 重叠的区间，用于冲突检查
+func (ic *IntervalCollection[K, T]) AnyOverlapping(r Range[K]) bool {
+	return ic.Overlapping(r).IsNotEmpty()
+}
+
+// MergeOverlapping 合并重叠或首尾相接的区间，返回覆盖范围相同的有序、互不相交的区间
+func (ic *IntervalCollection[K, T]) MergeOverlapping() []Range[K] {
+	res := make([]Range[K], 0)
+	for _, it := range ic.items {
+		if n := len(res); n > 0 && ic.compare(it.Start, res[n-1].End) <= 0 {
+			res[n-1].End = maxKey(ic.compare, res[n-1].End, it.End)
+			continue
+		}
+		res = append(res, it.Range)
+	}
+	return res
+}
+
+// Gaps 返回 within 内没有被任何区间覆盖的部分
+func (ic *IntervalCollection[K, T]) Gaps(within Range[K]) []Range[K] {
+	if ic.compare(within.Start, within.End) >= 0 {
+		return []Range[K]{}
+	}
+	return subtractRanges(ic.compare, []Range[K]{within}, ic.MergeOverlapping())
+}
+
+// Subtract 返回被本集合覆盖、但不被 ranges 覆盖的部分，结果有序且互不相交
+func (ic *IntervalCollection[K, T]) Subtract(ranges ...Range[K]) []Range[K] {
+	cut, _ := NewRanges(ic.compare, ranges...)
+	return subtractRanges(ic.compare, ic.MergeOverlapping(), cut.MergeOverlapping())
+}
+
+// query 返回 End > from 且 startOK(Start) 的记录；startOK 对 Start 单调（满足的 Start 均在不满足的之前）
+func (ic *IntervalCollection[K, T]) query(from K, startOK func(start K) bool) *slice_collcection.Collection[T] {
+	res := make([]T, 0)
+	var visit func(lo, hi int)
+	visit = func(lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		// 子树中所有区间都在 from 之前结束
+		if ic.compare(ic.maxEnd[mid], from) <= 0 {
+			return
+		}
+		visit(lo, mid)
+		if !startOK(ic.items[mid].Start) {
+			// 右子树的 Start 不小于 items[mid].Start，同样不满足
+			return
+		}
+		if ic.compare(ic.items[mid].End, from) > 0 {
+			res = append(res, ic.items[mid].item)
+		}
+		visit(mid+1, hi)
+	}
+	visit(0, len(ic.items))
+	return slice_collcection.NewCollection(res)
+}
+
+// build 将 added 排序后归并到 items（Start 相同时已有的在前），并重新计算每个子树的最大 End
+// 重建的结果写入新的切片，不修改已有的 items 与 maxEnd
+func (ic *IntervalCollection[K, T]) build(added []intervalItem[K, T]) {
+	sort.SliceStable(added, func(i, j int) bool {
+		return ic.compare(added[i].Start, added[j].Start) < 0
+	})
+	merged := make([]intervalItem[K, T], 0, len(ic.items)+len(added))
+	i, j := 0, 0
+	for i < len(ic.items) && j < len(added) {
+		if ic.compare(added[j].Start, ic.items[i].Start) < 0 {
+			merged = append(merged, added[j])
+			j++
+		} else {
+			merged = append(merged, ic.items[i])
+			i++
+		}
+	}
+	merged = append(merged, ic.items[i:]...)
+	merged = append(merged, added[j:]...)
+	ic.items = merged
+
+	ic.maxEnd = make([]K, len(ic.items))
+	var fill func(lo, hi int) (K, bool)
+	fill = func(lo, hi int) (K, bool) {
+		if lo >= hi {
+			var zero K
+			return zero, false
+		}
+		mid := (lo + hi) / 2
+		end := ic.items[mid].End
+		if left, ok := fill(lo, mid); ok {
+			end = maxKey(ic.compare, end, left)
+		}
+		if right, ok := fill(mid+1, hi); ok {
+			end = maxKey(ic.compare, end, right)
+		}
+		ic.maxEnd[mid] = end
+		return end, true
+	}
+	fill(0, len(ic.items))
+}
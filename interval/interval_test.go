@@ -0,0 +1,276 @@
+package interval
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+func TestRangeMap(t *testing.T) {
+	brackets, err := NewRangeMap[float64, float64](nil)
+	if err != nil {
+		t.Fatalf("NewRangeMap returned error: %v", err)
+	}
+	brackets.Set(0, 5000, 0).Set(5000, 8000, 0.03).Set(8000, math.Inf(1), 0.1)
+
+	for income, expected := range map[float64]float64{0: 0, 4999.99: 0, 5000: 0.03, 7999: 0.03, 8000: 0.1, 1e9: 0.1} {
+		if rate, ok := brackets.Get(income); !ok || rate != expected {
+			t.Errorf("Get(%v): expected %v, got %v (%v)", income, expected, rate, ok)
+		}
+	}
+	if _, ok := brackets.Get(-1); ok {
+		t.Error("Negative income should not be mapped")
+	}
+
+	// 覆盖中间的一段后，再恢复为原值会自动合并
+	brackets.Set(6000, 7000, 0.05)
+	if brackets.Count() != 5 {
+		t.Fatalf("Expected 5 ranges, got %v", brackets.Entries())
+	}
+	if entry, _ := brackets.GetEntry(6500); entry.Start != 6000 || entry.End != 7000 || entry.Value != 0.05 {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	brackets.Set(6000, 7000, 0.03)
+	if brackets.Count() != 3 {
+		t.Errorf("Equal adjacent ranges should coalesce, got %v", brackets.Entries())
+	}
+
+	brackets.Remove(1000, 2000)
+	if brackets.Has(1500) || !brackets.Has(999) || !brackets.Has(2000) {
+		t.Error("Unexpected state after Remove")
+	}
+	if got := brackets.Gaps(Range[float64]{Start: -100, End: 3000}); !reflect.DeepEqual(got, []Range[float64]{{-100, 0}, {1000, 2000}}) {
+		t.Errorf("Unexpected gaps %v", got)
+	}
+	if got := brackets.Overlapping(1500, 5001); len(got) != 2 || got[0].Start != 2000 || got[1].Value != 0.03 {
+		t.Errorf("Unexpected overlapping entries %v", got)
+	}
+
+	brackets.Set(10, 5, 1)
+	if brackets.Count() != 4 {
+		t.Errorf("Invalid ranges should be ignored, got %v", brackets.Entries())
+	}
+	if _, err := NewRangeMap[struct{}, int](nil); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+}
+
+type minutes int
+
+type version string
+
+func TestNamedKeyTypes(t *testing.T) {
+	shifts, err := NewRangeMap[minutes, string](nil)
+	if err != nil {
+		t.Fatalf("NewRangeMap returned error: %v", err)
+	}
+	shifts.Set(480, 720, "morning").Set(720, 1080, "afternoon")
+	if name, ok := shifts.Get(600); !ok || name != "morning" {
+		t.Errorf("Expected morning, got %q (%v)", name, ok)
+	}
+	if _, ok := shifts.Get(1080); ok {
+		t.Error("End of the last range should not be mapped")
+	}
+
+	releases, err := NewRangeMap[string, int](nil)
+	if err != nil {
+		t.Fatalf("NewRangeMap with string keys returned error: %v", err)
+	}
+	releases.Set("a", "m", 1).Set("m", "z", 2)
+	if v, ok := releases.Get("kiwi"); !ok || v != 1 {
+		t.Errorf("Expected 1 for kiwi, got %d (%v)", v, ok)
+	}
+
+	ranges, err := NewRanges[version](nil, Range[version]{"1.0", "1.5"}, Range[version]{"2.0", "3.0"})
+	if err != nil {
+		t.Fatalf("NewRanges returned error: %v", err)
+	}
+	if got := ranges.Overlapping(Range[version]{"1.2", "2.1"}).Count(); got != 2 {
+		t.Errorf("Expected 2 overlapping ranges, got %d", got)
+	}
+}
+
+func TestRangeMapRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	m, _ := NewRangeMap[int, int](nil)
+	model := make(map[int]int)
+	for step := 0; step < 2000; step++ {
+		start := r.Intn(100)
+		end := start + r.Intn(20)
+		if r.Intn(4) == 0 {
+			m.Remove(start, end)
+			for k := start; k < end; k++ {
+				delete(model, k)
+			}
+		} else {
+			v := r.Intn(3)
+			m.Set(start, end, v)
+			for k := start; k < end; k++ {
+				model[k] = v
+			}
+		}
+
+		entries := m.Entries()
+		for i := 1; i < len(entries); i++ {
+			prev, cur := entries[i-1], entries[i]
+			if prev.End > cur.Start || prev.End == cur.Start && prev.Value == cur.Value {
+				t.Fatalf("Entries are not normalized at step %d: %v", step, entries)
+			}
+		}
+	}
+	for k := -1; k < 130; k++ {
+		v, ok := m.Get(k)
+		expected, exists := model[k]
+		if ok != exists || v != expected {
+			t.Fatalf("Get(%d): expected %d/%v, got %d/%v", k, expected, exists, v, ok)
+		}
+	}
+}
+
+type booking struct {
+	Room string
+	From time.Time
+	To   time.Time
+}
+
+func at(hour int) time.Time {
+	return time.Date(2024, 5, 1, hour, 0, 0, 0, time.UTC)
+}
+
+func TestIntervalCollection(t *testing.T) {
+	bookings := slice_collcection.NewCollection([]booking{
+		{"standup", at(9), at(10)},
+		{"review", at(13), at(15)},
+		{"lunch", at(12), at(13)},
+		{"planning", at(9), at(12)},
+		{"empty", at(16), at(16)},
+	})
+	ic, err := NewIntervalCollection(bookings, func(b booking) Range[time.Time] {
+		return Range[time.Time]{Start: b.From, End: b.To}
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewIntervalCollection returned error: %v", err)
+	}
+	rooms := func(c *slice_collcection.Collection[booking]) []string {
+		res := make([]string, 0)
+		for _, b := range c.Values() {
+			res = append(res, b.Room)
+		}
+		return res
+	}
+
+	if ic.Count() != 4 {
+		t.Errorf("Empty intervals should be ignored, got %d", ic.Count())
+	}
+	if got := rooms(ic.Containing(at(9))); !reflect.DeepEqual(got, []string{"standup", "planning"}) {
+		t.Errorf("Unexpected bookings at 9:00 %v", got)
+	}
+	if got := rooms(ic.Containing(at(13))); !reflect.DeepEqual(got, []string{"review"}) {
+		t.Errorf("Half-open intervals should not contain their end, got %v", got)
+	}
+	if got := rooms(ic.Overlapping(Range[time.Time]{Start: at(11), End: at(14)})); !reflect.DeepEqual(got, []string{"planning", "lunch", "review"}) {
+		t.Errorf("Unexpected overlapping bookings %v", got)
+	}
+	if ic.AnyOverlapping(Range[time.Time]{Start: at(15), End: at(17)}) {
+		t.Error("15:00-17:00 should be free")
+	}
+
+	merged := ic.MergeOverlapping()
+	if !reflect.DeepEqual(merged, []Range[time.Time]{{at(9), at(15)}}) {
+		t.Errorf("Unexpected merged ranges %v", merged)
+	}
+	if got := ic.Gaps(Range[time.Time]{Start: at(8), End: at(18)}); !reflect.DeepEqual(got, []Range[time.Time]{{at(8), at(9)}, {at(15), at(18)}}) {
+		t.Errorf("Unexpected gaps %v", got)
+	}
+	if got := ic.Subtract(Range[time.Time]{Start: at(10), End: at(11)}, Range[time.Time]{Start: at(14), End: at(20)}); !reflect.DeepEqual(got, []Range[time.Time]{{at(9), at(10)}, {at(11), at(14)}}) {
+		t.Errorf("Unexpected subtraction %v", got)
+	}
+
+	ic.Add(booking{"late", at(17), at(18)})
+	if got := rooms(ic.Containing(at(17))); !reflect.DeepEqual(got, []string{"late"}) {
+		t.Errorf("Add should be visible to queries, got %v", got)
+	}
+}
+
+func TestIntervalCollectionConcurrentQueries(t *testing.T) {
+	ic, _ := NewRanges[int](nil, Range[int]{0, 10}, Range[int]{5, 15})
+	ic.Add(Range[int]{20, 30}, Range[int]{1, 2})
+
+	// 查询不修改内部状态，Add 之后可以并发查询
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if got := ic.Overlapping(Range[int]{8, 22}).Count(); got != 3 {
+				t.Errorf("Expected 3 overlapping ranges, got %d", got)
+			}
+			if got := len(ic.MergeOverlapping()); got != 2 {
+				t.Errorf("Expected 2 merged ranges, got %d", got)
+			}
+			_ = ic.Values()
+		}()
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestIntervalCollectionRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	ranges := make([]Range[int], 0)
+	for i := 0; i < 300; i++ {
+		start := r.Intn(1000)
+		ranges = append(ranges, Range[int]{Start: start, End: start + 1 + r.Intn(50)})
+	}
+	ic, _ := NewRanges[int](nil, ranges...)
+
+	for q := 0; q < 200; q++ {
+		start := r.Intn(1100) - 50
+		query := Range[int]{Start: start, End: start + r.Intn(30) + 1}
+		expected := make([]Range[int], 0)
+		for _, x := range ranges {
+			if x.Start < query.End && query.Start < x.End {
+				expected = append(expected, x)
+			}
+		}
+		sort.SliceStable(expected, func(i, j int) bool { return expected[i].Start < expected[j].Start })
+		got := ic.Overlapping(query).Values()
+		sort.SliceStable(got, func(i, j int) bool { return got[i].Start < got[j].Start })
+		if len(got) != len(expected) || len(got) > 0 && !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Overlapping(%v): expected %v, got %v", query, expected, got)
+		}
+	}
+
+	// 合并后的覆盖范围与逐点判断一致
+	covered := make(map[int]bool)
+	for _, x := range ranges {
+		for p := x.Start; p < x.End; p++ {
+			covered[p] = true
+		}
+	}
+	merged := ic.MergeOverlapping()
+	for i, m := range merged {
+		if i > 0 && merged[i-1].End >= m.Start {
+			t.Fatalf("Merged ranges should be disjoint: %v", merged)
+		}
+		for p := m.Start; p < m.End; p++ {
+			if !covered[p] {
+				t.Fatalf("Point %d is not covered", p)
+			}
+			delete(covered, p)
+		}
+	}
+	if len(covered) != 0 {
+		t.Errorf("%d points are missing from merged ranges", len(covered))
+	}
+}
@@ -0,0 +1,60 @@
+package interval
+
+import (
+	"reflect"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// Range 半开区间 [Start, End)，Start 不小于 End 时为空区间
+type Range[K any] struct {
+	Start K `json:"start"`
+	End   K `json:"end"`
+}
+
+// resolveCompare compare 为 nil 时使用 utils.NewCompareFuncOf 创建 K 的默认比较函数，没有默认比较函数时返回 errorx.NoComparableError
+func resolveCompare[K any](compare func(a, b K) int) (func(a, b K) int, error) {
+	if compare != nil {
+		return compare, nil
+	}
+	var zero K
+	fn := utils.NewCompareFuncOf(reflect.TypeOf(zero))
+	if fn == nil {
+		return nil, errorx.NoComparableError
+	}
+	return func(a, b K) int {
+		return fn(a, b)
+	}, nil
+}
+
+// maxKey 返回较大的一个
+func maxKey[K any](compare func(a, b K) int, a, b K) K {
+	if compare(a, b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// subtractRanges 从有序、互不相交的 ranges 中减去有序、互不相交的 cut，结果有序且互不相交
+func subtractRanges[K any](compare func(a, b K) int, ranges, cut []Range[K]) []Range[K] {
+	res := make([]Range[K], 0, len(ranges))
+	j := 0
+	for _, r := range ranges {
+		start := r.Start
+		// 跳过完全位于当前区间之前的 cut
+		for j < len(cut) && compare(cut[j].End, start) <= 0 {
+			j++
+		}
+		for k := j; k < len(cut) && compare(cut[k].Start, r.End) < 0; k++ {
+			if compare(cut[k].Start, start) > 0 {
+				res = append(res, Range[K]{Start: start, End: cut[k].Start})
+			}
+			start = maxKey(compare, start, cut[k].End)
+		}
+		if compare(start, r.End) < 0 {
+			res = append(res, Range[K]{Start: start, End: r.End})
+		}
+	}
+	return res
+}
@@ -0,0 +1,188 @@
+package interval
+
+import (
+	"sort"
+)
+
+// RangeEntry RangeMap 中的一段区间及其值
+type RangeEntry[K any, V comparable] struct {
+	Range[K]
+	Value V `json:"value"`
+}
+
+// RangeMap 将互不重叠的半开区间 [Start, End) 映射到值，区间按 Start 有序保存
+// Set 覆盖区间内原有的值，首尾相接且值相等（==）的区间会自动合并，因此同一个值在连续的 key 上只占一个区间。
+// Get 为 O(log n)，Set/Remove 为 O(log n + m)（m 为受影响的区间数，另加切片移动）。RangeMap 不是并发安全的
+type RangeMap[K any, V comparable] struct {
+	entries []RangeEntry[K, V]
+	compare func(a, b K) int
+}
+
+// NewRangeMap 创建 RangeMap，compare 为 nil 时使用 K 的默认比较函数（见 utils.NewCompareFuncOf），
+// 没有默认比较函数时返回 errorx.NoComparableError
+//
+// 使用示例：
+//
+//	brackets, _ := interval.NewRangeMap[float64, float64](nil)
+//	brackets.Set(0, 5000, 0).Set(5000, 8000, 0.03).Set(8000, math.Inf(1), 0.1)
+//	rate, _ := brackets.Get(6500) // 0.03
+func NewRangeMap[K any, V comparable](compare func(a, b K) int) (*RangeMap[K, V], error) {
+	compare, err := resolveCompare(compare)
+	if err != nil {
+		return nil, err
+	}
+	return &RangeMap[K, V]{compare: compare}, nil
+}
+
+// Set 将 [start, end) 映射到 value，覆盖重叠部分原有的值；start 不小于 end 时不做修改
+func (m *RangeMap[K, V]) Set(start, end K, value V) *RangeMap[K, V] {
+	if m.compare(start, end) >= 0 {
+		return m
+	}
+	lo, hi := m.cut(start, end)
+	entry := RangeEntry[K, V]{Range: Range[K]{Start: start, End: end}, Value: value}
+
+	// 与左右相接且值相等的区间合并
+	if lo > 0 && m.entries[lo-1].Value == value && m.compare(m.entries[lo-1].End, start) == 0 {
+		lo--
+		entry.Start = m.entries[lo].Start
+	}
+	if hi < len(m.entries) && m.entries[hi].Value == value && m.compare(m.entries[hi].Start, end) == 0 {
+		entry.End = m.entries[hi].End
+		hi++
+	}
+
+	m.replace(lo, hi, entry)
+	return m
+}
+
+// Remove 删除 [start, end) 内的映射，区间的其余部分保留；start 不小于 end 时不做修改
+func (m *RangeMap[K, V]) Remove(start, end K) *RangeMap[K, V] {
+	if m.compare(start, end) >= 0 {
+		return m
+	}
+	lo, hi := m.cut(start, end)
+	m.entries = append(m.entries[:lo], m.entries[hi:]...)
+	return m
+}
+
+// Get 返回 key 所在区间的值，key 不在任何区间内时返回 false
+func (m *RangeMap[K, V]) Get(key K) (V, bool) {
+	if i := m.find(key); i >= 0 {
+		return m.entries[i].Value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// GetEntry 返回 key 所在的区间及其值，key 不在任何区间内时返回 false
+func (m *RangeMap[K, V]) GetEntry(key K) (RangeEntry[K, V], bool) {
+	if i := m.find(key); i >= 0 {
+		return m.entries[i], true
+	}
+	return RangeEntry[K, V]{}, false
+}
+
+// Has key 是否在某个区间内
+func (m *RangeMap[K, V]) Has(key K) bool {
+	return m.find(key) >= 0
+}
+
+// Overlapping 按顺序返回与 [start, end) 重叠的区间，区间保持原样不做裁剪
+func (m *RangeMap[K, V]) Overlapping(start, end K) []RangeEntry[K, V] {
+	if m.compare(start, end) >= 0 {
+		return []RangeEntry[K, V]{}
+	}
+	lo := sort.Search(len(m.entries), func(i int) bool {
+		return m.compare(m.entries[i].End, start) > 0
+	})
+	res := make([]RangeEntry[K, V], 0)
+	for i := lo; i < len(m.entries) && m.compare(m.entries[i].Start, end) < 0; i++ {
+		res = append(res, m.entries[i])
+	}
+	return res
+}
+
+// Entries 按顺序返回所有区间
+func (m *RangeMap[K, V]) Entries() []RangeEntry[K, V] {
+	return append(make([]RangeEntry[K, V], 0, len(m.entries)), m.entries...)
+}
+
+// Count 区间数量
+func (m *RangeMap[K, V]) Count() int {
+	return len(m.entries)
+}
+
+// IsEmpty 是否为空
+func (m *RangeMap[K, V]) IsEmpty() bool {
+	return len(m.entries) == 0
+}
+
+// Each 按顺序遍历区间，fn 返回 false 时终止
+func (m *RangeMap[K, V]) Each(fn func(r Range[K], value V) bool) {
+	for _, e := range m.entries {
+		if !fn(e.Range, e.Value) {
+			return
+		}
+	}
+}
+
+// Gaps 返回 within 内没有映射的区间
+func (m *RangeMap[K, V]) Gaps(within Range[K]) []Range[K] {
+	if m.compare(within.Start, within.End) >= 0 {
+		return []Range[K]{}
+	}
+	covered := make([]Range[K], 0, len(m.entries))
+	for _, e := range m.entries {
+		covered = append(covered, e.Range)
+	}
+	return subtractRanges(m.compare, []Range[K]{within}, covered)
+}
+
+// find 返回包含 key 的区间下标，不存在时返回 -1
+func (m *RangeMap[K, V]) find(key K) int {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.compare(m.entries[i].End, key) > 0
+	})
+	if i < len(m.entries) && m.compare(m.entries[i].Start, key) <= 0 {
+		return i
+	}
+	return -1
+}
+
+// cut 在 start 与 end 处切分区间，返回完全位于 [start, end) 内的区间下标范围 [lo, hi)
+func (m *RangeMap[K, V]) cut(start, end K) (int, int) {
+	m.split(start)
+	m.split(end)
+	lo := sort.Search(len(m.entries), func(i int) bool {
+		return m.compare(m.entries[i].Start, start) >= 0
+	})
+	hi := sort.Search(len(m.entries), func(i int) bool {
+		return m.compare(m.entries[i].Start, end) >= 0
+	})
+	return lo, hi
+}
+
+// split 若 key 落在某个区间内部，将该区间在 key 处拆为两段
+func (m *RangeMap[K, V]) split(key K) {
+	i := m.find(key)
+	if i < 0 || m.compare(m.entries[i].Start, key) == 0 {
+		return
+	}
+	right := m.entries[i]
+	right.Start = key
+	m.entries[i].End = key
+	m.replace(i+1, i+1, right)
+}
+
+// replace 用 entry 替换下标范围 [lo, hi) 内的区间
+func (m *RangeMap[K, V]) replace(lo, hi int, entry RangeEntry[K, V]) {
+	if lo == hi {
+		m.entries = append(m.entries, RangeEntry[K, V]{})
+		copy(m.entries[lo+1:], m.entries[lo:])
+		m.entries[lo] = entry
+		return
+	}
+	m.entries[lo] = entry
+	m.entries = append(m.entries[:lo+1], m.entries[hi:]...)
+}
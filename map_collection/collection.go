@@ -60,9 +60,11 @@ func (c *Collection[K, V]) Has(key K) bool {
 }
 
 // Set 设置 key->val（直接修改当前 Collection，返回自身以支持链式调用）
+// 总是写入，不检查唯一索引；需要保证唯一约束时使用 TrySet
 func (c *Collection[K, V]) Set(key K, val V) *Collection[K, V] {
-	_, exists := c.value[key]
+	oldVal, exists := c.value[key]
 	c.value[key] = val
+	c.indexWrite(key, oldVal, exists, val)
 
 	// 如果是新增的 key，插入到 sortedKeys
 	if !exists {
@@ -120,14 +122,18 @@ func (c *Collection[K, V]) DeleteByFunc(fn func(K, V) bool) *Collection[K, V] {
 
 // Remove 删除指定的 key（直接修改当前 Collection，返回自身以支持链式调用）
 func (c *Collection[K, V]) Remove(key K) *Collection[K, V] {
+	oldVal, exists := c.value[key]
 	delete(c.value, key)
 	c.removeKeyFromSorted(key)
+	if exists {
+		c.indexRemove(key, oldVal)
+	}
 
 	return c
 }
 
 // Merge 合并另一个 map 到当前 Collection（不修改原 Collection，返回新的 Collection）
-// 键冲突时，以 other 为准
+// 键冲突时，以 other 为准；不检查唯一索引，需要保证唯一约束时使用 TryMerge
 func (c *Collection[K, V]) Merge(other map[K]V) *Collection[K, V] {
 	newMap := Merge(c.value, other)
 	newColl := c.cloneWithSortedKeys(newMap)
@@ -164,6 +170,7 @@ func (c *Collection[K, V]) MergeCollection(other *Collection[K, V]) *Collection[
 }
 
 // MergeInPlace 将另一个 map 合并到当前 Collection（直接修改当前 Collection，返回自身以支持链式调用）
+// 不检查唯一索引，需要保证唯一约束时使用 TryMergeInPlace
 func (c *Collection[K, V]) MergeInPlace(other map[K]V) *Collection[K, V] {
 	// 记录新增的 keys
 	newKeys := make([]K, 0)
@@ -173,7 +180,15 @@ func (c *Collection[K, V]) MergeInPlace(other map[K]V) *Collection[K, V] {
 		}
 	}
 
-	MergeInPlace(c.value, other)
+	if len(c.indexes) > 0 {
+		for k, v := range other {
+			oldVal, exists := c.value[k]
+			c.value[k] = v
+			c.indexWrite(k, oldVal, exists, v)
+		}
+	} else {
+		MergeInPlace(c.value, other)
+	}
 
 	// 增量更新 sortedKeys
 	if c.sortedKeys != nil {
@@ -241,14 +256,12 @@ func (c *Collection[K, V]) Foreach(fn func(value V, key K)) *Collection[K, V] {
 	return c
 }
 
-// TODO 后续看看怎么，现在没办法返回新的 Collection
-// // Map 对 value 进行映射转换，返回新的 Collection
-// // 注意：此方法会改变 value 的类型，因此返回的是 map[K]R 而不是 Collection
-// func (c *Collection[K, V]) Map(fn func(value V, key K) V) *Collection[K, V] {
-// 	newMap := MapValues(c.value, fn)
-//
-// 	return NewCollection(newMap)
-// }
+// Map 对 value 进行映射转换（不改变 value 类型），返回新的 Collection
+func (c *Collection[K, V]) Map(fn func(value V, key K) V) *Collection[K, V] {
+	newMap := MapValues(c.value, fn)
+
+	return c.cloneWithSortedKeys(newMap)
+}
 
 // Reduce 聚合：将 map 折叠为一个结果
 func (c *Collection[K, V]) Reduce(init any, fn func(acc any, value V, key K) any) any {
@@ -0,0 +1,65 @@
+package map_collection
+
+import (
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// Pair 键值对，用于在 map Collection 与 slice Collection 之间转换
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// KeyBy 以 keyFn 的结果为 key，将 slice Collection 转换为 map Collection
+// key 的顺序为首次出现的顺序；key 重复时后出现的元素覆盖先出现的元素
+func KeyBy[T any, K comparable](c *slice_collcection.Collection[T], keyFn func(item T) K) *Collection[K, T] {
+	return ToMap(c, keyFn, func(item T) T { return item }, nil)
+}
+
+// ToMap 以 keyFn、valFn 的结果为键值，将 slice Collection 转换为 map Collection
+// key 的顺序为首次出现的顺序
+// onCollision: key 重复时的处理函数，接收已有值与新值并返回最终值；为 nil 时新值覆盖已有值
+func ToMap[T any, K comparable, V any](c *slice_collcection.Collection[T], keyFn func(item T) K, valFn func(item T) V,
+	onCollision func(key K, existing, incoming V) V) *Collection[K, V] {
+	var items []T
+	if c != nil {
+		items = c.Values()
+	}
+
+	values := make(map[K]V, len(items))
+	order := make([]K, 0, len(items))
+	for _, item := range items {
+		k := keyFn(item)
+		v := valFn(item)
+		if existing, ok := values[k]; ok {
+			if onCollision != nil {
+				v = onCollision(k, existing, v)
+			}
+		} else {
+			order = append(order, k)
+		}
+		values[k] = v
+	}
+
+	coll := NewCollection(values)
+	coll.sortedKeys = order
+
+	return coll
+}
+
+// FromEntries 将键值对组成的 slice Collection 转换为 map Collection
+// key 的顺序为首次出现的顺序；key 重复时后出现的值覆盖先出现的值
+func FromEntries[K comparable, V any](c *slice_collcection.Collection[Pair[K, V]]) *Collection[K, V] {
+	return ToMap(c, func(p Pair[K, V]) K { return p.Key }, func(p Pair[K, V]) V { return p.Value }, nil)
+}
+
+// Entries 返回按 sortedKeys 顺序排列的键值对 slice Collection
+func (c *Collection[K, V]) Entries() *slice_collcection.Collection[Pair[K, V]] {
+	keys := c.orderedKeys()
+	entries := make([]Pair[K, V], 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, Pair[K, V]{Key: k, Value: c.value[k]})
+	}
+
+	return slice_collcection.NewCollection(entries)
+}
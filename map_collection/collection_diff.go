@@ -0,0 +1,106 @@
+package map_collection
+
+import (
+	"reflect"
+	"slices"
+)
+
+// DiffEntry 新增或删除的键值对
+type DiffEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// DiffChange 值发生变化的键值对
+type DiffChange[K comparable, V any] struct {
+	Key K
+	Old V
+	New V
+}
+
+// MapDiff 两个 Collection 之间的差异
+// Added 按 b 的 key 顺序排列，Removed、Changed 按 a 的 key 顺序排列（没有 key 比较函数时为 map 的遍历顺序）
+type MapDiff[K comparable, V any] struct {
+	Added   []DiffEntry[K, V]
+	Removed []DiffEntry[K, V]
+	Changed []DiffChange[K, V]
+}
+
+// IsEmpty 判断是否没有任何差异
+func (d *MapDiff[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff 计算从 a 变为 b 的差异
+// eq: 判断两个值是否相等，为 nil 时使用 reflect.DeepEqual
+func Diff[K comparable, V any](a, b *Collection[K, V], eq func(V, V) bool) *MapDiff[K, V] {
+	if eq == nil {
+		eq = func(x, y V) bool {
+			return reflect.DeepEqual(x, y)
+		}
+	}
+	if a == nil {
+		a = &Collection[K, V]{value: map[K]V{}}
+	}
+	if b == nil {
+		b = &Collection[K, V]{value: map[K]V{}}
+	}
+
+	diff := &MapDiff[K, V]{}
+	for _, k := range a.orderedKeys() {
+		oldVal := a.value[k]
+		newVal, ok := b.value[k]
+		if !ok {
+			diff.Removed = append(diff.Removed, DiffEntry[K, V]{Key: k, Value: oldVal})
+			continue
+		}
+		if !eq(oldVal, newVal) {
+			diff.Changed = append(diff.Changed, DiffChange[K, V]{Key: k, Old: oldVal, New: newVal})
+		}
+	}
+	for _, k := range b.orderedKeys() {
+		if _, ok := a.value[k]; !ok {
+			diff.Added = append(diff.Added, DiffEntry[K, V]{Key: k, Value: b.value[k]})
+		}
+	}
+
+	return diff
+}
+
+// Apply 将差异重放到当前 Collection 上（不修改原 Collection，返回新的 Collection）
+func (c *Collection[K, V]) Apply(diff *MapDiff[K, V]) *Collection[K, V] {
+	return c.Copy().ApplyInPlace(diff)
+}
+
+// ApplyInPlace 将差异重放到当前 Collection 上（直接修改当前 Collection，返回自身以支持链式调用）
+// Removed 中的 key 被删除，Changed 与 Added 中的 key 被设置为新值
+func (c *Collection[K, V]) ApplyInPlace(diff *MapDiff[K, V]) *Collection[K, V] {
+	if diff == nil {
+		return c
+	}
+
+	for _, entry := range diff.Removed {
+		c.Remove(entry.Key)
+	}
+	for _, change := range diff.Changed {
+		c.Set(change.Key, change.New)
+	}
+	for _, entry := range diff.Added {
+		c.Set(entry.Key, entry.Value)
+	}
+
+	return c
+}
+
+// orderedKeys 返回有序的 key 列表，不修改 Collection 本身
+// sortedKeys 已初始化时直接使用；否则有 key 比较函数时返回排序后的副本，没有时为 map 的遍历顺序
+func (c *Collection[K, V]) orderedKeys() []K {
+	if c.sortedKeys != nil {
+		return c.sortedKeys
+	}
+	keys := Keys(c.value)
+	if c.keyCompareFunc != nil {
+		slices.SortFunc(keys, c.keyCompareFunc)
+	}
+	return keys
+}
@@ -68,6 +68,8 @@ func (c *Collection[K, V]) cloneWithSortedKeys(newMap map[K]V) *Collection[K, V]
 		kType:          c.kType,
 		valCompareFunc: c.valCompareFunc,
 		keyCompareFunc: c.keyCompareFunc,
+		indexes:        c.cloneIndexes(),
+		indexOrder:     c.indexOrder,
 	}
 
 	if c.sortedKeys != nil {
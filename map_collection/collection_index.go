@@ -0,0 +1,413 @@
+package map_collection
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// valueIndex 基于 value 提取值的二级索引
+type valueIndex[K comparable, V any] struct {
+	extractor func(V) any // 从 value 中提取索引值，返回值需要可比较（可作为 map 的 key）
+	unique    bool        // 是否为唯一索引
+
+	buckets map[any][]K // 索引值 -> keys
+	built   bool        // 为 false 时需要根据当前数据重建
+	ordered []any       // 排序后的索引值，用于范围查询，为 nil 时按需重建
+}
+
+// CreateIndex 创建二级索引
+// extractor 从 value 中提取索引值，提取结果需要可作为 map 的 key；
+// unique 为 true 时，同一个索引值只允许对应一个 key。
+// 创建后 Set/Remove/Merge/MergeInPlace 等写入会自动维护索引；
+// 唯一约束只由 TrySet/TryMerge/TryMergeInPlace 检查，Set/Put/Merge 等链式写入总是生效，写入重复值后 LookupIndex 会返回所有对应的元素
+func (c *Collection[K, V]) CreateIndex(name string, extractor func(V) any, unique bool) error {
+	if extractor == nil {
+		return errorx.NilFunc
+	}
+	if _, exists := c.indexes[name]; exists {
+		return fmt.Errorf("%w: %s", errorx.IndexExistsError, name)
+	}
+
+	idx := &valueIndex[K, V]{extractor: extractor, unique: unique}
+	if err := c.buildIndex(name, idx); err != nil {
+		return err
+	}
+
+	if c.indexes == nil {
+		c.indexes = make(map[string]*valueIndex[K, V])
+	}
+	c.indexes[name] = idx
+	// 重新分配而不是原地插入，副本共享的 indexOrder 不受影响
+	order := make([]string, 0, len(c.indexOrder)+1)
+	order = append(order, c.indexOrder...)
+	c.indexOrder = append(order, name)
+	sort.Strings(c.indexOrder)
+
+	return nil
+}
+
+// DropIndex 删除二级索引
+func (c *Collection[K, V]) DropIndex(name string) error {
+	if _, exists := c.indexes[name]; !exists {
+		return fmt.Errorf("%w: %s", errorx.IndexNotFoundError, name)
+	}
+	delete(c.indexes, name)
+	order := make([]string, 0, len(c.indexOrder))
+	for _, n := range c.indexOrder {
+		if n != name {
+			order = append(order, n)
+		}
+	}
+	c.indexOrder = order
+
+	return nil
+}
+
+// LookupIndex 通过索引查找索引值等于 value 的元素，返回新的 Collection（保持原有的 key 顺序）
+// 注意：value 需要与 extractor 返回值的类型一致，例如 int64 与 int 不会相等
+func (c *Collection[K, V]) LookupIndex(name string, value any) (*Collection[K, V], error) {
+	idx, err := c.getIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	if !isHashable(value) {
+		return nil, errorx.InvalidTypeError
+	}
+
+	newMap := make(map[K]V)
+	for _, k := range idx.buckets[value] {
+		newMap[k] = c.value[k]
+	}
+	newColl := c.cloneWithSortedKeys(newMap)
+	newColl.sortedKeys = c.filterSortedKeys(newMap)
+
+	return newColl, nil
+}
+
+// IndexRange 通过索引查找索引值在 [lo, hi] 区间内的元素，返回按索引值排序的新 Collection
+// lo 或 hi 为 nil 时表示该侧不设边界，都为 nil 时返回所有可排序的索引值对应的元素；
+// 只有与边界同类（数值、字符串、bool、time.Time）的索引值参与比较，nil 或其它类型的索引值会被跳过；
+// 边界本身无法比较或 lo 与 hi 不同类时返回 errorx.NoComparableError
+func (c *Collection[K, V]) IndexRange(name string, lo, hi any) (*Collection[K, V], error) {
+	idx, err := c.getIndex(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := idx.orderedValues()
+	class := 0
+	for _, bound := range []any{lo, hi} {
+		if bound == nil {
+			continue
+		}
+		boundClass := orderClass(bound)
+		if boundClass == 0 || (class != 0 && boundClass != class) {
+			return nil, errorx.NoComparableError
+		}
+		class = boundClass
+	}
+	if class != 0 {
+		// 索引值先按类别排序，同类的值是连续的一段
+		first := sort.Search(len(ordered), func(i int) bool { return orderClass(ordered[i]) >= class })
+		last := sort.Search(len(ordered), func(i int) bool { return orderClass(ordered[i]) > class })
+		ordered = ordered[first:last]
+	}
+
+	start := 0
+	if lo != nil {
+		start = sort.Search(len(ordered), func(i int) bool {
+			cmp, _ := utils.CompareAny(ordered[i], lo)
+			return cmp >= 0
+		})
+	}
+	end := len(ordered)
+	if hi != nil {
+		end = sort.Search(len(ordered), func(i int) bool {
+			cmp, _ := utils.CompareAny(ordered[i], hi)
+			return cmp > 0
+		})
+	}
+
+	newMap := make(map[K]V)
+	keys := make([]K, 0)
+	for i := start; i < end; i++ {
+		for _, k := range idx.buckets[ordered[i]] {
+			newMap[k] = c.value[k]
+			keys = append(keys, k)
+		}
+	}
+	newColl := c.cloneWithSortedKeys(newMap)
+	newColl.sortedKeys = keys
+
+	return newColl, nil
+}
+
+// TrySet 设置 key->val（直接修改当前 Collection），违反唯一索引时返回 *errorx.UniqueViolation 且不做修改
+func (c *Collection[K, V]) TrySet(key K, val V) error {
+	if err := c.checkUniqueWrites(map[K]V{key: val}); err != nil {
+		return err
+	}
+	c.Set(key, val)
+
+	return nil
+}
+
+// TryMerge 合并另一个 map（不修改原 Collection，返回新的 Collection），违反唯一索引时返回 *errorx.UniqueViolation
+func (c *Collection[K, V]) TryMerge(other map[K]V) (*Collection[K, V], error) {
+	if err := c.checkUniqueWrites(other); err != nil {
+		return nil, err
+	}
+
+	return c.Merge(other), nil
+}
+
+// TryMergeInPlace 将另一个 map 合并到当前 Collection（直接修改当前 Collection），违反唯一索引时返回 *errorx.UniqueViolation 且不做修改
+func (c *Collection[K, V]) TryMergeInPlace(other map[K]V) error {
+	if err := c.checkUniqueWrites(other); err != nil {
+		return err
+	}
+	c.MergeInPlace(other)
+
+	return nil
+}
+
+// getIndex 获取已构建的索引
+func (c *Collection[K, V]) getIndex(name string) (*valueIndex[K, V], error) {
+	idx, exists := c.indexes[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errorx.IndexNotFoundError, name)
+	}
+	if !idx.built {
+		_ = c.buildIndex(name, idx)
+	}
+
+	return idx, nil
+}
+
+// buildIndex 根据当前数据（按 sortedKeys 顺序）重建索引，唯一索引存在重复值时返回错误
+func (c *Collection[K, V]) buildIndex(name string, idx *valueIndex[K, V]) error {
+	buckets := make(map[any][]K, len(c.value))
+	var violation error
+	for _, k := range c.orderedKeys() {
+		val := idx.extractor(c.value[k])
+		if !isHashable(val) {
+			continue
+		}
+		if idx.unique && len(buckets[val]) > 0 && violation == nil {
+			violation = &errorx.UniqueViolation{Index: name, Value: val, Key: k, ConflictKey: buckets[val][0]}
+		}
+		buckets[val] = append(buckets[val], k)
+	}
+
+	idx.buckets = buckets
+	idx.built = true
+	idx.ordered = nil
+
+	return violation
+}
+
+// validateIndexes 重建所有索引，唯一索引存在重复值时返回错误
+func (c *Collection[K, V]) validateIndexes() error {
+	for _, name := range c.indexOrder {
+		if err := c.buildIndex(name, c.indexes[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkUniqueWrites 检查将 updates 写入后是否违反唯一索引
+func (c *Collection[K, V]) checkUniqueWrites(updates map[K]V) error {
+	if len(c.indexes) == 0 || len(updates) == 0 {
+		return nil
+	}
+
+	for _, name := range c.indexOrder {
+		idx := c.indexes[name]
+		if !idx.unique {
+			continue
+		}
+		if !idx.built {
+			_ = c.buildIndex(name, idx)
+		}
+
+		claimed := make(map[any]K, len(updates))
+		for k, v := range updates {
+			val := idx.extractor(v)
+			if !isHashable(val) {
+				return fmt.Errorf("%w: index %s value %T is not comparable", errorx.InvalidTypeError, name, val)
+			}
+			if owner, ok := claimed[val]; ok && owner != k {
+				return &errorx.UniqueViolation{Index: name, Value: val, Key: k, ConflictKey: owner}
+			}
+			claimed[val] = k
+
+			for _, owner := range idx.buckets[val] {
+				if owner == k {
+					continue
+				}
+				// 同一批写入会覆盖该 key 的值，是否冲突由 claimed 判断
+				if _, overwritten := updates[owner]; overwritten {
+					continue
+				}
+				return &errorx.UniqueViolation{Index: name, Value: val, Key: k, ConflictKey: owner}
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexWrite 在 key 的值由 oldVal（exists 为 false 时表示原先不存在）写为 newVal 之后更新索引
+func (c *Collection[K, V]) indexWrite(key K, oldVal V, exists bool, newVal V) {
+	for name, idx := range c.indexes {
+		if !idx.built {
+			_ = c.buildIndex(name, idx)
+			continue
+		}
+		if exists {
+			idx.remove(key, idx.extractor(oldVal))
+		}
+		idx.add(key, idx.extractor(newVal))
+	}
+}
+
+// indexRemove 在 key 被删除之后更新索引
+func (c *Collection[K, V]) indexRemove(key K, oldVal V) {
+	for name, idx := range c.indexes {
+		if !idx.built {
+			_ = c.buildIndex(name, idx)
+			continue
+		}
+		idx.remove(key, idx.extractor(oldVal))
+	}
+}
+
+// cloneIndexes 复制索引定义，新 Collection 的索引在首次使用时按其数据重建
+func (c *Collection[K, V]) cloneIndexes() map[string]*valueIndex[K, V] {
+	if len(c.indexes) == 0 {
+		return nil
+	}
+
+	res := make(map[string]*valueIndex[K, V], len(c.indexes))
+	for name, idx := range c.indexes {
+		res[name] = &valueIndex[K, V]{extractor: idx.extractor, unique: idx.unique}
+	}
+
+	return res
+}
+
+// add 将 key 加入索引值 val 对应的桶
+func (idx *valueIndex[K, V]) add(key K, val any) {
+	if !isHashable(val) {
+		return
+	}
+	if len(idx.buckets[val]) == 0 {
+		idx.ordered = nil
+	}
+	idx.buckets[val] = append(idx.buckets[val], key)
+}
+
+// remove 将 key 从索引值 val 对应的桶中移除
+func (idx *valueIndex[K, V]) remove(key K, val any) {
+	if !isHashable(val) {
+		return
+	}
+	keys := idx.buckets[val]
+	for i, k := range keys {
+		if k == key {
+			keys = append(keys[:i:i], keys[i+1:]...)
+			break
+		}
+	}
+	if len(keys) == 0 {
+		delete(idx.buckets, val)
+		idx.ordered = nil
+		return
+	}
+	idx.buckets[val] = keys
+}
+
+// orderedValues 返回排序后的索引值：先按类别（见 orderClass），同类再按 utils.CompareAny 排序；无法排序的索引值不包含在内
+func (idx *valueIndex[K, V]) orderedValues() []any {
+	if idx.ordered != nil {
+		return idx.ordered
+	}
+
+	ordered := make([]any, 0, len(idx.buckets))
+	for val := range idx.buckets {
+		if orderClass(val) != 0 {
+			ordered = append(ordered, val)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		ci, cj := orderClass(ordered[i]), orderClass(ordered[j])
+		if ci != cj {
+			return ci < cj
+		}
+		cmp, _ := utils.CompareAny(ordered[i], ordered[j])
+		return cmp < 0
+	})
+	idx.ordered = ordered
+
+	return ordered
+}
+
+// orderClass 返回索引值的类别，同类的值之间可以通过 utils.CompareAny 比较；无法排序的值（包括 nil）返回 0
+func orderClass(v any) int {
+	if _, ok := v.(time.Time); ok {
+		return 4
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		return 0
+	case utils.IsComputableKind(rv.Kind()):
+		return 1
+	case rv.Kind() == reflect.String:
+		return 2
+	case rv.Kind() == reflect.Bool:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// isHashable 判断值是否可以作为 map 的 key
+// reflect.Type.Comparable 对包含接口字段的结构体、数组也返回 true，因此需要检查接口中实际保存的值
+func isHashable(v any) bool {
+	return v == nil || isHashableValue(reflect.ValueOf(v))
+}
+
+func isHashableValue(rv reflect.Value) bool {
+	if !rv.Type().Comparable() {
+		return false
+	}
+	switch rv.Kind() {
+	case reflect.Interface:
+		return rv.IsNil() || isHashableValue(rv.Elem())
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if !isHashableValue(rv.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Array:
+		// 元素为基础类型的数组不需要逐个检查
+		if kind := rv.Type().Elem().Kind(); kind != reflect.Interface && kind != reflect.Struct && kind != reflect.Array {
+			return true
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if !isHashableValue(rv.Index(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
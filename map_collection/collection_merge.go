@@ -0,0 +1,228 @@
+package map_collection
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// MergeResolver 合并时的键冲突解决函数
+// key: 冲突的 key，left: 当前 Collection 中的值，right: other 中的值
+// 返回合并后的值；返回 error 时整个合并操作中止，原 Collection 不会被修改
+type MergeResolver[K comparable, V any] func(key K, left, right V) (V, error)
+
+// MergeConflict 一次键冲突的记录
+type MergeConflict[K comparable, V any] struct {
+	Key    K // 冲突的 key
+	Left   V // 当前 Collection 中的值
+	Right  V // other 中的值
+	Result V // 解决后写入的值
+}
+
+// MergeReport 合并冲突报告，Conflicts 按合并结果的 key 顺序排列
+type MergeReport[K comparable, V any] struct {
+	Conflicts []MergeConflict[K, V]
+}
+
+// HasConflicts 是否出现过键冲突
+func (r *MergeReport[K, V]) HasConflicts() bool {
+	return len(r.Conflicts) != 0
+}
+
+// Keys 返回所有冲突的 key
+func (r *MergeReport[K, V]) Keys() []K {
+	keys := make([]K, 0, len(r.Conflicts))
+	for _, conflict := range r.Conflicts {
+		keys = append(keys, conflict.Key)
+	}
+	return keys
+}
+
+// MergeWith 使用 resolver 解决键冲突，合并另一个 map（不修改原 Collection，返回新的 Collection 与冲突报告）
+// 合并结果违反唯一索引时返回 *errorx.UniqueViolation
+func (c *Collection[K, V]) MergeWith(other map[K]V, resolver MergeResolver[K, V]) (*Collection[K, V], *MergeReport[K, V], error) {
+	resolved, conflicts, err := c.resolveConflicts(other, resolver)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newColl, err := c.TryMerge(resolved)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newColl, newColl.buildMergeReport(conflicts), nil
+}
+
+// MergeCollectionWith 使用 resolver 解决键冲突，合并另一个 Collection（不修改原 Collection，返回新的 Collection 与冲突报告）
+// 合并结果违反唯一索引时返回 *errorx.UniqueViolation
+func (c *Collection[K, V]) MergeCollectionWith(other *Collection[K, V], resolver MergeResolver[K, V]) (*Collection[K, V], *MergeReport[K, V], error) {
+	if other == nil {
+		if resolver == nil {
+			return nil, nil, errorx.NilFunc
+		}
+		return c.Copy(), &MergeReport[K, V]{}, nil
+	}
+
+	resolved, conflicts, err := c.resolveConflicts(other.value, resolver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.checkUniqueWrites(resolved); err != nil {
+		return nil, nil, err
+	}
+
+	// 沿用 other 的 key 顺序
+	newColl := c.MergeCollection(&Collection[K, V]{value: resolved, sortedKeys: other.sortedKeys})
+	return newColl, newColl.buildMergeReport(conflicts), nil
+}
+
+// MergeInPlaceWith 使用 resolver 解决键冲突，将另一个 map 合并到当前 Collection（直接修改当前 Collection）
+// resolver 返回 error 或合并结果违反唯一索引时，当前 Collection 保持不变
+func (c *Collection[K, V]) MergeInPlaceWith(other map[K]V, resolver MergeResolver[K, V]) (*Collection[K, V], *MergeReport[K, V], error) {
+	resolved, conflicts, err := c.resolveConflicts(other, resolver)
+	if err != nil {
+		return c, nil, err
+	}
+
+	if err := c.TryMergeInPlace(resolved); err != nil {
+		return c, nil, err
+	}
+	return c, c.buildMergeReport(conflicts), nil
+}
+
+// resolveConflicts 先对所有冲突的 key 调用 resolver，保证出错时不产生任何修改
+// 返回以解决后的值替换冲突 key 的待合并 map，以及冲突记录
+func (c *Collection[K, V]) resolveConflicts(other map[K]V, resolver MergeResolver[K, V]) (map[K]V, map[K]MergeConflict[K, V], error) {
+	if resolver == nil {
+		return nil, nil, errorx.NilFunc
+	}
+
+	resolved := make(map[K]V, len(other))
+	conflicts := make(map[K]MergeConflict[K, V])
+	for k, right := range other {
+		left, exists := c.value[k]
+		if !exists {
+			resolved[k] = right
+			continue
+		}
+		result, err := resolver(k, left, right)
+		if err != nil {
+			return nil, nil, fmt.Errorf("merge key %v: %w", k, err)
+		}
+		resolved[k] = result
+		conflicts[k] = MergeConflict[K, V]{Key: k, Left: left, Right: right, Result: result}
+	}
+
+	return resolved, conflicts, nil
+}
+
+// buildMergeReport 按 sortedKeys 的顺序生成冲突报告
+func (c *Collection[K, V]) buildMergeReport(conflicts map[K]MergeConflict[K, V]) *MergeReport[K, V] {
+	report := &MergeReport[K, V]{Conflicts: make([]MergeConflict[K, V], 0, len(conflicts))}
+	if len(conflicts) == 0 {
+		return report
+	}
+
+	for _, k := range c.orderedKeys() {
+		if conflict, ok := conflicts[k]; ok {
+			report.Conflicts = append(report.Conflicts, conflict)
+		}
+	}
+
+	return report
+}
+
+// KeepLeft 键冲突时保留当前 Collection 中的值
+func KeepLeft[K comparable, V any]() MergeResolver[K, V] {
+	return func(_ K, left, _ V) (V, error) {
+		return left, nil
+	}
+}
+
+// KeepRight 键冲突时以 other 为准（与 Merge 的默认行为一致）
+func KeepRight[K comparable, V any]() MergeResolver[K, V] {
+	return func(_ K, _, right V) (V, error) {
+		return right, nil
+	}
+}
+
+// RejectConflict 出现键冲突时返回 errorx.MergeConflictError，中止合并
+func RejectConflict[K comparable, V any]() MergeResolver[K, V] {
+	return func(_ K, left, _ V) (V, error) {
+		return left, errorx.MergeConflictError
+	}
+}
+
+// SumValues 键冲突时将两个数值相加
+func SumValues[K comparable, V utils.Number]() MergeResolver[K, V] {
+	return func(_ K, left, right V) (V, error) {
+		return left + right, nil
+	}
+}
+
+// ConcatValues 键冲突时拼接两个切片（left 在前，right 在后）
+func ConcatValues[K comparable, E any]() MergeResolver[K, []E] {
+	return func(_ K, left, right []E) ([]E, error) {
+		res := make([]E, 0, len(left)+len(right))
+		res = append(res, left...)
+		res = append(res, right...)
+		return res, nil
+	}
+}
+
+// DeepMerge 键冲突时递归合并嵌套的 map（如 map[string]any 形式的配置）
+// 两侧同一位置都是相同类型的 map 时逐 key 递归合并，否则以 right 为准；不会修改 left 与 right
+func DeepMerge[K comparable, V any]() MergeResolver[K, V] {
+	return func(_ K, left, right V) (V, error) {
+		merged := deepMergeValue(reflect.ValueOf(left), reflect.ValueOf(right))
+		if !merged.IsValid() {
+			var zero V
+			return zero, nil
+		}
+		res, ok := merged.Interface().(V)
+		if !ok {
+			return right, nil
+		}
+		return res, nil
+	}
+}
+
+// deepMergeValue 递归合并两个值，两者都为同类型 map 时合并，否则返回 right
+func deepMergeValue(left, right reflect.Value) reflect.Value {
+	for left.IsValid() && left.Kind() == reflect.Interface {
+		left = left.Elem()
+	}
+	for right.IsValid() && right.Kind() == reflect.Interface {
+		right = right.Elem()
+	}
+
+	if !left.IsValid() || !right.IsValid() ||
+		left.Kind() != reflect.Map || right.Kind() != reflect.Map ||
+		left.Type() != right.Type() {
+		return right
+	}
+
+	merged := reflect.MakeMapWithSize(left.Type(), left.Len()+right.Len())
+	iter := left.MapRange()
+	for iter.Next() {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	elemType := left.Type().Elem()
+	iter = right.MapRange()
+	for iter.Next() {
+		val := iter.Value()
+		if existing := left.MapIndex(iter.Key()); existing.IsValid() {
+			val = deepMergeValue(existing, val)
+			if !val.IsValid() {
+				val = reflect.Zero(elemType)
+			}
+		}
+		merged.SetMapIndex(iter.Key(), val)
+	}
+
+	return merged
+}
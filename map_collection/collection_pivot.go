@@ -0,0 +1,205 @@
+package map_collection
+
+import (
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// PivotTable 透视表（交叉表）
+// 行与列默认按首次出现的顺序排列，可以通过 SortRows/SortColumns 重新排序
+type PivotTable[R comparable, C comparable, V any] struct {
+	table        *Collection[R, *Collection[C, V]] // 行 -> (列 -> 聚合值)，只包含有数据的单元格
+	rowTotals    *Collection[R, V]
+	columnTotals *Collection[C, V]
+	grandTotal   V
+	columns      []C
+}
+
+// PivotCell 透视表的一个单元格，用于将透视表还原为长表
+type PivotCell[R comparable, C comparable, V any] struct {
+	Row    R `json:"row"`
+	Column C `json:"column"`
+	Value  V `json:"value"`
+}
+
+// Pivot 以 rowKey、colKey 对元素分组，并对每个单元格内的元素调用 aggregator 生成透视表
+// 行合计、列合计与总计同样通过对相应的全部元素调用 aggregator 得到，因此平均值等不可加的聚合也是正确的
+//
+// 使用示例：
+//
+//	table := map_collection.Pivot(orders,
+//	    func(o Order) string { return o.Month },
+//	    func(o Order) string { return o.Region },
+//	    map_collection.AggSum(func(o Order) float64 { return o.Amount }))
+//	table.Cell("2024-01", "east")
+func Pivot[T any, R comparable, C comparable, V any](c *slice_collcection.Collection[T], rowKey func(item T) R, colKey func(item T) C, aggregator func(items []T) V) *PivotTable[R, C, V] {
+	var items []T
+	if c != nil {
+		items = c.Values()
+	}
+
+	rowOrder := make([]R, 0)
+	columnOrder := make([]C, 0)
+	rowItems := make(map[R][]T)
+	columnItems := make(map[C][]T)
+	cellItems := make(map[R]map[C][]T)
+	for _, item := range items {
+		r := rowKey(item)
+		col := colKey(item)
+		if _, ok := rowItems[r]; !ok {
+			rowOrder = append(rowOrder, r)
+			cellItems[r] = make(map[C][]T)
+		}
+		if _, ok := columnItems[col]; !ok {
+			columnOrder = append(columnOrder, col)
+		}
+		rowItems[r] = append(rowItems[r], item)
+		columnItems[col] = append(columnItems[col], item)
+		cellItems[r][col] = append(cellItems[r][col], item)
+	}
+
+	rows := make(map[R]*Collection[C, V], len(rowOrder))
+	rowTotals := make(map[R]V, len(rowOrder))
+	for _, r := range rowOrder {
+		cells := make(map[C]V, len(cellItems[r]))
+		keys := make([]C, 0, len(cellItems[r]))
+		for _, col := range columnOrder {
+			if group, ok := cellItems[r][col]; ok {
+				cells[col] = aggregator(group)
+				keys = append(keys, col)
+			}
+		}
+		row := NewCollection(cells)
+		row.sortedKeys = keys
+		rows[r] = row
+		rowTotals[r] = aggregator(rowItems[r])
+	}
+	columnTotals := make(map[C]V, len(columnOrder))
+	for _, col := range columnOrder {
+		columnTotals[col] = aggregator(columnItems[col])
+	}
+
+	p := &PivotTable[R, C, V]{
+		table:        NewCollection(rows),
+		rowTotals:    NewCollection(rowTotals),
+		columnTotals: NewCollection(columnTotals),
+		grandTotal:   aggregator(items),
+		columns:      columnOrder,
+	}
+	p.table.sortedKeys = rowOrder
+	p.rowTotals.sortedKeys = append([]R(nil), rowOrder...)
+	p.columnTotals.sortedKeys = append([]C(nil), columnOrder...)
+
+	return p
+}
+
+// Table 返回嵌套的 map Collection：行 -> (列 -> 聚合值)，只包含有数据的单元格
+func (p *PivotTable[R, C, V]) Table() *Collection[R, *Collection[C, V]] {
+	return p.table
+}
+
+// RowTotals 返回每一行的合计
+func (p *PivotTable[R, C, V]) RowTotals() *Collection[R, V] {
+	return p.rowTotals
+}
+
+// ColumnTotals 返回每一列的合计
+func (p *PivotTable[R, C, V]) ColumnTotals() *Collection[C, V] {
+	return p.columnTotals
+}
+
+// GrandTotal 返回所有元素的合计
+func (p *PivotTable[R, C, V]) GrandTotal() V {
+	return p.grandTotal
+}
+
+// Rows 返回有序的行
+func (p *PivotTable[R, C, V]) Rows() []R {
+	return append([]R(nil), p.table.orderedKeys()...)
+}
+
+// Columns 返回有序的列
+func (p *PivotTable[R, C, V]) Columns() []C {
+	return append([]C(nil), p.columns...)
+}
+
+// Cell 返回单元格的聚合值，单元格没有数据时返回 false
+func (p *PivotTable[R, C, V]) Cell(row R, column C) (V, bool) {
+	cells, ok := p.table.Get(row)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return cells.Get(column)
+}
+
+// SortRows 按 compareFunc 对行排序（直接修改当前透视表）
+func (p *PivotTable[R, C, V]) SortRows(compareFunc func(a, b R) int) (*PivotTable[R, C, V], error) {
+	if compareFunc == nil {
+		return p, errorx.NilFunc
+	}
+	_, _ = p.table.OrderKeyByFunc(compareFunc)
+	_, _ = p.rowTotals.OrderKeyByFunc(compareFunc)
+
+	return p, nil
+}
+
+// SortColumns 按 compareFunc 对列排序（直接修改当前透视表）
+func (p *PivotTable[R, C, V]) SortColumns(compareFunc func(a, b C) int) (*PivotTable[R, C, V], error) {
+	if compareFunc == nil {
+		return p, errorx.NilFunc
+	}
+	p.table.Foreach(func(cells *Collection[C, V], _ R) {
+		_, _ = cells.OrderKeyByFunc(compareFunc)
+	})
+	_, _ = p.columnTotals.OrderKeyByFunc(compareFunc)
+	p.columns = append(p.columns[:0:0], p.columnTotals.sortedKeys...)
+
+	return p, nil
+}
+
+// Unpivot 将透视表还原为长表，按行、列的顺序输出有数据的单元格
+func (p *PivotTable[R, C, V]) Unpivot() *slice_collcection.Collection[PivotCell[R, C, V]] {
+	cells := make([]PivotCell[R, C, V], 0)
+	p.table.Foreach(func(row *Collection[C, V], r R) {
+		row.Foreach(func(v V, col C) {
+			cells = append(cells, PivotCell[R, C, V]{Row: r, Column: col, Value: v})
+		})
+	})
+
+	return slice_collcection.NewCollection(cells)
+}
+
+// AggSum 求和聚合，配合 Pivot 使用
+func AggSum[T any, N utils.Number](valueFn func(item T) N) func(items []T) N {
+	return func(items []T) N {
+		var sum N
+		for _, item := range items {
+			sum += valueFn(item)
+		}
+		return sum
+	}
+}
+
+// AggCount 计数聚合，配合 Pivot 使用
+func AggCount[T any]() func(items []T) int {
+	return func(items []T) int {
+		return len(items)
+	}
+}
+
+// AggAvg 平均值聚合，没有元素时返回 0，配合 Pivot 使用
+func AggAvg[T any, N utils.Number](valueFn func(item T) N) func(items []T) float64 {
+	return func(items []T) float64 {
+		if len(items) == 0 {
+			return 0
+		}
+		var sum float64
+		for _, item := range items {
+			sum += float64(valueFn(item))
+		}
+		return sum / float64(len(items))
+	}
+}
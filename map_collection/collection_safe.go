@@ -1,5 +1,9 @@
 package map_collection
 
+import (
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
 // Copy 返回一个新的线程安全的 Collection 副本
 func (sc *SafeCollection[K, V]) Copy() *SafeCollection[K, V] {
 	sc.mu.RLock()
@@ -92,10 +96,11 @@ func (sc *SafeCollection[K, V]) Set(key K, val V) *SafeCollection[K, V] {
 }
 
 // Put 设置 key->val（返回新的线程安全 Collection）
+// 唯一约束检查可能会重建索引，因此与其它写入一样持有写锁
 func (sc *SafeCollection[K, V]) Put(key K, val V) *SafeCollection[K, V] {
-	sc.mu.RLock()
+	sc.mu.Lock()
 	newColl := sc.coll.Put(key, val)
-	sc.mu.RUnlock()
+	sc.mu.Unlock()
 
 	return &SafeCollection[K, V]{
 		coll: newColl,
@@ -134,10 +139,11 @@ func (sc *SafeCollection[K, V]) Remove(key K) *SafeCollection[K, V] {
 }
 
 // Merge 合并另一个 map（返回新的线程安全 Collection）
+// 唯一约束检查可能会重建索引，因此持有写锁
 func (sc *SafeCollection[K, V]) Merge(other map[K]V) *SafeCollection[K, V] {
-	sc.mu.RLock()
+	sc.mu.Lock()
 	newColl := sc.coll.Merge(other)
-	sc.mu.RUnlock()
+	sc.mu.Unlock()
 
 	return &SafeCollection[K, V]{
 		coll: newColl,
@@ -145,22 +151,30 @@ func (sc *SafeCollection[K, V]) Merge(other map[K]V) *SafeCollection[K, V] {
 }
 
 // MergeCollection 合并另一个 SafeCollection（返回新的线程安全 Collection）
+// 唯一约束检查可能会重建当前 Collection 的索引，因此对当前 Collection 持有写锁
 func (sc *SafeCollection[K, V]) MergeCollection(other *SafeCollection[K, V]) *SafeCollection[K, V] {
 	if other == nil {
 		return sc.Copy()
 	}
 
-	sc.mu.RLock()
-	other.mu.RLock()
-	newColl := sc.coll.MergeCollection(other.coll)
-	other.mu.RUnlock()
-	sc.mu.RUnlock()
+	otherColl := other.snapshot()
+	sc.mu.Lock()
+	newColl := sc.coll.MergeCollection(otherColl)
+	sc.mu.Unlock()
 
 	return &SafeCollection[K, V]{
 		coll: newColl,
 	}
 }
 
+// snapshot 在读锁下复制底层 Collection，合并两个 SafeCollection 时不会同时持有两把锁（避免互相合并或与自身合并时死锁）
+func (sc *SafeCollection[K, V]) snapshot() *Collection[K, V] {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return sc.coll.Copy()
+}
+
 // MergeInPlace 将另一个 map 合并到当前 Collection（直接修改）
 func (sc *SafeCollection[K, V]) MergeInPlace(other map[K]V) *SafeCollection[K, V] {
 	sc.mu.Lock()
@@ -170,6 +184,128 @@ func (sc *SafeCollection[K, V]) MergeInPlace(other map[K]V) *SafeCollection[K, V
 	return sc
 }
 
+// MergeWith 使用 resolver 解决键冲突合并另一个 map（返回新的线程安全 Collection 与冲突报告）
+// 唯一约束检查可能会重建索引，因此持有写锁
+func (sc *SafeCollection[K, V]) MergeWith(other map[K]V, resolver MergeResolver[K, V]) (*SafeCollection[K, V], *MergeReport[K, V], error) {
+	sc.mu.Lock()
+	newColl, report, err := sc.coll.MergeWith(other, resolver)
+	sc.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &SafeCollection[K, V]{
+		coll: newColl,
+	}, report, nil
+}
+
+// MergeCollectionWith 使用 resolver 解决键冲突合并另一个 SafeCollection（返回新的线程安全 Collection 与冲突报告）
+// 唯一约束检查可能会重建当前 Collection 的索引，因此对当前 Collection 持有写锁
+func (sc *SafeCollection[K, V]) MergeCollectionWith(other *SafeCollection[K, V], resolver MergeResolver[K, V]) (*SafeCollection[K, V], *MergeReport[K, V], error) {
+	var otherColl *Collection[K, V]
+	if other != nil {
+		otherColl = other.snapshot()
+	}
+	sc.mu.Lock()
+	newColl, report, err := sc.coll.MergeCollectionWith(otherColl, resolver)
+	sc.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &SafeCollection[K, V]{
+		coll: newColl,
+	}, report, nil
+}
+
+// MergeInPlaceWith 使用 resolver 解决键冲突将另一个 map 合并到当前 Collection（直接修改）
+func (sc *SafeCollection[K, V]) MergeInPlaceWith(other map[K]V, resolver MergeResolver[K, V]) (*SafeCollection[K, V], *MergeReport[K, V], error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	_, report, err := sc.coll.MergeInPlaceWith(other, resolver)
+	return sc, report, err
+}
+
+// CreateIndex 创建二级索引
+func (sc *SafeCollection[K, V]) CreateIndex(name string, extractor func(V) any, unique bool) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return sc.coll.CreateIndex(name, extractor, unique)
+}
+
+// DropIndex 删除二级索引
+func (sc *SafeCollection[K, V]) DropIndex(name string) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return sc.coll.DropIndex(name)
+}
+
+// LookupIndex 通过索引查找（返回新的线程安全 Collection）
+// 索引可能需要按需重建，因此使用写锁
+func (sc *SafeCollection[K, V]) LookupIndex(name string, value any) (*SafeCollection[K, V], error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	newColl, err := sc.coll.LookupIndex(name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SafeCollection[K, V]{
+		coll: newColl,
+	}, nil
+}
+
+// IndexRange 通过索引进行范围查找（返回新的线程安全 Collection）
+// 索引可能需要按需重建，因此使用写锁
+func (sc *SafeCollection[K, V]) IndexRange(name string, lo, hi any) (*SafeCollection[K, V], error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	newColl, err := sc.coll.IndexRange(name, lo, hi)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SafeCollection[K, V]{
+		coll: newColl,
+	}, nil
+}
+
+// TrySet 设置 key->val（直接修改），违反唯一索引时返回错误
+func (sc *SafeCollection[K, V]) TrySet(key K, val V) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return sc.coll.TrySet(key, val)
+}
+
+// TryMerge 合并另一个 map（返回新的线程安全 Collection），违反唯一索引时返回错误
+func (sc *SafeCollection[K, V]) TryMerge(other map[K]V) (*SafeCollection[K, V], error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	newColl, err := sc.coll.TryMerge(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SafeCollection[K, V]{
+		coll: newColl,
+	}, nil
+}
+
+// TryMergeInPlace 将另一个 map 合并到当前 Collection（直接修改），违反唯一索引时返回错误
+func (sc *SafeCollection[K, V]) TryMergeInPlace(other map[K]V) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return sc.coll.TryMergeInPlace(other)
+}
+
 // Filter 过滤元素（返回新的线程安全 Collection）
 func (sc *SafeCollection[K, V]) Filter(fn func(V, K) bool) *SafeCollection[K, V] {
 	sc.mu.RLock()
@@ -225,6 +361,14 @@ func (sc *SafeCollection[K, V]) Last() (K, V, bool) {
 	return sc.coll.Last()
 }
 
+// Entries 返回按 sortedKeys 顺序排列的键值对 slice Collection
+func (sc *SafeCollection[K, V]) Entries() *slice_collcection.Collection[Pair[K, V]] {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return sc.coll.Entries()
+}
+
 // All 返回底层 map 的副本
 func (sc *SafeCollection[K, V]) All() map[K]V {
 	sc.mu.RLock()
@@ -0,0 +1,343 @@
+package map_collection
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// timeUnit 时间分桶的单位
+type timeUnit int
+
+const (
+	unitMinute timeUnit = iota
+	unitHour
+	unitDay
+	unitISOWeek
+	unitMonth
+)
+
+// TimeInterval 时间分桶的间隔
+type TimeInterval struct {
+	unit timeUnit
+	step int
+}
+
+var (
+	Minute  = TimeInterval{unit: unitMinute, step: 1}  // 按分钟分桶
+	Hour    = TimeInterval{unit: unitHour, step: 1}    // 按小时分桶
+	Day     = TimeInterval{unit: unitDay, step: 1}     // 按天分桶
+	ISOWeek = TimeInterval{unit: unitISOWeek, step: 1} // 按 ISO 周分桶（周一为一周的开始）
+	Month   = TimeInterval{unit: unitMonth, step: 1}   // 按自然月分桶
+)
+
+// Every 返回 n 倍的间隔，例如 Minute.Every(15) 表示 15 分钟
+// 分钟、小时按距当天 0 点的实际时长对齐（不能整除一天时，当天最后一个桶会较短），天、周、月以 1970 年为起点按日历对齐
+func (i TimeInterval) Every(n int) TimeInterval {
+	if n <= 0 {
+		n = 1
+	}
+	return TimeInterval{unit: i.unit, step: n}
+}
+
+// Truncate 返回 t 在 loc 时区下所在桶的起始时间，loc 为 nil 时使用 UTC
+// 分钟、小时按绝对时间截断，夏令时回拨当天重复的两个小时会落入不同的桶
+func (i TimeInterval) Truncate(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	step := max(i.step, 1)
+	year, month, day := t.Date()
+
+	switch i.unit {
+	case unitMinute, unitHour:
+		midnight := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		size := time.Duration(step) * time.Minute
+		if i.unit == unitHour {
+			size = time.Duration(step) * time.Hour
+		}
+		return midnight.Add(t.Sub(midnight) / size * size)
+	case unitDay:
+		days := floorDiv(civilDays(year, month, day), step) * step
+		return civilDate(days, loc)
+	case unitISOWeek:
+		// 1970-01-05 是周一，以它为起点按周对齐
+		weeks := floorDiv(civilDays(year, month, day)-4, 7*step) * step
+		return civilDate(weeks*7+4, loc)
+	default:
+		months := floorDiv(year*12+int(month)-1, step) * step
+		return time.Date(floorDiv(months, 12), time.Month(months-floorDiv(months, 12)*12+1), 1, 0, 0, 0, 0, loc)
+	}
+}
+
+// Next 返回 bucket 之后下一个桶的起始时间，bucket 需要是 Truncate 的结果
+func (i TimeInterval) Next(bucket time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	bucket = bucket.In(loc)
+	step := max(i.step, 1)
+	year, month, day := bucket.Date()
+
+	var next time.Time
+	switch i.unit {
+	case unitMinute:
+		next = bucket.Add(time.Duration(step) * time.Minute)
+	case unitHour:
+		next = bucket.Add(time.Duration(step) * time.Hour)
+	case unitDay:
+		next = time.Date(year, month, day+step, 0, 0, 0, 0, loc)
+	case unitISOWeek:
+		next = time.Date(year, month, day+7*step, 0, 0, 0, 0, loc)
+	default:
+		next = time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, loc)
+	}
+
+	// 分钟、小时跨天时对齐到次日第一个桶
+	return i.Truncate(next, loc)
+}
+
+// MaxTimeBuckets 补齐空桶后最多允许的桶数量
+const MaxTimeBuckets = 1 << 20
+
+// TimeBucketOption 时间分桶选项
+type TimeBucketOption func(*timeBucketOptions)
+
+// timeBucketOptions 时间分桶选项
+type timeBucketOptions struct {
+	fillGaps bool
+	from, to time.Time // 不为零值时固定分桶的范围（包含两端所在的桶）
+}
+
+// WithFillGaps 在第一个与最后一个桶之间补齐没有数据的桶
+func WithFillGaps() TimeBucketOption {
+	return func(o *timeBucketOptions) {
+		o.fillGaps = true
+	}
+}
+
+// WithTimeRange 固定分桶的范围并补齐其中没有数据的桶，范围外的元素会被忽略，常用于图表的固定坐标轴
+func WithTimeRange(from, to time.Time) TimeBucketOption {
+	return func(o *timeBucketOptions) {
+		o.fillGaps = true
+		o.from = from
+		o.to = to
+	}
+}
+
+// GroupByTime 按 timeFn 返回的时间在 loc 时区下分桶（loc 为 nil 时使用 UTC）
+// 返回以桶起始时间为 key、按时间升序排列的 Collection，每个桶内的元素保持原有顺序；
+// 使用 WithFillGaps/WithTimeRange 时没有数据的桶对应空的 slice Collection，补齐后的桶超过 MaxTimeBuckets 个时返回 errorx.TooLargeError
+//
+// 使用示例：
+//
+//	hourly, err := map_collection.GroupByTime(events, func(e Event) time.Time { return e.At },
+//	    map_collection.Hour, time.Local, map_collection.WithFillGaps())
+func GroupByTime[T any](c *slice_collcection.Collection[T], timeFn func(item T) time.Time, interval TimeInterval, loc *time.Location, opts ...TimeBucketOption) (*Collection[time.Time, *slice_collcection.Collection[T]], error) {
+	buckets, keys, err := bucketByTime(c, timeFn, interval, loc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[time.Time]*slice_collcection.Collection[T], len(keys))
+	for _, k := range keys {
+		groups[k] = slice_collcection.NewCollection(buckets[k])
+	}
+
+	return newTimeCollection(groups, keys), nil
+}
+
+// Resample 按时间分桶并对每个桶内的元素调用 aggregator
+// 补齐的空桶会以 nil 调用 aggregator，AggSum/AggCount/AggAvg 此时返回 0；补齐后的桶超过 MaxTimeBuckets 个时返回 errorx.TooLargeError
+//
+// 使用示例：
+//
+//	daily, err := map_collection.Resample(orders, func(o Order) time.Time { return o.PaidAt },
+//	    map_collection.Day, loc, map_collection.AggSum(func(o Order) float64 { return o.Amount }))
+func Resample[T any, V any](c *slice_collcection.Collection[T], timeFn func(item T) time.Time, interval TimeInterval, loc *time.Location, aggregator func(items []T) V, opts ...TimeBucketOption) (*Collection[time.Time, V], error) {
+	buckets, keys, err := bucketByTime(c, timeFn, interval, loc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[time.Time]V, len(keys))
+	for _, k := range keys {
+		values[k] = aggregator(buckets[k])
+	}
+
+	return newTimeCollection(values, keys), nil
+}
+
+// Downsample 使用 LTTB（Largest-Triangle-Three-Buckets）算法将时间序列降采样为最多 threshold 个点
+// 保留首尾两点，并在每个区间内选择与相邻点构成三角形面积最大的点，能较好地保留曲线形状，适合图表展示。
+// 元素数量不超过 threshold 或 threshold 小于 3 时返回全部的点；时间相同的点后出现的覆盖先出现的。
+func Downsample[T any, N utils.Number](c *slice_collcection.Collection[T], timeFn func(item T) time.Time, valueFn func(item T) N, threshold int) *Collection[time.Time, N] {
+	type point struct {
+		at    time.Time
+		value N
+	}
+	var items []T
+	if c != nil {
+		items = c.Values()
+	}
+	points := make([]point, 0, len(items))
+	for _, item := range items {
+		points = append(points, point{at: timeFn(item), value: valueFn(item)})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].at.Before(points[j].at)
+	})
+
+	selected := points
+	if threshold >= 3 && len(points) > threshold {
+		x := func(i int) float64 { return float64(points[i].at.Sub(points[0].at)) }
+		y := func(i int) float64 { return float64(points[i].value) }
+
+		selected = make([]point, 0, threshold)
+		selected = append(selected, points[0])
+		// 除首尾两点外，其余的点平均分为 threshold-2 个区间
+		size := float64(len(points)-2) / float64(threshold-2)
+		prev := 0
+		for b := 0; b < threshold-2; b++ {
+			start := int(float64(b)*size) + 1
+			end := int(float64(b+1)*size) + 1
+
+			// 下一个区间的平均点，最后一个区间使用终点
+			nextStart, nextEnd := end, min(int(float64(b+2)*size)+1, len(points))
+			if b == threshold-3 {
+				nextStart, nextEnd = len(points)-1, len(points)
+			}
+			var avgX, avgY float64
+			for i := nextStart; i < nextEnd; i++ {
+				avgX += x(i)
+				avgY += y(i)
+			}
+			avgX /= float64(nextEnd - nextStart)
+			avgY /= float64(nextEnd - nextStart)
+
+			best, bestArea := start, -1.0
+			for i := start; i < end; i++ {
+				area := math.Abs((x(prev)-avgX)*(y(i)-y(prev)) - (x(prev)-x(i))*(avgY-y(prev)))
+				if area > bestArea {
+					best, bestArea = i, area
+				}
+			}
+			selected = append(selected, points[best])
+			prev = best
+		}
+		selected = append(selected, points[len(points)-1])
+	}
+
+	values := make(map[time.Time]N, len(selected))
+	keys := make([]time.Time, 0, len(selected))
+	for _, p := range selected {
+		if _, exists := values[p.at]; !exists {
+			keys = append(keys, p.at)
+		}
+		values[p.at] = p.value
+	}
+
+	return newTimeCollection(values, keys)
+}
+
+// bucketByTime 按时间分桶，返回桶内元素与按时间升序排列的桶
+func bucketByTime[T any](c *slice_collcection.Collection[T], timeFn func(item T) time.Time, interval TimeInterval, loc *time.Location, opts []TimeBucketOption) (map[time.Time][]T, []time.Time, error) {
+	options := &timeBucketOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var from, to time.Time
+	if !options.from.IsZero() {
+		from = interval.Truncate(options.from, loc)
+	}
+	if !options.to.IsZero() {
+		to = interval.Truncate(options.to, loc)
+	}
+
+	var items []T
+	if c != nil {
+		items = c.Values()
+	}
+	// time.Time 包含时区信息，统一使用 loc 下的值作为 key
+	buckets := make(map[time.Time][]T)
+	for _, item := range items {
+		bucket := interval.Truncate(timeFn(item), loc)
+		if !from.IsZero() && bucket.Before(from) || !to.IsZero() && bucket.After(to) {
+			continue
+		}
+		buckets[bucket] = append(buckets[bucket], item)
+	}
+
+	keys := make([]time.Time, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Before(keys[j])
+	})
+
+	if !options.fillGaps {
+		return buckets, keys, nil
+	}
+	if from.IsZero() || to.IsZero() {
+		if len(keys) == 0 {
+			return buckets, keys, nil
+		}
+		if from.IsZero() {
+			from = keys[0]
+		}
+		if to.IsZero() {
+			to = keys[len(keys)-1]
+		}
+	}
+
+	filled := make([]time.Time, 0, len(keys))
+	for bucket := from; !bucket.After(to); bucket = interval.Next(bucket, loc) {
+		if len(filled) == MaxTimeBuckets {
+			return nil, nil, fmt.Errorf("%w: more than %d time buckets from %s to %s", errorx.TooLargeError, MaxTimeBuckets, from, to)
+		}
+		filled = append(filled, bucket)
+	}
+
+	return buckets, filled, nil
+}
+
+// newTimeCollection 创建以时间为 key、按 keys 顺序排列的 Collection
+func newTimeCollection[V any](values map[time.Time]V, keys []time.Time) *Collection[time.Time, V] {
+	coll := NewCollection(values, WithKeyCompare[time.Time, V](func(a, b time.Time) int {
+		return a.Compare(b)
+	}))
+	coll.sortedKeys = keys
+
+	return coll
+}
+
+// civilDays 返回日期距 1970-01-01 的天数
+func civilDays(year int, month time.Month, day int) int {
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// civilDate 返回距 1970-01-01 第 days 天在 loc 时区下的 0 点
+func civilDate(days int, loc *time.Location) time.Time {
+	year, month, day := time.Unix(int64(days)*86400, 0).UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// floorDiv 向下取整的整数除法
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,318 @@
+package map_collection
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Trie 基于前缀树的字符串 key 集合，提供与 Collection 相同的常用方法（Get、Set、Remove、Filter、Foreach、ToJSON），
+// 并支持按前缀查询：KeysWithPrefix、WalkPrefix、LongestPrefixOf、DeletePrefix，
+// 前缀查询的耗时只与前缀长度和匹配的 key 数量有关，与总 key 数量无关。
+// 遍历按 key 的字典序（字节序，与 sort.Strings 相同）进行。需要使用 NewTrie 创建，Trie 不是并发安全的
+type Trie[V any] struct {
+	root *trieNode[V]
+}
+
+// trieNode 前缀树节点，children 按 label 升序排列
+type trieNode[V any] struct {
+	label    byte
+	children []*trieNode[V]
+	value    V
+	hasValue bool
+	size     int // 子树（含自身）中 key 的数量
+}
+
+// NewTrie 使用 map 创建 Trie，values 为 nil 时创建空 Trie
+//
+// 使用示例：
+//
+//	routes := map_collection.NewTrie(map[string]Handler{"/api": api, "/api/users": users})
+//	prefix, h, ok := routes.LongestPrefixOf("/api/users/42") // "/api/users"
+//	routes.KeysWithPrefix("/api/")                          // [/api/users]
+func NewTrie[T ~map[string]V, V any](values T) *Trie[V] {
+	t := &Trie[V]{root: &trieNode[V]{}}
+	for k, v := range values {
+		t.Set(k, v)
+	}
+	return t
+}
+
+// NewTrieFrom 使用 Collection 的键值对创建 Trie，不修改原 Collection
+func NewTrieFrom[V any](c *Collection[string, V]) *Trie[V] {
+	return NewTrie(c.value)
+}
+
+// Count 返回 key 的数量
+func (t *Trie[V]) Count() int {
+	return t.root.size
+}
+
+// IsEmpty 判断是否为空
+func (t *Trie[V]) IsEmpty() bool {
+	return t.root.size == 0
+}
+
+// Get 获取指定 key 的值
+// 返回值和是否存在的标志
+func (t *Trie[V]) Get(key string) (V, bool) {
+	n := t.root.find(key)
+	if n == nil || !n.hasValue {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+// GetValue 获取指定 key 的值，如果不存在返回零值
+func (t *Trie[V]) GetValue(key string) V {
+	v, _ := t.Get(key)
+	return v
+}
+
+// Has 判断是否存在指定的 key
+func (t *Trie[V]) Has(key string) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Set 设置 key->val（直接修改当前 Trie，返回自身以支持链式调用）
+func (t *Trie[V]) Set(key string, val V) *Trie[V] {
+	// 先确认 key 是否已存在，新增时才需要更新路径上的 size
+	added := !t.Has(key)
+	n := t.root
+	if added {
+		n.size++
+	}
+	for i := 0; i < len(key); i++ {
+		n = n.child(key[i], true)
+		if added {
+			n.size++
+		}
+	}
+	n.value = val
+	n.hasValue = true
+
+	return t
+}
+
+// Remove 删除指定的 key（直接修改当前 Trie，返回自身以支持链式调用）
+func (t *Trie[V]) Remove(key string) *Trie[V] {
+	if t.Has(key) {
+		t.root.remove(key, false)
+	}
+	return t
+}
+
+// DeletePrefix 删除所有以 prefix 开头的 key，返回删除的数量；prefix 为空时清空 Trie
+func (t *Trie[V]) DeletePrefix(prefix string) int {
+	n := t.root.find(prefix)
+	if n == nil || n.size == 0 {
+		return 0
+	}
+	removed := n.size
+	if prefix == "" {
+		t.root = &trieNode[V]{}
+		return removed
+	}
+	t.root.remove(prefix, true)
+	return removed
+}
+
+// KeysWithPrefix 按字典序返回所有以 prefix 开头的 key
+func (t *Trie[V]) KeysWithPrefix(prefix string) []string {
+	n := t.root.find(prefix)
+	if n == nil {
+		return []string{}
+	}
+	keys := make([]string, 0, n.size)
+	n.walk([]byte(prefix), func(value V, key string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// CountPrefix 返回以 prefix 开头的 key 的数量，O(len(prefix))
+func (t *Trie[V]) CountPrefix(prefix string) int {
+	if n := t.root.find(prefix); n != nil {
+		return n.size
+	}
+	return 0
+}
+
+// WalkPrefix 按字典序遍历所有以 prefix 开头的键值对，fn 返回 false 时终止
+// 适合自动补全等只需要前几个结果的场景
+func (t *Trie[V]) WalkPrefix(prefix string, fn func(value V, key string) bool) {
+	if n := t.root.find(prefix); n != nil {
+		n.walk([]byte(prefix), fn)
+	}
+}
+
+// LongestPrefixOf 返回 s 的最长前缀 key 及其值，不存在时返回 false；用于路由等最长前缀匹配场景
+func (t *Trie[V]) LongestPrefixOf(s string) (string, V, bool) {
+	var (
+		value V
+		found bool
+		end   int
+	)
+	n := t.root
+	if n.hasValue {
+		value, found = n.value, true
+	}
+	for i := 0; i < len(s); i++ {
+		if n = n.child(s[i], false); n == nil {
+			break
+		}
+		if n.hasValue {
+			value, found, end = n.value, true, i+1
+		}
+	}
+	return s[:end], value, found
+}
+
+// Keys 按字典序返回所有的 key
+func (t *Trie[V]) Keys() []string {
+	return t.KeysWithPrefix("")
+}
+
+// Values 按 key 的字典序返回所有的 value
+func (t *Trie[V]) Values() []V {
+	values := make([]V, 0, t.Count())
+	t.Foreach(func(value V, key string) {
+		values = append(values, value)
+	})
+	return values
+}
+
+// Filter 过滤键值对，返回新的 Trie
+// fn 函数返回 true 的键值对会被保留
+func (t *Trie[V]) Filter(fn func(value V, key string) bool) *Trie[V] {
+	res := NewTrie[map[string]V](nil)
+	t.Foreach(func(value V, key string) {
+		if fn(value, key) {
+			res.Set(key, value)
+		}
+	})
+	return res
+}
+
+// Each 对每个键值对执行回调函数（按 key 的字典序）
+func (t *Trie[V]) Each(fn func(value V, key string)) *Trie[V] {
+	return t.Foreach(fn)
+}
+
+// Foreach 对每个键值对执行回调函数（按 key 的字典序）
+func (t *Trie[V]) Foreach(fn func(value V, key string)) *Trie[V] {
+	t.WalkPrefix("", func(value V, key string) bool {
+		fn(value, key)
+		return true
+	})
+	return t
+}
+
+// All 返回所有键值对组成的新 map
+func (t *Trie[V]) All() map[string]V {
+	m := make(map[string]V, t.Count())
+	t.Foreach(func(value V, key string) {
+		m[key] = value
+	})
+	return m
+}
+
+// ToCollection 转换为 Collection，Foreach 顺序与 Trie 相同（按 key 的字典序）
+func (t *Trie[V]) ToCollection() *Collection[string, V] {
+	return NewCollection(t.All(), WithKeyCompare[string, V](strings.Compare))
+}
+
+// ToJSON 将 Trie 转换为 JSON 字符串
+func (t *Trie[V]) ToJSON() (string, error) {
+	data, err := json.Marshal(t.All())
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// find 返回 key 对应的节点，不存在时返回 nil
+func (n *trieNode[V]) find(key string) *trieNode[V] {
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.child(key[i], false)
+	}
+	return n
+}
+
+// child 返回 label 对应的子节点，create 为 true 时不存在则按序插入
+func (n *trieNode[V]) child(label byte, create bool) *trieNode[V] {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= label
+	})
+	if i < len(n.children) && n.children[i].label == label {
+		return n.children[i]
+	}
+	if !create {
+		return nil
+	}
+
+	c := &trieNode[V]{label: label}
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+	return c
+}
+
+// remove 删除 key（whole 为 true 时删除整个子树），更新路径上的 size 并回收空节点；调用方需保证 key 存在
+func (n *trieNode[V]) remove(key string, whole bool) {
+	target := n.find(key)
+	removed := 1
+	if whole {
+		removed = target.size
+	}
+
+	var zero V
+	parent := (*trieNode[V])(nil)
+	for i := 0; ; i++ {
+		n.size -= removed
+		if i == len(key) {
+			if whole {
+				n.children = nil
+			}
+			n.value, n.hasValue = zero, false
+			break
+		}
+		next := n.child(key[i], false)
+		if n.size == 0 && parent != nil {
+			// 整个子树已空，从父节点摘除后无需继续下降
+			parent.dropChild(n.label)
+			return
+		}
+		parent, n = n, next
+	}
+	if n.size == 0 && parent != nil {
+		parent.dropChild(n.label)
+	}
+}
+
+// dropChild 删除 label 对应的子节点
+func (n *trieNode[V]) dropChild(label byte) {
+	for i, c := range n.children {
+		if c.label == label {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// walk 以 prefix 为当前路径，先序（即字典序）遍历子树，fn 返回 false 时终止并返回 false
+func (n *trieNode[V]) walk(prefix []byte, fn func(value V, key string) bool) bool {
+	if n.hasValue && !fn(n.value, string(prefix)) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.walk(append(prefix, c.label), fn) {
+			return false
+		}
+	}
+	return true
+}
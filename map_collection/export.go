@@ -19,6 +19,11 @@ type Collection[K comparable, V any] struct {
 
 	// 缓存排序后的keys,用于稳定的First/Last操作
 	sortedKeys []K
+
+	// 二级索引，索引名 -> 索引
+	indexes map[string]*valueIndex[K, V]
+	// 排序后的索引名，保证唯一约束的检查顺序稳定；只在创建、删除索引时整体替换，可以在副本间共享
+	indexOrder []string
 }
 
 // WithKeyCompare 设置 key 的比较函数（用于排序）
@@ -59,6 +64,11 @@ func NewCollection[T ~map[K]V, K comparable, V any](values T, opts ...Collection
 		opt(coll)
 	}
 
+	// 设置了 key 比较函数时，初始 sortedKeys 需要按其排序
+	if coll.keyCompareFunc != nil {
+		coll.initSortedKeys()
+	}
+
 	return coll
 }
 
@@ -0,0 +1,514 @@
+package map_collection
+
+// 本文件实现 RFC 6902 JSON Patch 的生成与应用，适用于 Collection[string, any]，
+// 嵌套的 map[string]any 会递归比较并生成带层级路径（RFC 6901 JSON Pointer）的操作。
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// JSON Patch 支持的操作类型
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+// JSONPatchOperation RFC 6902 中的一条操作
+type JSONPatchOperation struct {
+	Op    string // 操作类型，见 PatchOpXxx
+	Path  string // 目标位置（JSON Pointer）
+	From  string // move/copy 的来源位置（JSON Pointer）
+	Value any    // add/replace/test 的值
+}
+
+// MarshalJSON 序列化为 RFC 6902 格式，add/replace/test 始终输出 value 字段（包括 null）
+func (op JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	switch op.Op {
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		return json.Marshal(struct {
+			Op    string `json:"op"`
+			Path  string `json:"path"`
+			Value any    `json:"value"`
+		}{op.Op, op.Path, op.Value})
+	case PatchOpMove, PatchOpCopy:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			From string `json:"from"`
+			Path string `json:"path"`
+		}{op.Op, op.From, op.Path})
+	default:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+}
+
+// UnmarshalJSON 从 RFC 6902 格式解析，并校验必需的字段
+func (op *JSONPatchOperation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Op    string          `json:"op"`
+		Path  *string         `json:"path"`
+		From  *string         `json:"from"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Path == nil {
+		return fmt.Errorf("%w: missing path", errorx.InvalidPatchError)
+	}
+
+	op.Op = raw.Op
+	op.Path = *raw.Path
+	op.From = ""
+	op.Value = nil
+
+	switch raw.Op {
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		if len(raw.Value) == 0 {
+			return fmt.Errorf("%w: %s operation missing value", errorx.InvalidPatchError, raw.Op)
+		}
+		return json.Unmarshal(raw.Value, &op.Value)
+	case PatchOpMove, PatchOpCopy:
+		if raw.From == nil {
+			return fmt.Errorf("%w: %s operation missing from", errorx.InvalidPatchError, raw.Op)
+		}
+		op.From = *raw.From
+	case PatchOpRemove:
+	default:
+		return fmt.Errorf("%w: unknown op %q", errorx.InvalidPatchError, raw.Op)
+	}
+
+	return nil
+}
+
+// JSONPatch 生成将 a 变为 b 的 JSON Patch 操作列表
+// 两侧同一位置都是 map[string]any 时递归比较，其余值不相等时整体 replace；
+// 每一层都按 remove、replace、add 的顺序输出，同类操作按 key 的字典序排列，相同的输入总是生成相同的文档
+func JSONPatch(a, b *Collection[string, any]) []JSONPatchOperation {
+	var oldMap, newMap map[string]any
+	if a != nil {
+		oldMap = a.value
+	}
+	if b != nil {
+		newMap = b.value
+	}
+
+	return appendJSONValueDiff(make([]JSONPatchOperation, 0), "", oldMap, newMap)
+}
+
+// JSONPatchDocument 生成将 a 变为 b 的 JSON Patch 文档
+func JSONPatchDocument(a, b *Collection[string, any]) ([]byte, error) {
+	return json.Marshal(JSONPatch(a, b))
+}
+
+// ApplyJSONPatch 将 JSON Patch 操作列表应用到 c 上（不修改原 Collection，返回新的 Collection）
+// 操作按顺序执行，任意一条失败或结果违反唯一索引时返回错误，原 Collection 保持不变；c 为 nil 时视为空文档。
+// 非 map[string]any、[]any 的嵌套值（如 map[string]int、结构体）会先经过 JSON 编解码，结果中对应的值为 JSON 解码后的类型
+func ApplyJSONPatch(c *Collection[string, any], ops []JSONPatchOperation) (*Collection[string, any], error) {
+	var value map[string]any
+	if c != nil {
+		value = c.value
+	}
+	root, err := normalizeJSONValue(map[string]any(value))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		root, err = applyJSONPatchOperation(root, op)
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	newMap, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: patched document is not an object", errorx.InvalidPatchError)
+	}
+	if c == nil {
+		return NewCollection(newMap), nil
+	}
+
+	newColl := c.cloneWithSortedKeys(newMap)
+	newColl.sortedKeys = c.filterSortedKeys(newMap)
+	addedKeys := make([]string, 0)
+	for k := range newMap {
+		if _, exists := c.value[k]; !exists {
+			addedKeys = append(addedKeys, k)
+		}
+	}
+	sort.Strings(addedKeys)
+	for _, k := range addedKeys {
+		newColl.insertKeyInOrder(k)
+	}
+	if err := newColl.validateIndexes(); err != nil {
+		return nil, err
+	}
+
+	return newColl, nil
+}
+
+// ApplyJSONPatchDocument 解析 JSON Patch 文档并应用到 c 上（不修改原 Collection，返回新的 Collection）
+func ApplyJSONPatchDocument(c *Collection[string, any], doc []byte) (*Collection[string, any], error) {
+	var ops []JSONPatchOperation
+	if err := json.Unmarshal(doc, &ops); err != nil {
+		return nil, err
+	}
+
+	return ApplyJSONPatch(c, ops)
+}
+
+// appendJSONValueDiff 比较 path 处的新旧值，追加所需的操作
+func appendJSONValueDiff(ops []JSONPatchOperation, path string, oldVal, newVal any) []JSONPatchOperation {
+	oldMap, oldIsMap := oldVal.(map[string]any)
+	newMap, newIsMap := newVal.(map[string]any)
+	if !oldIsMap || !newIsMap {
+		if !reflect.DeepEqual(oldVal, newVal) {
+			ops = append(ops, JSONPatchOperation{Op: PatchOpReplace, Path: path, Value: newVal})
+		}
+		return ops
+	}
+
+	for _, k := range sortedStringKeys(oldMap) {
+		if _, ok := newMap[k]; !ok {
+			ops = append(ops, JSONPatchOperation{Op: PatchOpRemove, Path: path + "/" + escapeJSONPointer(k)})
+		}
+	}
+	for _, k := range sortedStringKeys(oldMap) {
+		if nv, ok := newMap[k]; ok {
+			ops = appendJSONValueDiff(ops, path+"/"+escapeJSONPointer(k), oldMap[k], nv)
+		}
+	}
+	for _, k := range sortedStringKeys(newMap) {
+		if _, ok := oldMap[k]; !ok {
+			ops = append(ops, JSONPatchOperation{Op: PatchOpAdd, Path: path + "/" + escapeJSONPointer(k), Value: newMap[k]})
+		}
+	}
+
+	return ops
+}
+
+// applyJSONPatchOperation 对文档执行一条操作，返回新的根节点
+func applyJSONPatchOperation(root any, op JSONPatchOperation) (any, error) {
+	tokens, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case PatchOpAdd:
+		val, err := normalizeJSONValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(root, tokens, val)
+	case PatchOpRemove:
+		newRoot, _, err := jsonPointerRemove(root, tokens)
+		return newRoot, err
+	case PatchOpReplace:
+		if _, err := jsonPointerGet(root, tokens); err != nil {
+			return nil, err
+		}
+		val, err := normalizeJSONValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			return val, nil
+		}
+		newRoot, _, err := jsonPointerRemove(root, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(newRoot, tokens, val)
+	case PatchOpMove:
+		fromTokens, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if isJSONPointerPrefix(fromTokens, tokens) && len(fromTokens) < len(tokens) {
+			return nil, fmt.Errorf("%w: cannot move %q into its own child", errorx.InvalidPatchError, op.From)
+		}
+		newRoot, val, err := jsonPointerRemove(root, fromTokens)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(newRoot, tokens, val)
+	case PatchOpCopy:
+		fromTokens, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		val, err := jsonPointerGet(root, fromTokens)
+		if err != nil {
+			return nil, err
+		}
+		if val, err = normalizeJSONValue(val); err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(root, tokens, val)
+	case PatchOpTest:
+		val, err := jsonPointerGet(root, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(val, op.Value) {
+			return nil, errorx.PatchTestFailedError
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", errorx.InvalidPatchError, op.Op)
+	}
+}
+
+// jsonPointerGet 获取 tokens 指向的值
+func jsonPointerGet(node any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path %q not found", errorx.InvalidPatchError, token)
+			}
+			node = child
+		case []any:
+			idx, err := jsonArrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[idx]
+		default:
+			return nil, fmt.Errorf("%w: cannot traverse into %T", errorx.InvalidPatchError, node)
+		}
+	}
+
+	return node, nil
+}
+
+// jsonPointerAdd 在 tokens 指向的位置添加值（数组为插入，对象为设置）
+func jsonPointerAdd(root any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return updateJSONPointerParent(root, tokens, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[key] = value
+			return p, nil
+		case []any:
+			idx, err := jsonArrayIndex(key, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+			res := make([]any, 0, len(p)+1)
+			res = append(res, p[:idx]...)
+			res = append(res, value)
+			res = append(res, p[idx:]...)
+			return res, nil
+		default:
+			return nil, fmt.Errorf("%w: cannot add into %T", errorx.InvalidPatchError, parent)
+		}
+	})
+}
+
+// jsonPointerRemove 删除 tokens 指向的值，返回新的根节点与被删除的值
+func jsonPointerRemove(root any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", errorx.InvalidPatchError)
+	}
+
+	var removed any
+	newRoot, err := updateJSONPointerParent(root, tokens, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			val, ok := p[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: path %q not found", errorx.InvalidPatchError, key)
+			}
+			removed = val
+			delete(p, key)
+			return p, nil
+		case []any:
+			idx, err := jsonArrayIndex(key, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = p[idx]
+			res := make([]any, 0, len(p)-1)
+			res = append(res, p[:idx]...)
+			res = append(res, p[idx+1:]...)
+			return res, nil
+		default:
+			return nil, fmt.Errorf("%w: cannot remove from %T", errorx.InvalidPatchError, parent)
+		}
+	})
+
+	return newRoot, removed, err
+}
+
+// updateJSONPointerParent 沿 tokens 找到目标的父节点，由 fn 返回父节点更新后的值，并逐层写回
+func updateJSONPointerParent(node any, tokens []string, fn func(parent any, key string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: path %q not found", errorx.InvalidPatchError, tokens[0])
+		}
+		newChild, err := updateJSONPointerParent(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = newChild
+		return n, nil
+	case []any:
+		idx, err := jsonArrayIndex(tokens[0], len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		newChild, err := updateJSONPointerParent(n[idx], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = newChild
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot traverse into %T", errorx.InvalidPatchError, node)
+	}
+}
+
+// jsonArrayIndex 解析数组下标，allowEnd 为 true 时允许 "-" 与 length（表示追加）
+func jsonArrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", errorx.InvalidPatchError, token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("%w: invalid array index %q", errorx.InvalidPatchError, token)
+	}
+	if idx > length || (idx == length && !allowEnd) {
+		return 0, fmt.Errorf("%w: array index %d out of range", errorx.InvalidPatchError, idx)
+	}
+
+	return idx, nil
+}
+
+// parseJSONPointer 解析 RFC 6901 JSON Pointer，"" 表示整个文档
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", errorx.InvalidPatchError, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// escapeJSONPointer 转义 JSON Pointer 中的特殊字符
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// isJSONPointerPrefix prefix 是否为 tokens 的前缀
+func isJSONPointerPrefix(prefix, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeJSONValue 深拷贝 map[string]any 与 []any 组成的嵌套结构，
+// 其它 map、切片、数组、结构体与指针等类型的值通过 JSON 编解码转换为同样的结构，避免与原值共享底层数据
+func normalizeJSONValue(v any) (any, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(val))
+		for k, item := range val {
+			normalized, err := normalizeJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = normalized
+		}
+		return res, nil
+	case []any:
+		res := make([]any, len(val))
+		for i, item := range val {
+			normalized, err := normalizeJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = normalized
+		}
+		return res, nil
+	case nil:
+		return nil, nil
+	}
+
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer, reflect.Interface:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errorx.InvalidPatchError, err)
+		}
+		var res any
+		if err := json.Unmarshal(data, &res); err != nil {
+			return nil, fmt.Errorf("%w: %v", errorx.InvalidPatchError, err)
+		}
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+// jsonEqual 按 JSON 语义比较两个值（如 int 1 与 float64 1 相等）
+func jsonEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	aData, errA := json.Marshal(a)
+	bData, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(aData, bData)
+}
+
+// sortedStringKeys 返回按字典序排列的 key
+func sortedStringKeys(m map[string]any) []string {
+	keys := Keys(m)
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,3 @@
+// Package search 提供基于倒排索引的全文检索，可对 slice_collcection 中结构体的字符串字段建立索引，
+// 支持可替换的分词器（中文二元分词、英文空白分词）、增量添加/删除以及 BM25/TF-IDF 排序与高亮摘要。
+package search
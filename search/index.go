@@ -0,0 +1,360 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// Field 需要建立索引的字符串字段
+type Field[T any] struct {
+	Name  string              // 字段名，作为 Hit.Highlights 的 key
+	Value func(item T) string // 读取字段文本
+	Boost float64             // 字段权重，小于等于 0 时为 1
+}
+
+// Option 索引选项
+type Option func(*options)
+
+// options 索引选项
+type options struct {
+	tokenizer Tokenizer
+	scorer    Scorer
+}
+
+// WithTokenizer 设置分词器，默认为 CJKBigramTokenizer
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(o *options) {
+		o.tokenizer = tokenizer
+	}
+}
+
+// WithScorer 设置评分方式，默认为 BM25(1.2, 0.75)
+func WithScorer(scorer Scorer) Option {
+	return func(o *options) {
+		o.scorer = scorer
+	}
+}
+
+// Index 倒排索引，按 idFn 返回的 ID 标识文档，并发安全
+type Index[K comparable, T any] struct {
+	mu sync.RWMutex
+
+	idFn      func(item T) K
+	fields    []Field[T]
+	tokenizer Tokenizer
+	scorer    Scorer
+
+	docs     map[K]*document[T]
+	postings []map[string]map[K]int // 按字段：词项 -> 文档 -> 词频
+	totalLen []int                  // 按字段：所有文档的词项数之和
+	seq      int
+}
+
+// document 已索引的文档
+type document[T any] struct {
+	item  T
+	seq   int        // 添加顺序，得分相同时先添加的排在前面
+	lens  []int      // 按字段：词项数
+	terms [][]string // 按字段：去重后的词项，用于删除
+}
+
+// Hit 检索结果
+type Hit[K comparable, T any] struct {
+	ID         K                 `json:"id"`
+	Item       T                 `json:"item"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"` // 字段名 -> 高亮后的摘要，只包含命中的字段
+}
+
+// NewIndex 创建倒排索引
+//
+// 使用示例：
+//
+//	idx, err := search.NewIndex(func(p Product) int { return p.ID }, []search.Field[Product]{
+//	    {Name: "title", Value: func(p Product) string { return p.Title }, Boost: 2},
+//	    {Name: "description", Value: func(p Product) string { return p.Description }},
+//	})
+//	idx.AddCollection(products)
+//	hits := idx.Search("无线 耳机", search.WithLimit(20))
+func NewIndex[K comparable, T any](idFn func(item T) K, fields []Field[T], opts ...Option) (*Index[K, T], error) {
+	if idFn == nil {
+		return nil, fmt.Errorf("%w: idFn", errorx.NilFunc)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: at least one field is required", errorx.FieldNotFoundError)
+	}
+	for _, field := range fields {
+		if field.Value == nil {
+			return nil, fmt.Errorf("%w: value of field %s", errorx.NilFunc, field.Name)
+		}
+	}
+
+	o := &options{tokenizer: CJKBigramTokenizer(), scorer: BM25(1.2, 0.75)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	idx := &Index[K, T]{
+		idFn:      idFn,
+		fields:    append([]Field[T](nil), fields...),
+		tokenizer: o.tokenizer,
+		scorer:    o.scorer,
+		docs:      make(map[K]*document[T]),
+		postings:  make([]map[string]map[K]int, len(fields)),
+		totalLen:  make([]int, len(fields)),
+	}
+	for i := range idx.postings {
+		idx.postings[i] = make(map[string]map[K]int)
+	}
+
+	return idx, nil
+}
+
+// FromCollection 创建倒排索引并添加 Collection 中的所有元素
+func FromCollection[K comparable, T any](c *slice_collcection.Collection[T], idFn func(item T) K, fields []Field[T], opts ...Option) (*Index[K, T], error) {
+	idx, err := NewIndex(idFn, fields, opts...)
+	if err != nil {
+		return nil, err
+	}
+	idx.AddCollection(c)
+
+	return idx, nil
+}
+
+// Add 添加文档，ID 已存在时替换原文档
+func (idx *Index[K, T]) Add(items ...T) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, item := range items {
+		id := idx.idFn(item)
+		idx.remove(id)
+
+		doc := &document[T]{
+			item:  item,
+			seq:   idx.seq,
+			lens:  make([]int, len(idx.fields)),
+			terms: make([][]string, len(idx.fields)),
+		}
+		idx.seq++
+		for f, field := range idx.fields {
+			tokens := idx.tokenizer.Tokenize(field.Value(item))
+			doc.lens[f] = len(tokens)
+			idx.totalLen[f] += len(tokens)
+			for _, token := range tokens {
+				posting, ok := idx.postings[f][token.Term]
+				if !ok {
+					posting = make(map[K]int)
+					idx.postings[f][token.Term] = posting
+				}
+				if posting[id] == 0 {
+					doc.terms[f] = append(doc.terms[f], token.Term)
+				}
+				posting[id]++
+			}
+		}
+		idx.docs[id] = doc
+	}
+}
+
+// AddCollection 添加 Collection 中的所有元素
+func (idx *Index[K, T]) AddCollection(c *slice_collcection.Collection[T]) {
+	if c == nil {
+		return
+	}
+	idx.Add(c.Values()...)
+}
+
+// Remove 删除文档，返回实际删除的数量
+func (idx *Index[K, T]) Remove(ids ...K) int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	removed := 0
+	for _, id := range ids {
+		if idx.remove(id) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Get 返回已索引的文档
+func (idx *Index[K, T]) Get(id K) (item T, ok bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	doc, ok := idx.docs[id]
+	if !ok {
+		return item, false
+	}
+	return doc.item, true
+}
+
+// Count 返回文档数量
+func (idx *Index[K, T]) Count() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+// remove 删除文档，调用方需要持有写锁
+func (idx *Index[K, T]) remove(id K) bool {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return false
+	}
+	for f, terms := range doc.terms {
+		idx.totalLen[f] -= doc.lens[f]
+		for _, term := range terms {
+			posting := idx.postings[f][term]
+			delete(posting, id)
+			if len(posting) == 0 {
+				delete(idx.postings[f], term)
+			}
+		}
+	}
+	delete(idx.docs, id)
+
+	return true
+}
+
+// SearchOption 检索选项
+type SearchOption func(*searchOptions)
+
+// searchOptions 检索选项
+type searchOptions struct {
+	limit         int
+	matchAll      bool
+	pre, post     string
+	snippetLength int
+}
+
+// WithLimit 最多返回 n 个结果，默认返回全部
+func WithLimit(n int) SearchOption {
+	return func(o *searchOptions) {
+		o.limit = n
+	}
+}
+
+// WithMatchAll 只返回包含查询中所有词项的文档，默认包含任一词项即可
+func WithMatchAll() SearchOption {
+	return func(o *searchOptions) {
+		o.matchAll = true
+	}
+}
+
+// WithHighlight 设置高亮标记，默认为 <em> 与 </em>
+func WithHighlight(pre, post string) SearchOption {
+	return func(o *searchOptions) {
+		o.pre = pre
+		o.post = post
+	}
+}
+
+// WithSnippetLength 设置摘要的最大字符数，默认为 80，小于等于 0 时返回高亮后的全文
+func WithSnippetLength(n int) SearchOption {
+	return func(o *searchOptions) {
+		o.snippetLength = n
+	}
+}
+
+// Search 检索并按相关度从高到低返回结果，查询使用与索引相同的分词器
+func (idx *Index[K, T]) Search(query string, opts ...SearchOption) *slice_collcection.Collection[Hit[K, T]] {
+	o := &searchOptions{pre: "<em>", post: "</em>", snippetLength: 80}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	terms := make([]string, 0)
+	querySet := make(map[string]bool)
+	for _, token := range idx.tokenizer.Tokenize(query) {
+		if !querySet[token.Term] {
+			querySet[token.Term] = true
+			terms = append(terms, token.Term)
+		}
+	}
+	if len(terms) == 0 {
+		return slice_collcection.NewCollection(make([]Hit[K, T], 0))
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[K]float64)
+	matched := make(map[K]int)
+	for _, term := range terms {
+		seen := make(map[K]bool)
+		for f, field := range idx.fields {
+			posting := idx.postings[f][term]
+			if len(posting) == 0 {
+				continue
+			}
+			boost := field.Boost
+			if boost <= 0 {
+				boost = 1
+			}
+			avgLen := float64(idx.totalLen[f]) / float64(len(idx.docs))
+			for id, tf := range posting {
+				scores[id] += boost * idx.scorer.Score(TermStats{
+					TF:        tf,
+					DF:        len(posting),
+					DocCount:  len(idx.docs),
+					DocLen:    float64(idx.docs[id].lens[f]),
+					AvgDocLen: avgLen,
+				})
+				if !seen[id] {
+					seen[id] = true
+					matched[id]++
+				}
+			}
+		}
+	}
+
+	hits := make([]Hit[K, T], 0, len(scores))
+	for id, score := range scores {
+		if o.matchAll && matched[id] < len(terms) {
+			continue
+		}
+		hits = append(hits, Hit[K, T]{ID: id, Item: idx.docs[id].item, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return idx.docs[hits[i].ID].seq < idx.docs[hits[j].ID].seq
+	})
+	if o.limit > 0 && o.limit < len(hits) {
+		hits = hits[:o.limit]
+	}
+
+	for i := range hits {
+		hits[i].Highlights = idx.highlight(hits[i].Item, querySet, o)
+	}
+
+	return slice_collcection.NewCollection(hits)
+}
+
+// highlight 为命中的字段生成高亮摘要
+func (idx *Index[K, T]) highlight(item T, querySet map[string]bool, o *searchOptions) map[string]string {
+	highlights := make(map[string]string)
+	for _, field := range idx.fields {
+		text := field.Value(item)
+		ranges := make([][2]int, 0)
+		for _, token := range idx.tokenizer.Tokenize(text) {
+			if querySet[token.Term] {
+				ranges = append(ranges, [2]int{token.Start, token.End})
+			}
+		}
+		if len(ranges) > 0 {
+			highlights[field.Name] = snippet(text, ranges, o.snippetLength, o.pre, o.post)
+		}
+	}
+
+	return highlights
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"math"
+)
+
+// TermStats 计算一个词项在一个文档字段中的得分所需的统计信息
+type TermStats struct {
+	TF        int     // 词项在字段中出现的次数
+	DF        int     // 包含该词项的文档数（按字段统计）
+	DocCount  int     // 文档总数
+	DocLen    float64 // 字段的词项数
+	AvgDocLen float64 // 所有文档该字段的平均词项数
+}
+
+// Scorer 相关度评分，查询中每个词项在每个字段上的得分乘以字段权重后累加为文档得分
+type Scorer interface {
+	Score(s TermStats) float64
+}
+
+// ScorerFunc 将函数适配为 Scorer
+type ScorerFunc func(s TermStats) float64
+
+// Score 实现 Scorer 接口
+func (f ScorerFunc) Score(s TermStats) float64 {
+	return f(s)
+}
+
+// BM25 Okapi BM25 评分，k1 控制词频饱和度（常用 1.2），b 控制字段长度归一化程度（常用 0.75）
+func BM25(k1, b float64) Scorer {
+	return ScorerFunc(func(s TermStats) float64 {
+		tf := float64(s.TF)
+		norm := 1.0
+		if s.AvgDocLen > 0 {
+			norm = 1 - b + b*s.DocLen/s.AvgDocLen
+		}
+		return idf(s) * tf * (k1 + 1) / (tf + k1*norm)
+	})
+}
+
+// TFIDF 经典 TF-IDF 评分，词频取对数以避免长文本中的高频词主导结果
+func TFIDF() Scorer {
+	return ScorerFunc(func(s TermStats) float64 {
+		if s.TF == 0 {
+			return 0
+		}
+		return (1 + math.Log(float64(s.TF))) * math.Log(1+float64(s.DocCount)/float64(s.DF))
+	})
+}
+
+// idf BM25 使用的逆文档频率，始终为正数
+func idf(s TermStats) float64 {
+	n, df := float64(s.DocCount), float64(s.DF)
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
@@ -0,0 +1,183 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+type product struct {
+	ID          int
+	Title       string
+	Description string
+}
+
+func productFields() []Field[product] {
+	return []Field[product]{
+		{Name: "title", Value: func(p product) string { return p.Title }, Boost: 2},
+		{Name: "description", Value: func(p product) string { return p.Description }},
+	}
+}
+
+func productIndex(t *testing.T, opts ...Option) *Index[int, product] {
+	t.Helper()
+	products := slice_collcection.NewCollection([]product{
+		{1, "蓝牙耳机", "降噪无线蓝牙耳机，续航 30 小时"},
+		{2, "Wireless Mouse", "Ergonomic wireless mouse with USB receiver"},
+		{3, "有线耳机", "入耳式有线耳机，适合运动"},
+		{4, "USB Cable", "Braided USB-C cable, 2 meters"},
+		{5, "无线充电器", "支持 Wireless 快充的无线充电器"},
+	})
+	idx, err := FromCollection(products, func(p product) int { return p.ID }, productFields(), opts...)
+	if err != nil {
+		t.Fatalf("FromCollection returned error: %v", err)
+	}
+	return idx
+}
+
+func hitIDs(hits *slice_collcection.Collection[Hit[int, product]]) []int {
+	ids := make([]int, 0, hits.Count())
+	for _, hit := range hits.Values() {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func terms(tokens []Token) []string {
+	res := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, token.Term)
+	}
+	return res
+}
+
+func TestTokenizers(t *testing.T) {
+	text := "全文检索 Go语言, Hello-World 中"
+	got := CJKBigramTokenizer().Tokenize(text)
+	if expected := []string{"全文", "文检", "检索", "go", "语言", "hello", "world", "中"}; !reflect.DeepEqual(terms(got), expected) {
+		t.Errorf("Expected %v, got %v", expected, terms(got))
+	}
+	for _, token := range got {
+		if text[token.Start:token.End] != token.Term && token.Term != "go" && token.Term != "hello" && token.Term != "world" {
+			t.Errorf("Unexpected offsets for %q: %q", token.Term, text[token.Start:token.End])
+		}
+	}
+
+	got = WhitespaceTokenizer().Tokenize("Hello, WORLD! USB-C 全文")
+	if expected := []string{"hello", "world", "usb", "c", "全文"}; !reflect.DeepEqual(terms(got), expected) {
+		t.Errorf("Expected %v, got %v", expected, terms(got))
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	idx := productIndex(t)
+
+	// 标题与描述都命中时，描述较短的排在前面
+	if ids := hitIDs(idx.Search("耳机")); !reflect.DeepEqual(ids, []int{3, 1}) {
+		t.Errorf("Unexpected hits %v", ids)
+	}
+	if ids := hitIDs(idx.Search("wireless")); !reflect.DeepEqual(ids, []int{2, 5}) {
+		t.Errorf("Unexpected hits %v", ids)
+	}
+	if ids := hitIDs(idx.Search("无线 耳机", WithMatchAll())); !reflect.DeepEqual(ids, []int{1}) {
+		t.Errorf("Unexpected match-all hits %v", ids)
+	}
+	if ids := hitIDs(idx.Search("无线 耳机", WithLimit(2))); len(ids) != 2 || ids[0] != 1 {
+		t.Errorf("Unexpected limited hits %v", ids)
+	}
+	if hits := idx.Search("  ,, "); !hits.IsEmpty() {
+		t.Error("Empty query should return no hits")
+	}
+
+	tfidf := productIndex(t, WithScorer(TFIDF()))
+	if ids := hitIDs(tfidf.Search("usb")); !reflect.DeepEqual(ids, []int{4, 2}) {
+		t.Errorf("Unexpected tf-idf hits %v", ids)
+	}
+}
+
+func TestSearchHighlights(t *testing.T) {
+	idx := productIndex(t)
+
+	hit := idx.Search("蓝牙耳机").First()
+	expected := map[string]string{
+		"title":       "<em>蓝牙耳机</em>",
+		"description": "降噪无线<em>蓝牙耳机</em>，续航 30 小时",
+	}
+	if !reflect.DeepEqual(hit.Highlights, expected) {
+		t.Errorf("Expected %v, got %v", expected, hit.Highlights)
+	}
+
+	hit = idx.Search("receiver", WithHighlight("[", "]"), WithSnippetLength(12)).First()
+	if got := hit.Highlights["description"]; got != "…SB [receiver]" {
+		t.Errorf("Unexpected snippet %q", got)
+	}
+}
+
+func TestIndexIncremental(t *testing.T) {
+	idx := productIndex(t)
+
+	idx.Add(product{ID: 6, Title: "头戴式耳机", Description: "无线"})
+	if idx.Count() != 6 {
+		t.Errorf("Expected 6 documents, got %d", idx.Count())
+	}
+	if ids := hitIDs(idx.Search("耳机")); !reflect.DeepEqual(ids, []int{3, 1, 6}) {
+		t.Errorf("Unexpected hits after add %v", ids)
+	}
+
+	// 相同 ID 替换原文档
+	idx.Add(product{ID: 3, Title: "运动水壶"})
+	if ids := hitIDs(idx.Search("耳机")); !reflect.DeepEqual(ids, []int{1, 6}) {
+		t.Errorf("Unexpected hits after replace %v", ids)
+	}
+
+	if removed := idx.Remove(1, 99); removed != 1 {
+		t.Errorf("Expected 1 removed, got %d", removed)
+	}
+	if ids := hitIDs(idx.Search("耳机")); !reflect.DeepEqual(ids, []int{6}) {
+		t.Errorf("Unexpected hits after remove %v", ids)
+	}
+	if _, ok := idx.Get(1); ok {
+		t.Error("Removed document should not be found")
+	}
+	if p, ok := idx.Get(3); !ok || p.Title != "运动水壶" {
+		t.Errorf("Unexpected document %v", p)
+	}
+}
+
+func TestIndexConcurrent(t *testing.T) {
+	idx := productIndex(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			idx.Add(product{ID: 100 + id, Title: "蓝牙音箱"})
+		}(i)
+		go func() {
+			defer wg.Done()
+			idx.Search("蓝牙")
+		}()
+	}
+	wg.Wait()
+
+	if hits := idx.Search("音箱"); hits.Count() != 8 {
+		t.Errorf("Expected 8 hits, got %d", hits.Count())
+	}
+}
+
+func TestNewIndexErrors(t *testing.T) {
+	if _, err := NewIndex[int](nil, productFields()); !errors.Is(err, errorx.NilFunc) {
+		t.Errorf("Expected NilFunc, got %v", err)
+	}
+	if _, err := NewIndex(func(p product) int { return p.ID }, []Field[product]{{Name: "title"}}); !errors.Is(err, errorx.NilFunc) {
+		t.Errorf("Expected NilFunc, got %v", err)
+	}
+	if _, err := NewIndex(func(p product) int { return p.ID }, nil); !errors.Is(err, errorx.FieldNotFoundError) {
+		t.Errorf("Expected FieldNotFoundError, got %v", err)
+	}
+}
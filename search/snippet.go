@@ -0,0 +1,72 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippet 截取包含第一个命中位置、最多 length 个字符的摘要，并用 pre/post 包裹命中的范围
+// 命中位置之前保留约 length/4 个字符的上下文，被截断的一端使用省略号
+func snippet(text string, ranges [][2]int, length int, pre, post string) string {
+	ranges = mergeRanges(ranges)
+
+	start, end := 0, len(text)
+	if length > 0 && utf8.RuneCountInString(text) > length {
+		start = ranges[0][0]
+		for context := length / 4; context > 0 && start > 0; context-- {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+		}
+		end = start
+		for n := 0; n < length && end < len(text); n++ {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		// 不截断高亮范围
+		for _, r := range ranges {
+			if r[0] < end && r[1] > end {
+				end = r[1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, r := range ranges {
+		if r[1] <= start || r[0] >= end {
+			continue
+		}
+		sb.WriteString(text[pos:r[0]])
+		sb.WriteString(pre)
+		sb.WriteString(text[r[0]:r[1]])
+		sb.WriteString(post)
+		pos = r[1]
+	}
+	sb.WriteString(text[pos:end])
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
+
+// mergeRanges 排序并合并重叠或相邻的范围（二元分词的词项会相互重叠）
+func mergeRanges(ranges [][2]int) [][2]int {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token 分词结果
+type Token struct {
+	Term  string // 归一化后的词项，用于建立索引与匹配
+	Start int    // 在原文中的起始字节偏移
+	End   int    // 在原文中的结束字节偏移（不包含），用于高亮
+}
+
+// Tokenizer 分词器，索引与查询使用同一个分词器
+type Tokenizer interface {
+	Tokenize(text string) []Token
+}
+
+// TokenizerFunc 将函数适配为 Tokenizer
+type TokenizerFunc func(text string) []Token
+
+// Tokenize 实现 Tokenizer 接口
+func (f TokenizerFunc) Tokenize(text string) []Token {
+	return f(text)
+}
+
+// WhitespaceTokenizer 英文分词器：按空白与标点切分并转为小写
+func WhitespaceTokenizer() Tokenizer {
+	return TokenizerFunc(func(text string) []Token {
+		return tokenizeWords(text, nil)
+	})
+}
+
+// CJKBigramTokenizer 中日韩文本的二元分词器：连续的中日韩字符按相邻两个字切分（“全文检索” → 全文、文检、检索），
+// 单独出现的字作为一个词项；其余文本与 WhitespaceTokenizer 相同，适合中英文混排的内容
+func CJKBigramTokenizer() Tokenizer {
+	return TokenizerFunc(func(text string) []Token {
+		return tokenizeWords(text, isCJK)
+	})
+}
+
+// tokenizeWords 按字母与数字切分单词并转为小写，cjk 不为 nil 时对其匹配的字符使用二元切分
+func tokenizeWords(text string, cjk func(r rune) bool) []Token {
+	tokens := make([]Token, 0)
+	wordStart := -1
+	flushWord := func(end int) {
+		if wordStart >= 0 {
+			tokens = append(tokens, Token{Term: strings.ToLower(text[wordStart:end]), Start: wordStart, End: end})
+			wordStart = -1
+		}
+	}
+
+	// 连续中日韩字符的起始位置，prev 为上一个字符的起始位置
+	runStart, prev := -1, -1
+	flushRun := func(end int) {
+		if runStart >= 0 && prev == runStart {
+			tokens = append(tokens, Token{Term: text[runStart:end], Start: runStart, End: end})
+		}
+		runStart, prev = -1, -1
+	}
+
+	for i, r := range text {
+		switch {
+		case cjk != nil && cjk(r):
+			flushWord(i)
+			if runStart < 0 {
+				runStart = i
+			} else {
+				tokens = append(tokens, Token{Term: text[prev : i+utf8.RuneLen(r)], Start: prev, End: i + utf8.RuneLen(r)})
+			}
+			prev = i
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushRun(i)
+			if wordStart < 0 {
+				wordStart = i
+			}
+		default:
+			flushRun(i)
+			flushWord(i)
+		}
+	}
+	flushRun(len(text))
+	flushWord(len(text))
+
+	return tokens
+}
+
+// isCJK 是否为中日韩字符
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,3 @@
+// Package set 提供基于哈希的泛型集合 Set，支持并、交、差等集合运算，
+// 以及按比较函数排序的 OrderedSet 与并发安全的 SafeSet。
+package set
@@ -0,0 +1,226 @@
+package set
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// OrderedSet 按比较函数保持有序的集合，遍历、Values 与 JSON 均按比较函数从小到大的顺序
+// 使用哈希判断成员关系，有序切片维护顺序：Has 为 O(1)，Add/Remove 为 O(n)；
+// 集合运算返回使用相同比较函数的新 OrderedSet。比较函数返回 0 的元素需要 ==，否则顺序不确定
+type OrderedSet[T comparable] struct {
+	set     *Set[T]
+	sorted  []T
+	compare func(a, b T) int
+}
+
+// NewOrdered 创建有序集合，compare 为 nil 时使用元素类型的默认比较函数（见 utils.NewCompareFuncOf），没有默认比较函数时返回 errorx.NoComparableError
+func NewOrdered[T comparable](compare func(a, b T) int, items ...T) (*OrderedSet[T], error) {
+	if compare == nil {
+		var zero T
+		fn := utils.NewCompareFuncOf(reflect.TypeOf(zero))
+		if fn == nil {
+			return nil, errorx.NoComparableError
+		}
+		compare = func(a, b T) int {
+			return fn(a, b)
+		}
+	}
+
+	s := &OrderedSet[T]{set: New[T](), compare: compare}
+	return s.Add(items...), nil
+}
+
+// Add 添加元素
+func (s *OrderedSet[T]) Add(items ...T) *OrderedSet[T] {
+	for _, item := range items {
+		if s.set.Has(item) {
+			continue
+		}
+		s.set.Add(item)
+		i := s.search(item)
+		s.sorted = append(s.sorted, item)
+		copy(s.sorted[i+1:], s.sorted[i:])
+		s.sorted[i] = item
+	}
+	return s
+}
+
+// Remove 删除元素
+func (s *OrderedSet[T]) Remove(items ...T) *OrderedSet[T] {
+	for _, item := range items {
+		if !s.set.Has(item) {
+			continue
+		}
+		s.set.Remove(item)
+		for i := s.search(item); i < len(s.sorted); i++ {
+			if s.sorted[i] == item {
+				s.sorted = append(s.sorted[:i], s.sorted[i+1:]...)
+				break
+			}
+		}
+	}
+	return s
+}
+
+// Has 是否包含元素
+func (s *OrderedSet[T]) Has(item T) bool {
+	return s.set.Has(item)
+}
+
+// Count 元素数量
+func (s *OrderedSet[T]) Count() int {
+	return len(s.sorted)
+}
+
+// IsEmpty 是否为空
+func (s *OrderedSet[T]) IsEmpty() bool {
+	return len(s.sorted) == 0
+}
+
+// Values 按顺序返回所有元素的副本
+func (s *OrderedSet[T]) Values() []T {
+	return append(make([]T, 0, len(s.sorted)), s.sorted...)
+}
+
+// First 返回最小的元素，集合为空时返回 false
+func (s *OrderedSet[T]) First() (T, bool) {
+	if len(s.sorted) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.sorted[0], true
+}
+
+// Last 返回最大的元素，集合为空时返回 false
+func (s *OrderedSet[T]) Last() (T, bool) {
+	if len(s.sorted) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.sorted[len(s.sorted)-1], true
+}
+
+// Each 按顺序遍历元素，f 返回 false 时终止
+func (s *OrderedSet[T]) Each(f func(item T) bool) {
+	for _, item := range s.sorted {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// Set 转换为无序的 Set
+func (s *OrderedSet[T]) Set() *Set[T] {
+	return s.set.Clone()
+}
+
+// ToCollection 转换为按顺序排列的 slice Collection
+func (s *OrderedSet[T]) ToCollection() *slice_collcection.Collection[T] {
+	return slice_collcection.NewCollection(s.Values())
+}
+
+// Union 并集
+func (s *OrderedSet[T]) Union(other *OrderedSet[T]) *OrderedSet[T] {
+	return s.filterBoth(other, func(inS, inOther bool) bool { return true })
+}
+
+// Intersection 交集
+func (s *OrderedSet[T]) Intersection(other *OrderedSet[T]) *OrderedSet[T] {
+	return s.filterBoth(other, func(inS, inOther bool) bool { return inS && inOther })
+}
+
+// Difference 差集，属于 s 但不属于 other 的元素
+func (s *OrderedSet[T]) Difference(other *OrderedSet[T]) *OrderedSet[T] {
+	return s.filterBoth(other, func(inS, inOther bool) bool { return inS && !inOther })
+}
+
+// SymmetricDifference 对称差集，只属于其中一个集合的元素
+func (s *OrderedSet[T]) SymmetricDifference(other *OrderedSet[T]) *OrderedSet[T] {
+	return s.filterBoth(other, func(inS, inOther bool) bool { return inS != inOther })
+}
+
+// IsSubset s 是否为 other 的子集
+func (s *OrderedSet[T]) IsSubset(other *OrderedSet[T]) bool {
+	return s.set.IsSubset(other.set)
+}
+
+// IsSuperset s 是否为 other 的超集
+func (s *OrderedSet[T]) IsSuperset(other *OrderedSet[T]) bool {
+	return s.set.IsSuperset(other.set)
+}
+
+// Disjoint 两个集合是否没有公共元素
+func (s *OrderedSet[T]) Disjoint(other *OrderedSet[T]) bool {
+	return s.set.Disjoint(other.set)
+}
+
+// Equal 两个集合的元素是否相同
+func (s *OrderedSet[T]) Equal(other *OrderedSet[T]) bool {
+	return s.set.Equal(other.set)
+}
+
+// MarshalJSON 序列化为按顺序排列的 JSON 数组
+func (s *OrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Values())
+}
+
+// UnmarshalJSON 从 JSON 数组反序列化；零值 OrderedSet 使用元素类型的默认比较函数
+func (s *OrderedSet[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if s.compare == nil {
+		ordered, err := NewOrdered[T](nil)
+		if err != nil {
+			return err
+		}
+		s.compare = ordered.compare
+	}
+	s.set = New[T]()
+	s.sorted = nil
+	s.Add(items...)
+	return nil
+}
+
+// search 返回第一个不小于 item 的位置
+func (s *OrderedSet[T]) search(item T) int {
+	return sort.Search(len(s.sorted), func(i int) bool {
+		return s.compare(s.sorted[i], item) >= 0
+	})
+}
+
+// filterBoth 归并两个有序切片，保留 keep 返回 true 的元素，结果使用 s 的比较函数
+func (s *OrderedSet[T]) filterBoth(other *OrderedSet[T], keep func(inS, inOther bool) bool) *OrderedSet[T] {
+	res := &OrderedSet[T]{set: New[T](), compare: s.compare}
+	appendItem := func(item T, inS, inOther bool) {
+		if keep(inS, inOther) && !res.set.Has(item) {
+			res.set.Add(item)
+			res.sorted = append(res.sorted, item)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(s.sorted) || j < len(other.sorted) {
+		switch {
+		case j >= len(other.sorted) || i < len(s.sorted) && s.compare(s.sorted[i], other.sorted[j]) < 0:
+			appendItem(s.sorted[i], true, other.Has(s.sorted[i]))
+			i++
+		case i >= len(s.sorted) || s.compare(s.sorted[i], other.sorted[j]) > 0:
+			appendItem(other.sorted[j], s.Has(other.sorted[j]), true)
+			j++
+		default:
+			appendItem(s.sorted[i], true, other.Has(s.sorted[i]))
+			appendItem(other.sorted[j], s.Has(other.sorted[j]), true)
+			i++
+			j++
+		}
+	}
+	return res
+}
@@ -0,0 +1,197 @@
+package set
+
+import (
+	"sync"
+
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// SafeSet 并发安全的 Set，读写使用读写锁保护
+// 集合运算的参数为普通 Set（可通过 Snapshot 获取），返回新的 Set，避免同时持有两个集合的锁
+type SafeSet[T comparable] struct {
+	mu  sync.RWMutex
+	set *Set[T]
+}
+
+// NewSafe 创建并发安全的集合
+func NewSafe[T comparable](items ...T) *SafeSet[T] {
+	return &SafeSet[T]{set: New(items...)}
+}
+
+// Add 添加元素
+func (s *SafeSet[T]) Add(items ...T) *SafeSet[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set.Add(items...)
+	return s
+}
+
+// Remove 删除元素
+func (s *SafeSet[T]) Remove(items ...T) *SafeSet[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set.Remove(items...)
+	return s
+}
+
+// AddIfAbsent 元素不存在时添加并返回 true，用于原子地“检查并添加”
+func (s *SafeSet[T]) AddIfAbsent(item T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.set.Has(item) {
+		return false
+	}
+	s.set.Add(item)
+	return true
+}
+
+// Has 是否包含元素
+func (s *SafeSet[T]) Has(item T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Has(item)
+}
+
+// Count 元素数量
+func (s *SafeSet[T]) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Count()
+}
+
+// IsEmpty 是否为空
+func (s *SafeSet[T]) IsEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.IsEmpty()
+}
+
+// Clear 清空集合
+func (s *SafeSet[T]) Clear() *SafeSet[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set.Clear()
+	return s
+}
+
+// Snapshot 返回当前元素的副本
+func (s *SafeSet[T]) Snapshot() *Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Clone()
+}
+
+// Values 返回所有元素，顺序不固定
+func (s *SafeSet[T]) Values() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Values()
+}
+
+// Sorted 返回排序后的元素
+func (s *SafeSet[T]) Sorted() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Sorted()
+}
+
+// Each 遍历元素，遍历期间持有读锁，f 中不能修改该集合
+func (s *SafeSet[T]) Each(f func(item T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.set.Each(f)
+}
+
+// ToCollection 转换为按 Sorted 顺序排列的 slice Collection
+func (s *SafeSet[T]) ToCollection() *slice_collcection.Collection[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.ToCollection()
+}
+
+// Union 并集
+func (s *SafeSet[T]) Union(other *Set[T]) *Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Union(other)
+}
+
+// Intersection 交集
+func (s *SafeSet[T]) Intersection(other *Set[T]) *Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Intersection(other)
+}
+
+// Difference 差集
+func (s *SafeSet[T]) Difference(other *Set[T]) *Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Difference(other)
+}
+
+// SymmetricDifference 对称差集
+func (s *SafeSet[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.SymmetricDifference(other)
+}
+
+// IsSubset 是否为 other 的子集
+func (s *SafeSet[T]) IsSubset(other *Set[T]) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.IsSubset(other)
+}
+
+// IsSuperset 是否为 other 的超集
+func (s *SafeSet[T]) IsSuperset(other *Set[T]) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.IsSuperset(other)
+}
+
+// Disjoint 是否与 other 没有公共元素
+func (s *SafeSet[T]) Disjoint(other *Set[T]) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.Disjoint(other)
+}
+
+// MarshalJSON 序列化为排序后的 JSON 数组
+func (s *SafeSet[T]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.set.MarshalJSON()
+}
+
+// UnmarshalJSON 从 JSON 数组反序列化
+func (s *SafeSet[T]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.set == nil {
+		s.set = New[T]()
+	}
+	return s.set.UnmarshalJSON(data)
+}
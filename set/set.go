@@ -0,0 +1,290 @@
+package set
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// maxPowerSetSize PowerSet 允许的最大元素数量，结果包含 2^n 个集合
+const maxPowerSetSize = 20
+
+// Set 基于 map 的无序集合，集合运算均返回新的 Set，不修改参与运算的集合
+// Set 不是并发安全的，并发场景使用 SafeSet
+type Set[T comparable] struct {
+	m map[T]struct{}
+}
+
+// Pair Cartesian 的结果元素
+type Pair[A comparable, B comparable] struct {
+	First  A `json:"first"`
+	Second B `json:"second"`
+}
+
+// New 创建集合
+func New[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{m: make(map[T]struct{}, len(items))}
+	return s.Add(items...)
+}
+
+// FromCollection 使用 slice Collection 的元素创建集合
+func FromCollection[T comparable](c *slice_collcection.Collection[T]) *Set[T] {
+	if c == nil {
+		return New[T]()
+	}
+	return New(c.Values()...)
+}
+
+// FromMapKeys 使用 map Collection 的 key 创建集合
+func FromMapKeys[K comparable, V any](c *map_collection.Collection[K, V]) *Set[K] {
+	if c == nil {
+		return New[K]()
+	}
+	return New(c.Keys()...)
+}
+
+// Add 添加元素
+func (s *Set[T]) Add(items ...T) *Set[T] {
+	if s.m == nil {
+		s.m = make(map[T]struct{}, len(items))
+	}
+	for _, item := range items {
+		s.m[item] = struct{}{}
+	}
+	return s
+}
+
+// Remove 删除元素
+func (s *Set[T]) Remove(items ...T) *Set[T] {
+	for _, item := range items {
+		delete(s.m, item)
+	}
+	return s
+}
+
+// Has 是否包含元素
+func (s *Set[T]) Has(item T) bool {
+	_, ok := s.m[item]
+	return ok
+}
+
+// Count 元素数量
+func (s *Set[T]) Count() int {
+	return len(s.m)
+}
+
+// IsEmpty 是否为空
+func (s *Set[T]) IsEmpty() bool {
+	return len(s.m) == 0
+}
+
+// Clear 清空集合
+func (s *Set[T]) Clear() *Set[T] {
+	clear(s.m)
+	return s
+}
+
+// Clone 复制集合
+func (s *Set[T]) Clone() *Set[T] {
+	res := &Set[T]{m: make(map[T]struct{}, len(s.m))}
+	for item := range s.m {
+		res.m[item] = struct{}{}
+	}
+	return res
+}
+
+// Values 返回所有元素，顺序不固定；需要固定顺序时使用 Sorted
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, len(s.m))
+	for item := range s.m {
+		values = append(values, item)
+	}
+	return values
+}
+
+// Sorted 返回排序后的元素：有默认比较函数（见 utils.NewCompareFuncOf）的类型按默认比较函数排序，其它类型按 JSON 编码排序
+func (s *Set[T]) Sorted() []T {
+	values := s.Values()
+	sortValues(values)
+	return values
+}
+
+// Each 遍历元素，顺序不固定，f 返回 false 时终止
+func (s *Set[T]) Each(f func(item T) bool) {
+	for item := range s.m {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// ToCollection 转换为按 Sorted 顺序排列的 slice Collection
+func (s *Set[T]) ToCollection() *slice_collcection.Collection[T] {
+	return slice_collcection.NewCollection(s.Sorted())
+}
+
+// Union 并集
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	res := s.Clone()
+	for item := range other.m {
+		res.m[item] = struct{}{}
+	}
+	return res
+}
+
+// Intersection 交集
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if len(small.m) > len(large.m) {
+		small, large = large, small
+	}
+	res := New[T]()
+	for item := range small.m {
+		if large.Has(item) {
+			res.m[item] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference 差集，属于 s 但不属于 other 的元素
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	res := New[T]()
+	for item := range s.m {
+		if !other.Has(item) {
+			res.m[item] = struct{}{}
+		}
+	}
+	return res
+}
+
+// SymmetricDifference 对称差集，只属于其中一个集合的元素
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	res := s.Difference(other)
+	for item := range other.m {
+		if !s.Has(item) {
+			res.m[item] = struct{}{}
+		}
+	}
+	return res
+}
+
+// IsSubset s 是否为 other 的子集
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if len(s.m) > len(other.m) {
+		return false
+	}
+	for item := range s.m {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset s 是否为 other 的超集
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Disjoint 两个集合是否没有公共元素
+func (s *Set[T]) Disjoint(other *Set[T]) bool {
+	small, large := s, other
+	if len(small.m) > len(large.m) {
+		small, large = large, small
+	}
+	for item := range small.m {
+		if large.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 两个集合的元素是否相同
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return len(s.m) == len(other.m) && s.IsSubset(other)
+}
+
+// PowerSet 幂集，返回所有子集（包括空集与自身），按子集大小从小到大排列
+// 结果包含 2^n 个集合，元素超过 20 个时返回 errorx.TooLargeError
+func (s *Set[T]) PowerSet() (*slice_collcection.Collection[*Set[T]], error) {
+	if len(s.m) > maxPowerSetSize {
+		return nil, fmt.Errorf("%w: power set of %d elements", errorx.TooLargeError, len(s.m))
+	}
+
+	items := s.Sorted()
+	subsets := make([]*Set[T], 0, 1<<len(items))
+	for mask := 0; mask < 1<<len(items); mask++ {
+		subset := New[T]()
+		for i, item := range items {
+			if mask&(1<<i) != 0 {
+				subset.m[item] = struct{}{}
+			}
+		}
+		subsets = append(subsets, subset)
+	}
+	// 按大小稳定排序，同样大小的子集保持按位掩码生成的顺序
+	sort.SliceStable(subsets, func(i, j int) bool {
+		return subsets[i].Count() < subsets[j].Count()
+	})
+
+	return slice_collcection.NewCollection(subsets), nil
+}
+
+// Cartesian 笛卡尔积，返回所有 (a, b) 组成的集合
+func Cartesian[A comparable, B comparable](a *Set[A], b *Set[B]) *Set[Pair[A, B]] {
+	res := &Set[Pair[A, B]]{m: make(map[Pair[A, B]]struct{}, len(a.m)*len(b.m))}
+	for x := range a.m {
+		for y := range b.m {
+			res.m[Pair[A, B]{First: x, Second: y}] = struct{}{}
+		}
+	}
+	return res
+}
+
+// MarshalJSON 序列化为按 Sorted 顺序排列的 JSON 数组，保证输出稳定
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Sorted())
+}
+
+// UnmarshalJSON 从 JSON 数组反序列化，重复的元素会被合并
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	s.m = make(map[T]struct{}, len(items))
+	s.Add(items...)
+	return nil
+}
+
+// sortValues 对元素排序：有默认比较函数时使用比较函数，否则按 JSON 编码排序
+func sortValues[T comparable](values []T) {
+	var zero T
+	if compare := utils.NewCompareFuncOf(reflect.TypeOf(zero)); compare != nil {
+		sort.Slice(values, func(i, j int) bool {
+			return compare(values[i], values[j]) < 0
+		})
+		return
+	}
+
+	keys := make(map[T][]byte, len(values))
+	for _, v := range values {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%#v", v))
+		}
+		keys[v] = encoded
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return bytes.Compare(keys[values[i]], keys[values[j]]) < 0
+	})
+}
@@ -0,0 +1,235 @@
+package set
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	a := New(1, 2, 3, 4)
+	b := New(3, 4, 5)
+
+	cases := map[string]struct {
+		got      *Set[int]
+		expected []int
+	}{
+		"union":                {a.Union(b), []int{1, 2, 3, 4, 5}},
+		"intersection":         {a.Intersection(b), []int{3, 4}},
+		"difference":           {a.Difference(b), []int{1, 2}},
+		"symmetric difference": {a.SymmetricDifference(b), []int{1, 2, 5}},
+	}
+	for name, tc := range cases {
+		if !reflect.DeepEqual(tc.got.Sorted(), tc.expected) {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, tc.got.Sorted())
+		}
+	}
+	if !reflect.DeepEqual(a.Sorted(), []int{1, 2, 3, 4}) {
+		t.Error("Set operations should not modify the receiver")
+	}
+
+	if !New(3, 4).IsSubset(a) || a.IsSubset(b) || !a.IsSuperset(New(1)) {
+		t.Error("Unexpected subset result")
+	}
+	if a.Disjoint(b) || !a.Disjoint(New(9)) {
+		t.Error("Unexpected disjoint result")
+	}
+	if !a.Equal(New(4, 3, 2, 1, 1)) || a.Equal(b) {
+		t.Error("Unexpected equal result")
+	}
+
+	a.Add(9).Remove(1, 2)
+	if !a.Has(9) || a.Has(1) || a.Count() != 3 {
+		t.Errorf("Unexpected set after Add/Remove %v", a.Sorted())
+	}
+
+	var zero Set[string]
+	zero.Add("x")
+	if !zero.Has("x") {
+		t.Error("Zero value set should be usable")
+	}
+}
+
+func TestSetPowerSetAndCartesian(t *testing.T) {
+	subsets, err := New("a", "b", "c").PowerSet()
+	if err != nil {
+		t.Fatalf("PowerSet returned error: %v", err)
+	}
+	got := make([][]string, 0, subsets.Count())
+	for _, s := range subsets.Values() {
+		got = append(got, s.Sorted())
+	}
+	expected := [][]string{{}, {"a"}, {"b"}, {"c"}, {"a", "b"}, {"a", "c"}, {"b", "c"}, {"a", "b", "c"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	big := New[int]()
+	for i := 0; i < 21; i++ {
+		big.Add(i)
+	}
+	if _, err := big.PowerSet(); !errors.Is(err, errorx.TooLargeError) {
+		t.Errorf("Expected TooLargeError, got %v", err)
+	}
+
+	product := Cartesian(New(1, 2), New("x", "y"))
+	if product.Count() != 4 || !product.Has(Pair[int, string]{2, "x"}) {
+		t.Errorf("Unexpected cartesian product %v", product.Values())
+	}
+	data, _ := json.Marshal(product)
+	if string(data) != `[{"first":1,"second":"x"},{"first":1,"second":"y"},{"first":2,"second":"x"},{"first":2,"second":"y"}]` {
+		t.Errorf("Unexpected json %s", data)
+	}
+}
+
+func TestSetJSONAndConversions(t *testing.T) {
+	s := New("pear", "apple", "fig")
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != `["apple","fig","pear"]` {
+		t.Errorf("Unexpected json %s (%v)", data, err)
+	}
+
+	var decoded Set[string]
+	if err := json.Unmarshal([]byte(`["b","a","b"]`), &decoded); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Sorted(), []string{"a", "b"}) {
+		t.Errorf("Unexpected decoded set %v", decoded.Sorted())
+	}
+
+	fromSlice := FromCollection(slice_collcection.NewCollection([]int{3, 1, 3}))
+	if !reflect.DeepEqual(fromSlice.ToCollection().Values(), []int{1, 3}) {
+		t.Errorf("Unexpected set from collection %v", fromSlice.Sorted())
+	}
+	fromMap := FromMapKeys(map_collection.NewCollection(map[string]int{"x": 1, "y": 2}))
+	if !reflect.DeepEqual(fromMap.Sorted(), []string{"x", "y"}) {
+		t.Errorf("Unexpected set from map keys %v", fromMap.Sorted())
+	}
+}
+
+type setID int
+
+func TestSetNamedTypes(t *testing.T) {
+	s := New[setID](3, 1, 2)
+
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != "[1,2,3]" {
+		t.Errorf("Expected [1,2,3], got %s (%v)", data, err)
+	}
+	if got := s.ToCollection().Values(); !reflect.DeepEqual(got, []setID{1, 2, 3}) {
+		t.Errorf("Expected sorted collection, got %v", got)
+	}
+	subsets, err := New[setID](2, 1).PowerSet()
+	if err != nil || subsets.Count() != 4 {
+		t.Errorf("PowerSet returned %v, %v", subsets, err)
+	}
+
+	ordered, err := NewOrdered[setID](nil, 3, 1, 2)
+	if err != nil {
+		t.Fatalf("NewOrdered returned error: %v", err)
+	}
+	if !reflect.DeepEqual(ordered.Values(), []setID{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", ordered.Values())
+	}
+
+	type code string
+	codes, err := NewOrdered[code](nil, "b", "a")
+	if err != nil || !reflect.DeepEqual(codes.Values(), []code{"a", "b"}) {
+		t.Errorf("NewOrdered for named strings returned %v, %v", codes, err)
+	}
+}
+
+func TestOrderedSet(t *testing.T) {
+	byLength := func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+		return 0
+	}
+	s, err := NewOrdered(byLength, "banana", "fig", "apple", "fig")
+	if err != nil {
+		t.Fatalf("NewOrdered returned error: %v", err)
+	}
+	if !reflect.DeepEqual(s.Values(), []string{"fig", "apple", "banana"}) {
+		t.Errorf("Unexpected order %v", s.Values())
+	}
+	s.Add("kiwi").Remove("apple")
+	if !reflect.DeepEqual(s.Values(), []string{"fig", "kiwi", "banana"}) {
+		t.Errorf("Unexpected order after Add/Remove %v", s.Values())
+	}
+	if first, _ := s.First(); first != "fig" {
+		t.Errorf("Unexpected first %s", first)
+	}
+	if last, _ := s.Last(); last != "banana" {
+		t.Errorf("Unexpected last %s", last)
+	}
+
+	other, _ := NewOrdered(byLength, "kiwi", "cherry", "date")
+	if got := s.Union(other).Values(); !reflect.DeepEqual(got, []string{"fig", "date", "kiwi", "banana", "cherry"}) {
+		t.Errorf("Unexpected union %v", got)
+	}
+	if got := s.Intersection(other).Values(); !reflect.DeepEqual(got, []string{"kiwi"}) {
+		t.Errorf("Unexpected intersection %v", got)
+	}
+	if got := s.Difference(other).Values(); !reflect.DeepEqual(got, []string{"fig", "banana"}) {
+		t.Errorf("Unexpected difference %v", got)
+	}
+	if got := s.SymmetricDifference(other).Values(); !reflect.DeepEqual(got, []string{"fig", "date", "banana", "cherry"}) {
+		t.Errorf("Unexpected symmetric difference %v", got)
+	}
+
+	data, _ := json.Marshal(s)
+	if string(data) != `["fig","kiwi","banana"]` {
+		t.Errorf("Unexpected json %s", data)
+	}
+
+	var decoded OrderedSet[int]
+	if err := json.Unmarshal([]byte(`[3,1,2]`), &decoded); err != nil || !reflect.DeepEqual(decoded.Values(), []int{1, 2, 3}) {
+		t.Errorf("Unexpected decoded ordered set %v (%v)", decoded.Values(), err)
+	}
+
+	type point struct{ X, Y int }
+	if _, err := NewOrdered[point](nil); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+}
+
+func TestSafeSet(t *testing.T) {
+	s := NewSafe[int]()
+	var wg sync.WaitGroup
+	added := make(chan int, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			if s.AddIfAbsent(v % 10) {
+				added <- v % 10
+			}
+			s.Has(v)
+		}(i)
+	}
+	wg.Wait()
+	close(added)
+
+	if len(added) != 10 || s.Count() != 10 {
+		t.Errorf("Expected 10 distinct elements, got %d added, %d in set", len(added), s.Count())
+	}
+	if got := s.Intersection(New(1, 2, 42)).Sorted(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Unexpected intersection %v", got)
+	}
+	data, _ := json.Marshal(s)
+	if string(data) != `[0,1,2,3,4,5,6,7,8,9]` {
+		t.Errorf("Unexpected json %s", data)
+	}
+}
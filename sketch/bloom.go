@@ -0,0 +1,176 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// maxBloomHashes 哈希函数数量的上限，对应约 1e-19 的误判率；解码时超过上限的数据视为无效
+const maxBloomHashes = 64
+
+// Bloom Bloom 过滤器，ApproxContains 返回 false 时元素一定不存在，返回 true 时有一定概率误判
+// 位数组大小与哈希函数数量由预计元素数量与误判率确定，添加的元素超过预计数量后误判率会升高。Bloom 不是并发安全的
+type Bloom[T any] struct {
+	bits  []uint64
+	m     uint64 // 位数
+	k     uint64 // 哈希函数数量
+	count uint64 // 调用 Add 的次数（合并时相加），用于估算误判率
+}
+
+// NewBloom 按预计元素数量与期望误判率创建 Bloom 过滤器
+// expectedItems 需要大于 0，falsePositiveRate 需要在 (0, 1) 之间，否则返回 errorx.InvalidArgumentError；
+// 哈希函数数量最多为 64，误判率低于约 1e-19 时只增加位数
+//
+// 使用示例：
+//
+//	seen, _ := sketch.NewBloom[string](10_000_000, 0.001) // 约 17MB
+//	if !seen.ApproxContains(id) {
+//	    seen.Add(id) // 一定是第一次出现
+//	}
+func NewBloom[T any](expectedItems int, falsePositiveRate float64) (*Bloom[T], error) {
+	if expectedItems <= 0 {
+		return nil, fmt.Errorf("%w: expected items %d", errorx.InvalidArgumentError, expectedItems)
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		return nil, fmt.Errorf("%w: false positive rate %v", errorx.InvalidArgumentError, falsePositiveRate)
+	}
+
+	n := float64(expectedItems)
+	m := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Min(maxBloomHashes, math.Max(1, math.Round(float64(m)/n*math.Ln2))))
+	return newBloom[T](m, k), nil
+}
+
+// MustBloom 与 NewBloom 相同，参数不合法时 panic
+func MustBloom[T any](expectedItems int, falsePositiveRate float64) *Bloom[T] {
+	b, err := NewBloom[T](expectedItems, falsePositiveRate)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func newBloom[T any](m, k uint64) *Bloom[T] {
+	m = max(m, 64)
+	return &Bloom[T]{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// Add 添加元素
+func (b *Bloom[T]) Add(items ...T) {
+	for _, item := range items {
+		h1, h2 := doubleHash(hashOf(item))
+		for i := uint64(0); i < b.k; i++ {
+			pos := (h1 + i*h2) % b.m
+			b.bits[pos/64] |= 1 << (pos % 64)
+		}
+		b.count++
+	}
+}
+
+// ApproxContains 元素是否可能存在：返回 false 时一定不存在，返回 true 时可能误判
+func (b *Bloom[T]) ApproxContains(item T) bool {
+	h1, h2 := doubleHash(hashOf(item))
+	for i := uint64(0); i < b.k; i++ {
+		pos := (h1 + i*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddIfAbsent 元素可能已存在时返回 false，否则添加并返回 true，用于流式去重
+func (b *Bloom[T]) AddIfAbsent(item T) bool {
+	if b.ApproxContains(item) {
+		return false
+	}
+	b.Add(item)
+	return true
+}
+
+// Count 返回添加元素的次数（包括重复添加）
+func (b *Bloom[T]) Count() uint64 {
+	return b.count
+}
+
+// BitSize 返回位数组的位数
+func (b *Bloom[T]) BitSize() uint64 {
+	return b.m
+}
+
+// HashCount 返回哈希函数的数量
+func (b *Bloom[T]) HashCount() uint64 {
+	return b.k
+}
+
+// FalsePositiveRate 按当前置位比例估算误判率
+func (b *Bloom[T]) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range b.bits {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(b.m), float64(b.k))
+}
+
+// Merge 合并另一个参数相同的 Bloom 过滤器（按位或），合并后包含两者的全部元素
+// 参数不同时返回 errorx.IncompatibleMergeError
+func (b *Bloom[T]) Merge(other *Bloom[T]) error {
+	if b.m != other.m || b.k != other.k {
+		return fmt.Errorf("%w: bloom filters with m=%d,k=%d and m=%d,k=%d",
+			errorx.IncompatibleMergeError, b.m, b.k, other.m, other.k)
+	}
+	for i, w := range other.bits {
+		b.bits[i] |= w
+	}
+	b.count += other.count
+	return nil
+}
+
+// Clone 复制 Bloom 过滤器
+func (b *Bloom[T]) Clone() *Bloom[T] {
+	res := *b
+	res.bits = append([]uint64(nil), b.bits...)
+	return &res
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+func (b *Bloom[T]) MarshalBinary() ([]byte, error) {
+	e := newEncoder(kindBloom, 24+8*len(b.bits))
+	e.uint64(b.m)
+	e.uint64(b.k)
+	e.uint64(b.count)
+	for _, w := range b.bits {
+		e.uint64(w)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，数据格式错误时返回 errorx.InvalidEncodingError
+func (b *Bloom[T]) UnmarshalBinary(data []byte) error {
+	d := newDecoder(kindBloom, data)
+	m, k, count := d.uint64(), d.uint64(), d.uint64()
+	if d.err != nil {
+		return d.err
+	}
+	// 先确认 m 不超过剩余数据的位数，再计算字数，避免 m 接近 MaxUint64 时溢出
+	size := uint64(len(d.buf))
+	if m == 0 || size%8 != 0 || m > size*8 || (m+63)/64 != size/8 {
+		return fmt.Errorf("%w: bloom filter size mismatch", errorx.InvalidEncodingError)
+	}
+	if k == 0 || k > maxBloomHashes {
+		return fmt.Errorf("%w: bloom filter with %d hash functions", errorx.InvalidEncodingError, k)
+	}
+	words := make([]uint64, size/8)
+	for i := range words {
+		words[i] = d.uint64()
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	b.bits, b.m, b.k, b.count = words, m, k, count
+	return nil
+}
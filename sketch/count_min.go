@@ -0,0 +1,139 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// CountMin Count-Min Sketch 近似频次统计，ApproxFrequency 只会高估不会低估：
+// 以 1-delta 的概率，误差不超过 epsilon * Total()。CountMin 不是并发安全的
+type CountMin[T any] struct {
+	width    uint64
+	depth    uint64
+	counters []uint64 // depth 行 width 列
+	total    uint64
+}
+
+// NewCountMin 按误差与置信度创建 Count-Min Sketch：宽度为 ceil(e/epsilon)，深度为 ceil(ln(1/delta))
+// epsilon 与 delta 需要在 (0, 1) 之间，否则返回 errorx.InvalidArgumentError
+//
+// 使用示例：
+//
+//	freq, _ := sketch.NewCountMin[string](0.0001, 0.01) // 误差不超过总数的 0.01%，置信度 99%
+//	freq.AddCount(path, 1)
+//	freq.ApproxFrequency(path)
+func NewCountMin[T any](epsilon, delta float64) (*CountMin[T], error) {
+	if !(epsilon > 0 && epsilon < 1) {
+		return nil, fmt.Errorf("%w: epsilon %v", errorx.InvalidArgumentError, epsilon)
+	}
+	if !(delta > 0 && delta < 1) {
+		return nil, fmt.Errorf("%w: delta %v", errorx.InvalidArgumentError, delta)
+	}
+
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := uint64(math.Max(1, math.Ceil(math.Log(1/delta))))
+	return &CountMin[T]{width: width, depth: depth, counters: make([]uint64, width*depth)}, nil
+}
+
+// MustCountMin 与 NewCountMin 相同，参数不合法时 panic
+func MustCountMin[T any](epsilon, delta float64) *CountMin[T] {
+	c, err := NewCountMin[T](epsilon, delta)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Add 添加元素，每个元素的频次加 1
+func (c *CountMin[T]) Add(items ...T) {
+	for _, item := range items {
+		c.AddCount(item, 1)
+	}
+}
+
+// AddCount 元素的频次增加 n
+func (c *CountMin[T]) AddCount(item T, n uint64) {
+	h1, h2 := doubleHash(hashOf(item))
+	for i := uint64(0); i < c.depth; i++ {
+		c.counters[i*c.width+(h1+i*h2)%c.width] += n
+	}
+	c.total += n
+}
+
+// ApproxFrequency 估算元素的频次，结果不小于真实频次
+func (c *CountMin[T]) ApproxFrequency(item T) uint64 {
+	h1, h2 := doubleHash(hashOf(item))
+	res := uint64(math.MaxUint64)
+	for i := uint64(0); i < c.depth; i++ {
+		res = min(res, c.counters[i*c.width+(h1+i*h2)%c.width])
+	}
+	return res
+}
+
+// Total 返回所有元素的频次之和
+func (c *CountMin[T]) Total() uint64 {
+	return c.total
+}
+
+// Width 返回每行计数器的数量
+func (c *CountMin[T]) Width() uint64 {
+	return c.width
+}
+
+// Depth 返回行数（哈希函数数量）
+func (c *CountMin[T]) Depth() uint64 {
+	return c.depth
+}
+
+// Merge 合并另一个尺寸相同的 Count-Min Sketch（计数器相加），尺寸不同时返回 errorx.IncompatibleMergeError
+func (c *CountMin[T]) Merge(other *CountMin[T]) error {
+	if c.width != other.width || c.depth != other.depth {
+		return fmt.Errorf("%w: count-min sketches %dx%d and %dx%d",
+			errorx.IncompatibleMergeError, c.depth, c.width, other.depth, other.width)
+	}
+	for i, v := range other.counters {
+		c.counters[i] += v
+	}
+	c.total += other.total
+	return nil
+}
+
+// Clone 复制 Count-Min Sketch
+func (c *CountMin[T]) Clone() *CountMin[T] {
+	res := *c
+	res.counters = append([]uint64(nil), c.counters...)
+	return &res
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+func (c *CountMin[T]) MarshalBinary() ([]byte, error) {
+	e := newEncoder(kindCountMin, 24+8*len(c.counters))
+	e.uint64(c.width)
+	e.uint64(c.depth)
+	e.uint64(c.total)
+	for _, v := range c.counters {
+		e.uint64(v)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，数据格式错误时返回 errorx.InvalidEncodingError
+func (c *CountMin[T]) UnmarshalBinary(data []byte) error {
+	d := newDecoder(kindCountMin, data)
+	width, depth, total := d.uint64(), d.uint64(), d.uint64()
+	if d.err == nil && (width == 0 || depth == 0 || uint64(len(d.buf))/8/width != depth || uint64(len(d.buf)) != width*depth*8) {
+		return fmt.Errorf("%w: count-min sketch size mismatch", errorx.InvalidEncodingError)
+	}
+	counters := make([]uint64, width*depth)
+	for i := range counters {
+		counters[i] = d.uint64()
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	c.width, c.depth, c.total, c.counters = width, depth, total, counters
+	return nil
+}
@@ -0,0 +1,4 @@
+// Package sketch 提供用于海量数据的概率数据结构：Bloom 过滤器（近似成员判断）、HyperLogLog（近似去重计数）、
+// Count-Min Sketch（近似频次）与 Space-Saving TopK（高频元素），均以固定内存工作，
+// 支持从 slice_collcection 或 channel 填充、合并多个实例以及二进制序列化。
+package sketch
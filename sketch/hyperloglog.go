@@ -0,0 +1,138 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// HyperLogLog 近似去重计数，使用 2^precision 个 6 位以内的寄存器（每个寄存器一个字节），
+// 标准误差约为 1.04/sqrt(2^precision)，precision 为 14 时约 0.81%、占用 16KB。HyperLogLog 不是并发安全的
+type HyperLogLog[T any] struct {
+	precision uint8
+	registers []uint8
+}
+
+// precision 的取值范围
+const (
+	minPrecision = 4
+	maxPrecision = 18
+)
+
+// NewHyperLogLog 创建 HyperLogLog，precision 需要在 [4, 18] 之间，否则返回 errorx.InvalidArgumentError
+//
+// 使用示例：
+//
+//	uv, _ := sketch.NewHyperLogLog[int64](14)
+//	uv.Add(userID)
+//	uv.ApproxCountDistinct()
+func NewHyperLogLog[T any](precision int) (*HyperLogLog[T], error) {
+	if precision < minPrecision || precision > maxPrecision {
+		return nil, fmt.Errorf("%w: precision %d out of [%d, %d]", errorx.InvalidArgumentError, precision, minPrecision, maxPrecision)
+	}
+	return &HyperLogLog[T]{precision: uint8(precision), registers: make([]uint8, 1<<precision)}, nil
+}
+
+// MustHyperLogLog 与 NewHyperLogLog 相同，参数不合法时 panic
+func MustHyperLogLog[T any](precision int) *HyperLogLog[T] {
+	h, err := NewHyperLogLog[T](precision)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// Add 添加元素
+func (h *HyperLogLog[T]) Add(items ...T) {
+	for _, item := range items {
+		x := hashOf(item)
+		idx := x >> (64 - h.precision)
+		// 剩余的位左移后补一个哨兵位，保证前导零数量不超过 64-precision
+		w := x<<h.precision | 1<<(h.precision-1)
+		rho := uint8(bits.LeadingZeros64(w)) + 1
+		if rho > h.registers[idx] {
+			h.registers[idx] = rho
+		}
+	}
+}
+
+// ApproxCountDistinct 估算不同元素的数量，基数较小时使用线性计数修正
+func (h *HyperLogLog[T]) ApproxCountDistinct() uint64 {
+	m := float64(len(h.registers))
+	sum, zeros := 0.0, 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Precision 返回精度
+func (h *HyperLogLog[T]) Precision() int {
+	return int(h.precision)
+}
+
+// Merge 合并另一个精度相同的 HyperLogLog（寄存器取最大值），合并后的计数为两者的并集
+// 精度不同时返回 errorx.IncompatibleMergeError
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.precision != other.precision {
+		return fmt.Errorf("%w: hyperloglog precision %d and %d", errorx.IncompatibleMergeError, h.precision, other.precision)
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// Clone 复制 HyperLogLog
+func (h *HyperLogLog[T]) Clone() *HyperLogLog[T] {
+	return &HyperLogLog[T]{precision: h.precision, registers: append([]uint8(nil), h.registers...)}
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	e := newEncoder(kindHyperLogLog, 16+len(h.registers))
+	e.uint64(uint64(h.precision))
+	e.bytes(h.registers)
+	return e.buf, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，数据格式错误时返回 errorx.InvalidEncodingError
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	d := newDecoder(kindHyperLogLog, data)
+	precision := d.uint64()
+	registers := d.bytes()
+	if err := d.finish(); err != nil {
+		return err
+	}
+	if precision < minPrecision || precision > maxPrecision || len(registers) != 1<<precision {
+		return fmt.Errorf("%w: hyperloglog precision %d with %d registers", errorx.InvalidEncodingError, precision, len(registers))
+	}
+
+	h.precision = uint8(precision)
+	h.registers = append([]uint8(nil), registers...)
+	return nil
+}
+
+// hllAlpha 偏差修正系数
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
@@ -0,0 +1,213 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// Sketch 可逐个添加元素的概率结构，Bloom、HyperLogLog、CountMin 与 TopK 均实现了该接口
+type Sketch[T any] interface {
+	Add(items ...T)
+}
+
+// FromCollection 将 Collection 的所有元素添加到 s 中并返回 s
+//
+// 使用示例：
+//
+//	hll := sketch.FromCollection(sketch.MustHyperLogLog[string](14), visitorIDs)
+//	hll.ApproxCountDistinct()
+func FromCollection[T any, S Sketch[T]](s S, c *slice_collcection.Collection[T]) S {
+	if c != nil {
+		s.Add(c.Values()...)
+	}
+	return s
+}
+
+// FromStream 持续读取 ch 中的元素添加到 s 中，直到 ch 关闭后返回 s
+func FromStream[T any, S Sketch[T]](s S, ch <-chan T) S {
+	for item := range ch {
+		s.Add(item)
+	}
+	return s
+}
+
+// 二进制格式的类型标记，序列化数据以 magic、类型、版本三个字节开头
+const (
+	encodingMagic   byte = 0xC5
+	encodingVersion byte = 1
+
+	kindBloom       byte = 1
+	kindHyperLogLog byte = 2
+	kindCountMin    byte = 3
+	kindTopK        byte = 4
+)
+
+// encoder 按大端序写入二进制数据
+type encoder struct {
+	buf []byte
+}
+
+func newEncoder(kind byte, size int) *encoder {
+	e := &encoder{buf: make([]byte, 0, size+3)}
+	e.buf = append(e.buf, encodingMagic, kind, encodingVersion)
+	return e
+}
+
+func (e *encoder) uint64(v uint64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.uint64(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// decoder 按大端序读取二进制数据，遇到错误后的读取均返回零值，最终通过 err 统一返回
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func newDecoder(kind byte, data []byte) *decoder {
+	d := &decoder{buf: data}
+	if len(data) < 3 || data[0] != encodingMagic || data[1] != kind {
+		d.err = fmt.Errorf("%w: unexpected header", errorx.InvalidEncodingError)
+		return d
+	}
+	if data[2] != encodingVersion {
+		d.err = fmt.Errorf("%w: unsupported version %d", errorx.InvalidEncodingError, data[2])
+		return d
+	}
+	d.buf = data[3:]
+	return d
+}
+
+func (d *decoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 8 {
+		d.err = fmt.Errorf("%w: unexpected end of data", errorx.InvalidEncodingError)
+		return 0
+	}
+	v := binary.BigEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+// bytes 读取长度前缀的字节串，返回的切片引用原数据
+func (d *decoder) bytes() []byte {
+	n := d.uint64()
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.buf)) {
+		d.err = fmt.Errorf("%w: unexpected end of data", errorx.InvalidEncodingError)
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+// finish 检查数据是否恰好读完
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.buf) != 0 {
+		d.err = fmt.Errorf("%w: %d trailing bytes", errorx.InvalidEncodingError, len(d.buf))
+	}
+	return d.err
+}
+
+// hashOf 计算元素的 64 位哈希：字符串、字节切片与数值类型直接按内容编码，其它类型按 JSON 编码（失败时使用 %#v）
+// 哈希与进程无关，保证序列化后在其它进程中合并、查询的结果一致
+func hashOf(item any) uint64 {
+	var scratch [8]byte
+	var data []byte
+	switch v := item.(type) {
+	case string:
+		return hashString(v)
+	case []byte:
+		data = v
+	case int:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case int8:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case int16:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case int32:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case int64:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case uint:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case uint8:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case uint16:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case uint32:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], uint64(v))
+	case uint64:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], v)
+	case float32:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], math.Float64bits(float64(v)))
+	case float64:
+		data = binary.LittleEndian.AppendUint64(scratch[:0], math.Float64bits(v))
+	case bool:
+		if v {
+			data = []byte{1}
+		} else {
+			data = []byte{0}
+		}
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%#v", v))
+		}
+		data = encoded
+	}
+	return hashBytes(data)
+}
+
+// FNV-1a 参数
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func hashString(s string) uint64 {
+	h := uint64(fnvOffset)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return mix64(h)
+}
+
+func hashBytes(b []byte) uint64 {
+	h := uint64(fnvOffset)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime
+	}
+	return mix64(h)
+}
+
+// mix64 splitmix64 的终结函数，改善 FNV 低位的分布
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// doubleHash 由一个 64 位哈希派生两个哈希，用于 Kirsch-Mitzenmacher 双重哈希：h1 + i*h2
+func doubleHash(h uint64) (uint64, uint64) {
+	return h, mix64(h^0x9e3779b97f4a7c15) | 1
+}
@@ -0,0 +1,335 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+func TestBloom(t *testing.T) {
+	b, err := NewBloom[string](10000, 0.01)
+	if err != nil {
+		t.Fatalf("NewBloom returned error: %v", err)
+	}
+	for i := 0; i < 10000; i++ {
+		b.Add(fmt.Sprintf("user-%d", i))
+	}
+	for i := 0; i < 10000; i++ {
+		if !b.ApproxContains(fmt.Sprintf("user-%d", i)) {
+			t.Fatalf("Bloom filter must not have false negatives (user-%d)", i)
+		}
+	}
+	falsePositives := 0
+	for i := 10000; i < 20000; i++ {
+		if b.ApproxContains(fmt.Sprintf("user-%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 0.02 {
+		t.Errorf("False positive rate too high: %v", rate)
+	}
+	if estimated := b.FalsePositiveRate(); estimated <= 0 || estimated > 0.02 {
+		t.Errorf("Unexpected estimated false positive rate %v", estimated)
+	}
+
+	if !b.AddIfAbsent("new-user") || b.AddIfAbsent("new-user") {
+		t.Error("Unexpected AddIfAbsent result")
+	}
+
+	if _, err := NewBloom[int](0, 0.01); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+	if _, err := NewBloom[int](10, 1); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
+
+func TestBloomMergeAndBinary(t *testing.T) {
+	a := MustBloom[int](1000, 0.01)
+	b := MustBloom[int](1000, 0.01)
+	a.Add(1, 2, 3)
+	b.Add(4, 5)
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		if !a.ApproxContains(v) {
+			t.Errorf("Merged filter should contain %d", v)
+		}
+	}
+	if a.Count() != 5 {
+		t.Errorf("Expected count 5, got %d", a.Count())
+	}
+	if err := a.Merge(MustBloom[int](5000, 0.01)); !errors.Is(err, errorx.IncompatibleMergeError) {
+		t.Errorf("Expected IncompatibleMergeError, got %v", err)
+	}
+
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	var decoded Bloom[int]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	if !decoded.ApproxContains(4) || decoded.BitSize() != a.BitSize() || decoded.HashCount() != a.HashCount() {
+		t.Error("Decoded filter differs from the original")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-3]); !errors.Is(err, errorx.InvalidEncodingError) {
+		t.Errorf("Expected InvalidEncodingError for truncated data, got %v", err)
+	}
+	var hll HyperLogLog[int]
+	if err := hll.UnmarshalBinary(data); !errors.Is(err, errorx.InvalidEncodingError) {
+		t.Errorf("Expected InvalidEncodingError for wrong kind, got %v", err)
+	}
+
+	// 构造头部声明与实际数据不符的输入
+	crafted := func(m, k uint64, words int) []byte {
+		e := newEncoder(kindBloom, 24+8*words)
+		e.uint64(m)
+		e.uint64(k)
+		e.uint64(0)
+		for i := 0; i < words; i++ {
+			e.uint64(0)
+		}
+		return e.buf
+	}
+	for _, tc := range []struct {
+		name  string
+		m, k  uint64
+		words int
+	}{
+		{"huge m", math.MaxUint64, 3, 0},
+		{"huge m with words", math.MaxUint64 - 62, 3, 1},
+		{"zero m", 0, 3, 0},
+		{"extra words", 64, 3, 2},
+		{"zero k", 64, 0, 1},
+		{"too many k", 64, maxBloomHashes + 1, 1},
+	} {
+		if err := decoded.UnmarshalBinary(crafted(tc.m, tc.k, tc.words)); !errors.Is(err, errorx.InvalidEncodingError) {
+			t.Errorf("%s: expected InvalidEncodingError, got %v", tc.name, err)
+		}
+	}
+	if err := decoded.UnmarshalBinary(crafted(100, maxBloomHashes, 2)); err != nil {
+		t.Errorf("Valid crafted filter should decode, got %v", err)
+	}
+
+	if k := MustBloom[int](10, 1e-300).HashCount(); k > maxBloomHashes {
+		t.Errorf("Hash count should be capped at %d, got %d", maxBloomHashes, k)
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	h := MustHyperLogLog[int](14)
+	for i := 0; i < 100000; i++ {
+		h.Add(i % 50000)
+	}
+	if estimate := h.ApproxCountDistinct(); math.Abs(float64(estimate)-50000)/50000 > 0.03 {
+		t.Errorf("Estimate %d too far from 50000", estimate)
+	}
+
+	small := MustHyperLogLog[string](10)
+	small.Add("a", "b", "c", "a")
+	if estimate := small.ApproxCountDistinct(); estimate != 3 {
+		t.Errorf("Expected small cardinality 3, got %d", estimate)
+	}
+
+	// 两个有重叠的集合合并后估算并集
+	a, b := MustHyperLogLog[int](12), MustHyperLogLog[int](12)
+	for i := 0; i < 20000; i++ {
+		a.Add(i)
+		b.Add(i + 10000)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if estimate := a.ApproxCountDistinct(); math.Abs(float64(estimate)-30000)/30000 > 0.05 {
+		t.Errorf("Merged estimate %d too far from 30000", estimate)
+	}
+	if err := a.Merge(MustHyperLogLog[int](14)); !errors.Is(err, errorx.IncompatibleMergeError) {
+		t.Errorf("Expected IncompatibleMergeError, got %v", err)
+	}
+
+	data, _ := a.MarshalBinary()
+	var decoded HyperLogLog[int]
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded.ApproxCountDistinct() != a.ApproxCountDistinct() {
+		t.Errorf("Unexpected decoded estimate %d (%v)", decoded.ApproxCountDistinct(), err)
+	}
+
+	if _, err := NewHyperLogLog[int](3); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
+
+func TestCountMin(t *testing.T) {
+	c := MustCountMin[string](0.001, 0.01)
+	for i := 0; i < 1000; i++ {
+		c.Add(fmt.Sprintf("item-%d", i%100))
+	}
+	c.AddCount("hot", 5000)
+
+	if got := c.ApproxFrequency("hot"); got < 5000 || float64(got-5000) > 0.001*float64(c.Total()) {
+		t.Errorf("Unexpected frequency for hot item: %d", got)
+	}
+	for i := 0; i < 100; i++ {
+		if got := c.ApproxFrequency(fmt.Sprintf("item-%d", i)); got < 10 {
+			t.Fatalf("Count-Min must not underestimate, got %d", got)
+		}
+	}
+	if c.Total() != 6000 {
+		t.Errorf("Expected total 6000, got %d", c.Total())
+	}
+
+	other := MustCountMin[string](0.001, 0.01)
+	other.AddCount("hot", 10)
+	if err := c.Merge(other); err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if got := c.ApproxFrequency("hot"); got < 5010 {
+		t.Errorf("Merged frequency should include both sketches, got %d", got)
+	}
+	if err := c.Merge(MustCountMin[string](0.01, 0.01)); !errors.Is(err, errorx.IncompatibleMergeError) {
+		t.Errorf("Expected IncompatibleMergeError, got %v", err)
+	}
+
+	data, _ := c.MarshalBinary()
+	var decoded CountMin[string]
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded.ApproxFrequency("hot") != c.ApproxFrequency("hot") || decoded.Total() != c.Total() {
+		t.Errorf("Decoded sketch differs from the original (%v)", err)
+	}
+
+	if _, err := NewCountMin[string](0, 0.01); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	top := MustTopK[string](10)
+	// 5 个高频元素混在大量低频元素中
+	for i := 0; i < 5000; i++ {
+		top.Add(fmt.Sprintf("rare-%d", i))
+		if i%5 == 0 {
+			top.Add("a", "b", "c", "d", "e")
+		}
+		if i%2 == 0 {
+			top.Add("a")
+		}
+	}
+
+	hitters := top.Top(3).Values()
+	if len(hitters) != 3 || hitters[0].Item != "a" {
+		t.Fatalf("Unexpected top hitters %+v", hitters)
+	}
+	for _, h := range hitters {
+		if h.Count < h.Error {
+			t.Errorf("Count should not be smaller than error: %+v", h)
+		}
+	}
+	if count, ok := top.ApproxFrequency("a"); !ok || count < 3500 {
+		t.Errorf("Unexpected frequency for a: %d %v", count, ok)
+	}
+	if top.Total() != 5000+5000+2500 {
+		t.Errorf("Unexpected total %d", top.Total())
+	}
+
+	names := make(map[string]bool)
+	for _, h := range top.Top(5).Values() {
+		names[h.Item] = true
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		if !names[name] {
+			t.Errorf("Expected %s among the top 5, got %v", name, top.Top(5).Values())
+		}
+	}
+
+	if _, err := NewTopK[string](0); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
+
+func TestTopKMergeAndBinary(t *testing.T) {
+	a, b := MustTopK[int](3), MustTopK[int](3)
+	a.AddCount(1, 100)
+	a.AddCount(2, 50)
+	a.AddCount(3, 10)
+	b.AddCount(2, 80)
+	b.AddCount(4, 60)
+	b.AddCount(5, 5)
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	hitters := a.Top(0).Values()
+	if len(hitters) != 3 || hitters[0].Item != 2 || hitters[0].Count != 130 || hitters[1].Item != 1 || hitters[2].Item != 4 {
+		t.Errorf("Unexpected merged hitters %+v", hitters)
+	}
+	// 1 不在 b 中，按 b 的最小计数补齐
+	if hitters[1].Count != 105 || hitters[1].Error != 5 {
+		t.Errorf("Unexpected merged error bound %+v", hitters[1])
+	}
+	if a.Total() != 305 {
+		t.Errorf("Unexpected merged total %d", a.Total())
+	}
+	if err := a.Merge(MustTopK[int](4)); !errors.Is(err, errorx.IncompatibleMergeError) {
+		t.Errorf("Expected IncompatibleMergeError, got %v", err)
+	}
+
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	var decoded TopK[int]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	if fmt.Sprint(decoded.Top(0).Values()) != fmt.Sprint(hitters) || decoded.Capacity() != 3 {
+		t.Errorf("Decoded topk differs: %+v", decoded.Top(0).Values())
+	}
+	// 反序列化后仍可继续添加
+	decoded.AddCount(9, 1000)
+	if first := decoded.Top(1).First(); first.Item != 9 || first.Error == 0 {
+		t.Errorf("Unexpected hitter after eviction %+v", first)
+	}
+
+	// 重复的元素会破坏计数器与堆的对应关系
+	e := newEncoder(kindTopK, 0)
+	e.uint64(3)
+	e.uint64(20)
+	e.bytes([]byte(`{"hitters":[{"item":1,"count":10},{"item":1,"count":5}]}`))
+	if err := decoded.UnmarshalBinary(e.buf); !errors.Is(err, errorx.InvalidEncodingError) {
+		t.Errorf("Expected InvalidEncodingError for duplicated items, got %v", err)
+	}
+}
+
+func TestPopulate(t *testing.T) {
+	ids := slice_collcection.NewCollection([]int64{1, 2, 3, 2, 1})
+	if got := FromCollection(MustHyperLogLog[int64](10), ids).ApproxCountDistinct(); got != 3 {
+		t.Errorf("Expected 3 distinct ids, got %d", got)
+	}
+
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, s := range []string{"x", "y", "x"} {
+			ch <- s
+		}
+	}()
+	freq := FromStream(MustCountMin[string](0.01, 0.01), ch)
+	if freq.ApproxFrequency("x") != 2 || freq.Total() != 3 {
+		t.Errorf("Unexpected frequency %d / %d", freq.ApproxFrequency("x"), freq.Total())
+	}
+
+	type visit struct {
+		User string
+		Page string
+	}
+	seen := FromCollection(MustBloom[visit](100, 0.01), slice_collcection.NewCollection([]visit{{"u1", "/"}, {"u2", "/a"}}))
+	if !seen.ApproxContains(visit{"u1", "/"}) {
+		t.Error("Struct items should be hashed by content")
+	}
+}
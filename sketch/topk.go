@@ -0,0 +1,273 @@
+package sketch
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// HeavyHitter TopK 的统计结果，真实频次在 [Count-Error, Count] 之间
+type HeavyHitter[T comparable] struct {
+	Item  T      `json:"item"`
+	Count uint64 `json:"count"`
+	Error uint64 `json:"error"` // 最大高估量
+}
+
+// TopK 基于 Space-Saving 算法的高频元素统计，只保留 capacity 个计数器：
+// 频次超过 Total()/capacity 的元素一定会被保留，每个计数最多高估 Total()/capacity。
+// 需要准确的前 k 名时，capacity 通常取 k 的数倍。TopK 不是并发安全的
+type TopK[T comparable] struct {
+	capacity int
+	entries  map[T]*topKEntry[T]
+	heap     topKHeap[T] // 按计数的小顶堆，堆顶为淘汰候选
+	total    uint64
+}
+
+type topKEntry[T comparable] struct {
+	HeavyHitter[T]
+	index int // 在堆中的下标
+}
+
+// NewTopK 创建保留 capacity 个计数器的 TopK，capacity 需要大于 0，否则返回 errorx.InvalidArgumentError
+//
+// 使用示例：
+//
+//	hot, _ := sketch.NewTopK[string](1000)
+//	hot.Add(query)
+//	hot.Top(10) // 最热的 10 个查询
+func NewTopK[T comparable](capacity int) (*TopK[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("%w: capacity %d", errorx.InvalidArgumentError, capacity)
+	}
+	return &TopK[T]{capacity: capacity, entries: make(map[T]*topKEntry[T], capacity)}, nil
+}
+
+// MustTopK 与 NewTopK 相同，参数不合法时 panic
+func MustTopK[T comparable](capacity int) *TopK[T] {
+	t, err := NewTopK[T](capacity)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Add 添加元素，每个元素的频次加 1
+func (t *TopK[T]) Add(items ...T) {
+	for _, item := range items {
+		t.AddCount(item, 1)
+	}
+}
+
+// AddCount 元素的频次增加 n；计数器已满且元素不在其中时，替换计数最小的元素并继承其计数作为误差
+func (t *TopK[T]) AddCount(item T, n uint64) {
+	t.total += n
+	if e, ok := t.entries[item]; ok {
+		e.Count += n
+		heap.Fix(&t.heap, e.index)
+		return
+	}
+	if len(t.entries) < t.capacity {
+		e := &topKEntry[T]{HeavyHitter: HeavyHitter[T]{Item: item, Count: n}}
+		t.entries[item] = e
+		heap.Push(&t.heap, e)
+		return
+	}
+
+	e := t.heap[0]
+	delete(t.entries, e.Item)
+	e.Item, e.Error, e.Count = item, e.Count, e.Count+n
+	t.entries[item] = e
+	heap.Fix(&t.heap, 0)
+}
+
+// ApproxFrequency 返回元素的估计频次（上界）；元素不在计数器中时返回 false，此时真实频次不超过最小计数
+func (t *TopK[T]) ApproxFrequency(item T) (uint64, bool) {
+	if e, ok := t.entries[item]; ok {
+		return e.Count, true
+	}
+	return 0, false
+}
+
+// Top 返回计数最大的 n 个元素，按计数降序、误差升序排列；n 小于等于 0 时返回全部
+func (t *TopK[T]) Top(n int) *slice_collcection.Collection[HeavyHitter[T]] {
+	res := make([]HeavyHitter[T], 0, len(t.heap))
+	for _, e := range t.heap {
+		res = append(res, e.HeavyHitter)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Error < res[j].Error
+	})
+	if n > 0 && n < len(res) {
+		res = res[:n]
+	}
+	return slice_collcection.NewCollection(res)
+}
+
+// Total 返回所有元素的频次之和
+func (t *TopK[T]) Total() uint64 {
+	return t.total
+}
+
+// Capacity 返回计数器数量
+func (t *TopK[T]) Capacity() int {
+	return t.capacity
+}
+
+// Merge 合并另一个 TopK（可合并摘要）：一侧缺失的元素按该侧的最小计数补齐计数与误差，再保留计数最大的 capacity 个
+// 合并后的误差界与单个 TopK 处理两者全部数据时相同。capacity 不同时返回 errorx.IncompatibleMergeError
+func (t *TopK[T]) Merge(other *TopK[T]) error {
+	if t.capacity != other.capacity {
+		return fmt.Errorf("%w: topk capacity %d and %d", errorx.IncompatibleMergeError, t.capacity, other.capacity)
+	}
+
+	minA, minB := t.minCount(), other.minCount()
+	merged := make(map[T]HeavyHitter[T], len(t.entries)+len(other.entries))
+	for item, e := range t.entries {
+		h := e.HeavyHitter
+		if o, ok := other.entries[item]; ok {
+			h.Count += o.Count
+			h.Error += o.Error
+		} else {
+			h.Count += minB
+			h.Error += minB
+		}
+		merged[item] = h
+	}
+	for item, o := range other.entries {
+		if _, ok := t.entries[item]; !ok {
+			merged[item] = HeavyHitter[T]{Item: item, Count: o.Count + minA, Error: o.Error + minA}
+		}
+	}
+
+	hitters := make([]HeavyHitter[T], 0, len(merged))
+	for _, h := range merged {
+		hitters = append(hitters, h)
+	}
+	sort.Slice(hitters, func(i, j int) bool {
+		if hitters[i].Count != hitters[j].Count {
+			return hitters[i].Count > hitters[j].Count
+		}
+		return hitters[i].Error < hitters[j].Error
+	})
+	if len(hitters) > t.capacity {
+		hitters = hitters[:t.capacity]
+	}
+
+	t.reset(hitters)
+	t.total += other.total
+	return nil
+}
+
+// Clone 复制 TopK
+func (t *TopK[T]) Clone() *TopK[T] {
+	res := &TopK[T]{capacity: t.capacity, total: t.total}
+	res.reset(t.Top(0).Values())
+	return res
+}
+
+// topKPayload TopK 中元素的序列化结构，元素通过 encoding/json 编码
+type topKPayload[T comparable] struct {
+	Hitters []HeavyHitter[T] `json:"hitters"`
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler，元素使用 encoding/json 编码，需要能够 JSON 序列化
+func (t *TopK[T]) MarshalBinary() ([]byte, error) {
+	items, err := json.Marshal(topKPayload[T]{Hitters: t.Top(0).Values()})
+	if err != nil {
+		return nil, err
+	}
+	e := newEncoder(kindTopK, 24+len(items))
+	e.uint64(uint64(t.capacity))
+	e.uint64(t.total)
+	e.bytes(items)
+	return e.buf, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，数据格式错误或包含重复的元素时返回 errorx.InvalidEncodingError
+func (t *TopK[T]) UnmarshalBinary(data []byte) error {
+	d := newDecoder(kindTopK, data)
+	capacity, total := d.uint64(), d.uint64()
+	items := d.bytes()
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	var payload topKPayload[T]
+	if err := json.Unmarshal(items, &payload); err != nil {
+		return fmt.Errorf("%w: %v", errorx.InvalidEncodingError, err)
+	}
+	if capacity == 0 || capacity > math.MaxInt || uint64(len(payload.Hitters)) > capacity {
+		return fmt.Errorf("%w: topk with capacity %d and %d items", errorx.InvalidEncodingError, capacity, len(payload.Hitters))
+	}
+
+	seen := make(map[T]struct{}, len(payload.Hitters))
+	for _, h := range payload.Hitters {
+		if _, dup := seen[h.Item]; dup {
+			return fmt.Errorf("%w: duplicated topk item %v", errorx.InvalidEncodingError, h.Item)
+		}
+		seen[h.Item] = struct{}{}
+	}
+
+	t.capacity, t.total = int(capacity), total
+	t.reset(payload.Hitters)
+	return nil
+}
+
+// minCount 计数器已满时返回最小计数，未满时返回 0（未被记录的元素一定没有出现过）
+func (t *TopK[T]) minCount() uint64 {
+	if len(t.entries) < t.capacity || len(t.heap) == 0 {
+		return 0
+	}
+	return t.heap[0].Count
+}
+
+// reset 使用 hitters 重建计数器
+func (t *TopK[T]) reset(hitters []HeavyHitter[T]) {
+	t.entries = make(map[T]*topKEntry[T], len(hitters))
+	t.heap = make(topKHeap[T], 0, len(hitters))
+	for _, h := range hitters {
+		e := &topKEntry[T]{HeavyHitter: h, index: len(t.heap)}
+		t.entries[h.Item] = e
+		t.heap = append(t.heap, e)
+	}
+	heap.Init(&t.heap)
+}
+
+// topKHeap 按计数的小顶堆，计数相同时误差大的在前（优先淘汰）
+type topKHeap[T comparable] []*topKEntry[T]
+
+func (h topKHeap[T]) Len() int { return len(h) }
+
+func (h topKHeap[T]) Less(i, j int) bool {
+	if h[i].Count != h[j].Count {
+		return h[i].Count < h[j].Count
+	}
+	return h[i].Error > h[j].Error
+}
+
+func (h topKHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *topKHeap[T]) Push(x any) {
+	e := x.(*topKEntry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *topKHeap[T]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
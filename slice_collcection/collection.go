@@ -620,7 +620,7 @@ func (c *Collection[T]) SortBy(key string) (*Collection[T], error) {
 	}
 
 	// 使用闭包避免重复反射操作
-	lessFunc := utils.NewCompareFunc(field.Type.Kind())
+	lessFunc := utils.NewCompareFuncOf(field.Type)
 	if lessFunc == nil {
 		return c, errorx.KeyUnComparableError
 	}
@@ -704,9 +704,9 @@ func (c *Collection[T]) SortFloatBy(key string) (*Collection[T], error) {
 	return c, nil
 }
 
-// SortBy 按照某个字段进行排序
+// SortByFunc 按照自定义函数进行排序（稳定排序，相等的元素保持原有顺序）
 func (c *Collection[T]) SortByFunc(fn func(v1, v2 T) bool) (*Collection[T], error) {
-	sort.Slice(c.value, func(i, j int) bool {
+	sort.SliceStable(c.value, func(i, j int) bool {
 		return fn(c.value[i], c.value[j])
 	})
 
@@ -735,7 +735,7 @@ func (c *Collection[T]) SortByDesc(key string) (*Collection[T], error) {
 	}
 
 	// 使用闭包避免重复反射操作
-	lessFunc := utils.NewCompareFunc(field.Type.Kind())
+	lessFunc := utils.NewCompareFuncOf(field.Type)
 	if lessFunc == nil {
 		return c, errorx.KeyUnComparableError
 	}
@@ -759,6 +759,7 @@ func (c *Collection[T]) SortByDesc(key string) (*Collection[T], error) {
 }
 
 // KeyByStrField 根据某个字段为key，返回一个map,要求key对应的field是string
+// 需要任意类型的 key 或保持顺序时，使用 map_collection.KeyBy
 func (c *Collection[T]) KeyByStrField(key string) (map[string]T, error) {
 	res := make(map[string]T)
 	for _, v := range c.value {
@@ -1034,35 +1035,9 @@ func (c *Collection[T]) Avg() (float64, error) {
 // Median 获取中位值。
 // 中位数（Median）又称中值，统计学中的专有名词，是按顺序排列的一组数据中居于中间位置的数，代表一个样本、种群或概率分布中的一个数值，其可将数值集合划分为相等的上下两部分。
 // 对于有限的数集，可以通过把所有观察值高低排序后找出正中间的一个作为中位数。如果观察值有偶数个，通常取最中间的两个数值的平均数作为中位数。
+// 使用快速选择而不是完整排序，不修改原 Collection
 func (c *Collection[T]) Median() (float64, error) {
-	if !c.isComputable() {
-		return 0.0, errorx.NoComputableError
-	}
-
-	if c.IsEmpty() {
-		return 0.0, nil
-	}
-
-	// 修复副作用:先复制集合再排序,避免修改原集合
-	coll := c.Copy()
-	_, err := coll.Sort()
-	if err != nil {
-		return 0.0, err
-	}
-
-	// 直接计算中位数,避免创建不必要的新集合
-	length := len(coll.value)
-	if length%2 == 0 {
-		// 偶数个元素:取中间两个的平均值
-		mid1 := coll.Index(length/2 - 1)
-		mid2 := coll.Index(length / 2)
-		tempColl := NewCollection([]T{mid1, mid2})
-		return tempColl.Avg()
-	}
-	// 奇数个元素:取中间元素
-	mid := coll.Index(length / 2)
-	tempColl := NewCollection([]T{mid})
-	return tempColl.Avg()
+	return c.Percentile(50)
 }
 
 // Mode 获取Mode值，众数，一组数据中出现最多的
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewCollection(t *testing.T) {
@@ -1117,6 +1118,69 @@ func TestSortByDescPtr(t *testing.T) {
 	}
 }
 
+// TestSortNamedTypes tests sorting strings and named types whose underlying type is basic
+func TestSortNamedTypes(t *testing.T) {
+	type ID int
+	type Code string
+
+	ids, err := NewCollection([]ID{3, 1, 2}).Sort()
+	if err != nil {
+		t.Fatalf("Sort returned an error, %v", err)
+	}
+	if !reflect.DeepEqual(ids.value, []ID{1, 2, 3}) {
+		t.Errorf("Sort did not order named ints correctly: %v", ids.value)
+	}
+
+	codes, err := NewCollection([]Code{"b", "c", "a"}).Sort()
+	if err != nil {
+		t.Fatalf("Sort returned an error, %v", err)
+	}
+	if !reflect.DeepEqual(codes.value, []Code{"a", "b", "c"}) {
+		t.Errorf("Sort did not order named strings correctly: %v", codes.value)
+	}
+
+	if max, err := NewCollection([]string{"pear", "apple", "zebra"}).Max(); err != nil || max != "zebra" {
+		t.Errorf("Max of strings returned %v, %v", max, err)
+	}
+
+	type Item struct {
+		Name string
+		ID   ID
+	}
+	items, err := NewCollection([]Item{{"b", 2}, {"c", 3}, {"a", 1}}).SortBy("ID")
+	if err != nil {
+		t.Fatalf("SortBy returned an error, %v", err)
+	}
+	if items.Index(0).Name != "a" || items.Index(2).Name != "c" {
+		t.Errorf("SortBy did not order named int fields correctly: %v", items.value)
+	}
+}
+
+// TestSortByTime tests sorting by time.Time values and fields
+func TestSortByTime(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	times, err := NewCollection([]time.Time{day(3), day(1), day(2)}).Sort()
+	if err != nil {
+		t.Fatalf("Sort returned an error, %v", err)
+	}
+	if !reflect.DeepEqual(times.value, []time.Time{day(1), day(2), day(3)}) {
+		t.Errorf("Sort did not order times correctly: %v", times.value)
+	}
+
+	type Event struct {
+		Name string
+		At   time.Time
+	}
+	events, err := NewCollection([]Event{{"b", day(2)}, {"c", day(3)}, {"a", day(1)}}).SortByDesc("At")
+	if err != nil {
+		t.Fatalf("SortByDesc returned an error, %v", err)
+	}
+	if events.Index(0).Name != "c" || events.Index(1).Name != "b" || events.Index(2).Name != "a" {
+		t.Errorf("SortByDesc did not order time fields correctly: %v", events.value)
+	}
+}
+
 // TestKeyByStrField tests the KeyByStrField method of the Collection struct
 func TestKeyByStrField(t *testing.T) {
 	// create a new Collection with some elements
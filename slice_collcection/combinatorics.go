@@ -0,0 +1,430 @@
+package slice_collcection
+
+import (
+	"math"
+	"math/big"
+)
+
+// Pair 两个不同类型元素组成的二元组
+type Pair[A any, B any] struct {
+	First  A `json:"first"`
+	Second B `json:"second"`
+}
+
+// Triple 三个不同类型元素组成的三元组
+type Triple[A any, B any, C any] struct {
+	First  A `json:"first"`
+	Second B `json:"second"`
+	Third  C `json:"third"`
+}
+
+// Zip 按下标将两个 Collection 的元素配对，长度以较短的一侧为准
+//
+// 使用示例：
+//
+//	// [1 2 3] 与 ["a" "b"] => {1 a}, {2 b}
+//	pairs := slice_collcection.Zip(ids, names)
+func Zip[A any, B any](a *Collection[A], b *Collection[B]) *Collection[Pair[A, B]] {
+	return ZipWith(a, b, func(x A, y B) Pair[A, B] {
+		return Pair[A, B]{First: x, Second: y}
+	})
+}
+
+// Zip3 按下标将三个 Collection 的元素组成三元组，长度以最短的一个为准
+func Zip3[A any, B any, C any](a *Collection[A], b *Collection[B], c *Collection[C]) *Collection[Triple[A, B, C]] {
+	av, bv, cv := valuesOf(a), valuesOf(b), valuesOf(c)
+	n := min(len(av), len(bv), len(cv))
+	res := make([]Triple[A, B, C], 0, n)
+	for i := 0; i < n; i++ {
+		res = append(res, Triple[A, B, C]{First: av[i], Second: bv[i], Third: cv[i]})
+	}
+	return NewCollection(res)
+}
+
+// ZipWith 按下标使用 fn 合并两个 Collection 的元素，长度以较短的一侧为准
+func ZipWith[A any, B any, R any](a *Collection[A], b *Collection[B], fn func(x A, y B) R) *Collection[R] {
+	av, bv := valuesOf(a), valuesOf(b)
+	n := min(len(av), len(bv))
+	res := make([]R, 0, n)
+	for i := 0; i < n; i++ {
+		res = append(res, fn(av[i], bv[i]))
+	}
+	return NewCollection(res)
+}
+
+// Unzip 将二元组拆分为两个 Collection，是 Zip 的逆操作
+func Unzip[A any, B any](c *Collection[Pair[A, B]]) (*Collection[A], *Collection[B]) {
+	pairs := valuesOf(c)
+	first := make([]A, 0, len(pairs))
+	second := make([]B, 0, len(pairs))
+	for _, p := range pairs {
+		first = append(first, p.First)
+		second = append(second, p.Second)
+	}
+	return NewCollection(first), NewCollection(second)
+}
+
+// Interleave 轮流从每个 Collection 中取一个元素合并为一个 Collection，较短的取完后跳过
+//
+// 使用示例：
+//
+//	// [1 2 3] [10] [20 30] => [1 10 20 2 30 3]
+//	merged := slice_collcection.Interleave(a, b, c)
+func Interleave[T any](cs ...*Collection[T]) *Collection[T] {
+	pools := make([][]T, len(cs))
+	total, longest := 0, 0
+	for i, c := range cs {
+		pools[i] = valuesOf(c)
+		total += len(pools[i])
+		longest = max(longest, len(pools[i]))
+	}
+	res := make([]T, 0, total)
+	for i := 0; i < longest; i++ {
+		for _, pool := range pools {
+			if i < len(pool) {
+				res = append(res, pool[i])
+			}
+		}
+	}
+	return NewCollection(res)
+}
+
+// Transpose 转置 Collection 组成的二维结构，结果的第 i 行由每一行的第 i 个元素组成
+// 各行长度不一致时，较短的行在超出部分直接跳过，因此结果的行可能短于输入的行数
+//
+// 使用示例：
+//
+//	// [[1 2 3] [4 5 6]] => [[1 4] [2 5] [3 6]]
+//	columns := slice_collcection.Transpose(rows)
+func Transpose[T any](c *Collection[*Collection[T]]) *Collection[*Collection[T]] {
+	rows := make([][]T, 0)
+	longest := 0
+	for _, row := range valuesOf(c) {
+		values := valuesOf(row)
+		rows = append(rows, values)
+		longest = max(longest, len(values))
+	}
+	res := make([]*Collection[T], 0, longest)
+	for i := 0; i < longest; i++ {
+		column := make([]T, 0, len(rows))
+		for _, row := range rows {
+			if i < len(row) {
+				column = append(column, row[i])
+			}
+		}
+		res = append(res, NewCollection(column))
+	}
+	return NewCollection(res)
+}
+
+// Iterator 惰性生成的组合序列，只保存当前结果对应的下标，不会一次性生成整个结果空间
+// 按 Next 推进、Value 读取当前结果；Iterator 不是并发安全的
+//
+// 使用示例：
+//
+//	it := slice_collcection.Combinations(players, 2)
+//	for it.Next() {
+//	    fmt.Println(it.Value())
+//	}
+type Iterator[T any] struct {
+	pools   [][]T                // 每个位置可选的元素
+	indices []int                // 当前结果在各位置选中的下标
+	step    func(idx []int) bool // 推进下标，返回 false 表示已经结束
+	total   func() *big.Int      // 结果总数
+	empty   bool                 // 结果空间为空
+	started bool
+	done    bool
+}
+
+// Combinations 按下标的字典序惰性生成从 c 中选取 k 个元素的所有组合（不考虑顺序），共 C(n, k) 个
+// 元素按下标区分，相等的元素也会产生重复的组合；k 为 0 时生成一个空组合，k 小于 0 或大于元素数量时没有结果
+func Combinations[T any](c *Collection[T], k int) *Iterator[T] {
+	values := append(make([]T, 0), valuesOf(c)...)
+	n := len(values)
+	it := &Iterator[T]{
+		empty: k < 0 || k > n,
+		total: func() *big.Int {
+			return new(big.Int).Binomial(int64(n), int64(k))
+		},
+	}
+	if it.empty {
+		return it
+	}
+	it.pools = make([][]T, k)
+	it.indices = make([]int, k)
+	for i := range it.indices {
+		it.pools[i] = values
+		it.indices[i] = i
+	}
+	it.step = func(idx []int) bool {
+		// 找到最右侧还能增大的位置，其后的位置依次紧随
+		i := k - 1
+		for i >= 0 && idx[i] == i+n-k {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+		return true
+	}
+	return it
+}
+
+// Permutations 按下标的字典序惰性生成 c 中所有元素的全排列，共 n! 个
+// 元素按下标区分，相等的元素也会产生重复的排列；c 为空时生成一个空排列
+func Permutations[T any](c *Collection[T]) *Iterator[T] {
+	values := append(make([]T, 0), valuesOf(c)...)
+	n := len(values)
+	it := &Iterator[T]{
+		pools:   make([][]T, n),
+		indices: make([]int, n),
+		total: func() *big.Int {
+			return new(big.Int).MulRange(1, int64(n))
+		},
+	}
+	for i := range it.indices {
+		it.pools[i] = values
+		it.indices[i] = i
+	}
+	it.step = func(idx []int) bool {
+		// 标准的 next permutation：找到最右侧的升序对，与其后最小的较大值交换后反转尾部
+		i := n - 2
+		for i >= 0 && idx[i] >= idx[i+1] {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		j := n - 1
+		for idx[j] <= idx[i] {
+			j--
+		}
+		idx[i], idx[j] = idx[j], idx[i]
+		for l, r := i+1, n-1; l < r; l, r = l+1, r-1 {
+			idx[l], idx[r] = idx[r], idx[l]
+		}
+		return true
+	}
+	return it
+}
+
+// CartesianProduct 惰性生成多个同类型 Collection 的笛卡尔积，每个结果依次包含每个 Collection 中的一个元素，
+// 最后一个 Collection 变化最快；任一 Collection 为空时没有结果，不传入 Collection 时生成一个空结果
+// 元素类型不同时使用 CartesianProduct2 或 CartesianProduct3
+//
+// 使用示例：
+//
+//	// 按 x、y、z 三个坐标轴的取值展开网格点
+//	points := slice_collcection.CartesianProduct(xs, ys, zs).Collect()
+func CartesianProduct[T any](cs ...*Collection[T]) *Iterator[T] {
+	it := &Iterator[T]{
+		pools:   make([][]T, len(cs)),
+		indices: make([]int, len(cs)),
+	}
+	it.total = func() *big.Int {
+		total := big.NewInt(1)
+		for _, pool := range it.pools {
+			total.Mul(total, big.NewInt(int64(len(pool))))
+		}
+		return total
+	}
+	for i, c := range cs {
+		it.pools[i] = append(make([]T, 0), valuesOf(c)...)
+		if len(it.pools[i]) == 0 {
+			it.empty = true
+		}
+	}
+	it.step = func(idx []int) bool {
+		// 里程表式进位
+		for i := len(idx) - 1; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < len(it.pools[i]) {
+				return true
+			}
+			idx[i] = 0
+		}
+		return false
+	}
+	return it
+}
+
+// ProductIterator 惰性生成的不同类型 Collection 的笛卡尔积，结果为 Pair 或 Triple
+// 与 Iterator 一样只保存当前结果对应的下标；ProductIterator 不是并发安全的
+type ProductIterator[R any] struct {
+	it    *Iterator[int]    // 各位置下标的笛卡尔积
+	value func(idx []int) R // 根据下标组装结果
+}
+
+// CartesianProduct2 惰性生成两个不同类型 Collection 的笛卡尔积，b 变化最快；任一 Collection 为空时没有结果
+//
+// 使用示例：
+//
+//	// 商品 × 数量 展开为报价单
+//	quotes := slice_collcection.CartesianProduct2(products, quantities).Collect()
+func CartesianProduct2[A any, B any](a *Collection[A], b *Collection[B]) *ProductIterator[Pair[A, B]] {
+	av, bv := append(make([]A, 0), valuesOf(a)...), append(make([]B, 0), valuesOf(b)...)
+	return &ProductIterator[Pair[A, B]]{
+		it: indexProduct(len(av), len(bv)),
+		value: func(idx []int) Pair[A, B] {
+			return Pair[A, B]{First: av[idx[0]], Second: bv[idx[1]]}
+		},
+	}
+}
+
+// CartesianProduct3 惰性生成三个不同类型 Collection 的笛卡尔积，c 变化最快；任一 Collection 为空时没有结果
+//
+// 使用示例：
+//
+//	// 尺码 × 颜色 × 材质 展开为 SKU
+//	variants := slice_collcection.CartesianProduct3(sizes, colors, materials).Collect()
+func CartesianProduct3[A any, B any, C any](a *Collection[A], b *Collection[B], c *Collection[C]) *ProductIterator[Triple[A, B, C]] {
+	av, bv, cv := append(make([]A, 0), valuesOf(a)...), append(make([]B, 0), valuesOf(b)...), append(make([]C, 0), valuesOf(c)...)
+	return &ProductIterator[Triple[A, B, C]]{
+		it: indexProduct(len(av), len(bv), len(cv)),
+		value: func(idx []int) Triple[A, B, C] {
+			return Triple[A, B, C]{First: av[idx[0]], Second: bv[idx[1]], Third: cv[idx[2]]}
+		},
+	}
+}
+
+// indexProduct 生成各位置下标 [0, n) 的笛卡尔积
+func indexProduct(sizes ...int) *Iterator[int] {
+	cs := make([]*Collection[int], len(sizes))
+	for i, n := range sizes {
+		idx := make([]int, n)
+		for j := range idx {
+			idx[j] = j
+		}
+		cs[i] = NewCollection(idx)
+	}
+	return CartesianProduct(cs...)
+}
+
+// Next 推进到下一个结果，没有更多结果时返回 false
+func (it *ProductIterator[R]) Next() bool {
+	return it.it.Next()
+}
+
+// Value 返回当前结果，在第一次 Next 之前或结束之后返回零值
+func (it *ProductIterator[R]) Value() R {
+	idx := it.it.Value()
+	if idx == nil {
+		var zero R
+		return zero
+	}
+	return it.value(idx)
+}
+
+// Each 从当前位置开始遍历剩余的结果，fn 返回 false 时终止
+func (it *ProductIterator[R]) Each(fn func(item R) bool) {
+	for it.Next() {
+		if !fn(it.Value()) {
+			return
+		}
+	}
+}
+
+// Take 取出接下来的最多 n 个结果
+func (it *ProductIterator[R]) Take(n int) *Collection[R] {
+	res := make([]R, 0, max(n, 0))
+	for len(res) < n && it.Next() {
+		res = append(res, it.Value())
+	}
+	return NewCollection(res)
+}
+
+// Collect 取出剩余的所有结果，结果空间很大时请先通过 Total 确认数量
+func (it *ProductIterator[R]) Collect() *Collection[R] {
+	res := make([]R, 0)
+	it.Each(func(item R) bool {
+		res = append(res, item)
+		return true
+	})
+	return NewCollection(res)
+}
+
+// Total 返回结果的总数（与当前遍历位置无关），超出 int 范围时返回 false
+func (it *ProductIterator[R]) Total() (int, bool) {
+	return it.it.Total()
+}
+
+// Next 推进到下一个结果，没有更多结果时返回 false
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		it.done = it.empty
+		return !it.done
+	}
+	if !it.step(it.indices) {
+		it.done = true
+		return false
+	}
+	return true
+}
+
+// Value 返回当前结果，每次调用都返回新的切片，可以直接保存；在第一次 Next 之前或结束之后返回 nil
+func (it *Iterator[T]) Value() []T {
+	if !it.started || it.done {
+		return nil
+	}
+	res := make([]T, len(it.indices))
+	for i, idx := range it.indices {
+		res[i] = it.pools[i][idx]
+	}
+	return res
+}
+
+// Each 从当前位置开始遍历剩余的结果，fn 返回 false 时终止
+func (it *Iterator[T]) Each(fn func(item []T) bool) {
+	for it.Next() {
+		if !fn(it.Value()) {
+			return
+		}
+	}
+}
+
+// Take 取出接下来的最多 n 个结果
+func (it *Iterator[T]) Take(n int) *Collection[[]T] {
+	res := make([][]T, 0, max(n, 0))
+	for len(res) < n && it.Next() {
+		res = append(res, it.Value())
+	}
+	return NewCollection(res)
+}
+
+// Collect 取出剩余的所有结果，结果空间很大时请先通过 Total 确认数量
+func (it *Iterator[T]) Collect() *Collection[[]T] {
+	res := make([][]T, 0)
+	it.Each(func(item []T) bool {
+		res = append(res, item)
+		return true
+	})
+	return NewCollection(res)
+}
+
+// Total 返回结果的总数（与当前遍历位置无关），超出 int 范围时返回 false
+func (it *Iterator[T]) Total() (int, bool) {
+	if it.empty {
+		return 0, true
+	}
+	total := it.total()
+	if !total.IsInt64() || total.Int64() > math.MaxInt {
+		return 0, false
+	}
+	return int(total.Int64()), true
+}
+
+// valuesOf 返回 c 中的元素，c 为 nil 时视为空 Collection
+func valuesOf[T any](c *Collection[T]) []T {
+	if c == nil {
+		return nil
+	}
+	return c.value
+}
@@ -0,0 +1,209 @@
+package slice_collcection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestZipUnzip(t *testing.T) {
+	ids := NewCollection([]int{1, 2, 3})
+	names := NewCollection([]string{"a", "b"})
+
+	pairs := Zip(ids, names)
+	expected := []Pair[int, string]{{1, "a"}, {2, "b"}}
+	if !reflect.DeepEqual(pairs.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, pairs.Values())
+	}
+
+	first, second := Unzip(pairs)
+	if !reflect.DeepEqual(first.Values(), []int{1, 2}) || !reflect.DeepEqual(second.Values(), []string{"a", "b"}) {
+		t.Errorf("Unexpected unzip result %v %v", first.Values(), second.Values())
+	}
+
+	triples := Zip3(ids, names, NewCollection([]bool{true, false, true}))
+	if triples.Count() != 2 || triples.Values()[1] != (Triple[int, string, bool]{2, "b", false}) {
+		t.Errorf("Unexpected zip3 result %v", triples.Values())
+	}
+
+	labels := ZipWith(ids, names, func(id int, name string) string {
+		return name + string(rune('0'+id))
+	})
+	if !reflect.DeepEqual(labels.Values(), []string{"a1", "b2"}) {
+		t.Errorf("Unexpected zipWith result %v", labels.Values())
+	}
+}
+
+func TestInterleaveTranspose(t *testing.T) {
+	merged := Interleave(NewCollection([]int{1, 2, 3}), NewCollection([]int{10}), NewCollection([]int{20, 30}))
+	if !reflect.DeepEqual(merged.Values(), []int{1, 10, 20, 2, 30, 3}) {
+		t.Errorf("Unexpected interleave result %v", merged.Values())
+	}
+
+	rows := NewCollection([]*Collection[int]{
+		NewCollection([]int{1, 2, 3}),
+		NewCollection([]int{4, 5}),
+	})
+	columns := make([][]int, 0)
+	for _, c := range Transpose(rows).Values() {
+		columns = append(columns, c.Values())
+	}
+	if !reflect.DeepEqual(columns, [][]int{{1, 4}, {2, 5}, {3}}) {
+		t.Errorf("Unexpected transpose result %v", columns)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	it := Combinations(NewCollection([]string{"a", "b", "c", "d"}), 2)
+	if total, ok := it.Total(); !ok || total != 6 {
+		t.Errorf("Expected 6 combinations, got %d", total)
+	}
+	if it.Value() != nil {
+		t.Error("Value before Next should be nil")
+	}
+	first := it.Take(2).Values()
+	if !reflect.DeepEqual(first, [][]string{{"a", "b"}, {"a", "c"}}) {
+		t.Errorf("Unexpected first combinations %v", first)
+	}
+	rest := it.Collect().Values()
+	if !reflect.DeepEqual(rest, [][]string{{"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}}) {
+		t.Errorf("Unexpected remaining combinations %v", rest)
+	}
+	if it.Next() || it.Value() != nil {
+		t.Error("Exhausted iterator should stay exhausted")
+	}
+
+	if got := Combinations(NewCollection([]int{1, 2}), 0).Collect().Values(); !reflect.DeepEqual(got, [][]int{{}}) {
+		t.Errorf("Expected one empty combination, got %v", got)
+	}
+	if got := Combinations(NewCollection([]int{1, 2}), 3).Collect(); got.IsNotEmpty() {
+		t.Errorf("Expected no combinations, got %v", got.Values())
+	}
+
+	// 只生成需要的部分，不会展开整个结果空间
+	large := Combinations(NewCollection(make([]int, 100)), 50)
+	if _, ok := large.Total(); ok {
+		t.Error("C(100, 50) should overflow int")
+	}
+	if large.Take(3).Count() != 3 {
+		t.Error("Expected 3 combinations from a large space")
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	it := Permutations(NewCollection([]int{1, 2, 3}))
+	if total, _ := it.Total(); total != 6 {
+		t.Errorf("Expected 6 permutations, got %d", total)
+	}
+	expected := [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}}
+	if got := it.Collect().Values(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	count := 0
+	Permutations(NewCollection([]int{1, 2, 3, 4, 5})).Each(func(item []int) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Errorf("Each should stop when fn returns false, got %d", count)
+	}
+	if got := Permutations(NewCollection([]int{})).Collect().Values(); !reflect.DeepEqual(got, [][]int{{}}) {
+		t.Errorf("Expected one empty permutation, got %v", got)
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	sizes := NewCollection([]string{"S", "M"})
+	colors := NewCollection([]string{"red", "blue"})
+	materials := NewCollection([]string{"cotton"})
+
+	it := CartesianProduct(sizes, colors, materials)
+	if total, _ := it.Total(); total != 4 {
+		t.Errorf("Expected 4 variants, got %d", total)
+	}
+	expected := [][]string{
+		{"S", "red", "cotton"},
+		{"S", "blue", "cotton"},
+		{"M", "red", "cotton"},
+		{"M", "blue", "cotton"},
+	}
+	if got := it.Collect().Values(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// 创建后修改原 Collection 不影响结果
+	sizes.Append("L")
+	if total, _ := it.Total(); total != 4 {
+		t.Errorf("Iterator should snapshot its input, got %d", total)
+	}
+
+	empty := CartesianProduct(sizes, NewCollection([]string{}))
+	if total, _ := empty.Total(); total != 0 || empty.Next() {
+		t.Error("Product with an empty collection should have no results")
+	}
+}
+
+// TestCartesianProductTyped tests the CartesianProduct2 and CartesianProduct3 functions with different element types
+func TestCartesianProductTyped(t *testing.T) {
+	sizes := NewCollection([]string{"S", "M"})
+	prices := NewCollection([]float64{9.9, 19.9})
+	stock := NewCollection([]bool{true})
+
+	it := CartesianProduct3(sizes, prices, stock)
+	if total, _ := it.Total(); total != 4 {
+		t.Errorf("Expected 4 variants, got %d", total)
+	}
+	if it.Value() != (Triple[string, float64, bool]{}) {
+		t.Error("Value before Next should be the zero value")
+	}
+	if got := it.Take(1).Values(); !reflect.DeepEqual(got, []Triple[string, float64, bool]{{"S", 9.9, true}}) {
+		t.Errorf("Unexpected first variant %v", got)
+	}
+	expected := []Triple[string, float64, bool]{{"S", 19.9, true}, {"M", 9.9, true}, {"M", 19.9, true}}
+	if got := it.Collect().Values(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if it.Next() {
+		t.Error("Exhausted iterator should stay exhausted")
+	}
+
+	pairs := CartesianProduct2(NewCollection([]int{1, 2}), NewCollection([]string{"a"}))
+	if got := pairs.Collect().Values(); !reflect.DeepEqual(got, []Pair[int, string]{{1, "a"}, {2, "a"}}) {
+		t.Errorf("Unexpected pairs %v", got)
+	}
+	if empty := CartesianProduct2(sizes, NewCollection([]int{})); empty.Next() {
+		t.Error("Product with an empty collection should have no results")
+	}
+}
+
+// TestCombinatoricsNilCollection tests that the zip and combinatorics functions treat a nil Collection as empty
+func TestCombinatoricsNilCollection(t *testing.T) {
+	var none *Collection[int]
+	names := NewCollection([]string{"a"})
+
+	if Zip(none, names).Count() != 0 || Zip3(none, names, names).Count() != 0 {
+		t.Error("Zip with a nil collection should be empty")
+	}
+	if ZipWith(names, none, func(x string, y int) string { return x }).Count() != 0 {
+		t.Error("ZipWith with a nil collection should be empty")
+	}
+	first, second := Unzip[int, string](nil)
+	if first.Count() != 0 || second.Count() != 0 {
+		t.Error("Unzip of a nil collection should be empty")
+	}
+	if got := Interleave(none, NewCollection([]int{1, 2})).Values(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Unexpected interleave result %v", got)
+	}
+	if Transpose[int](nil).Count() != 0 {
+		t.Error("Transpose of a nil collection should be empty")
+	}
+	if got := Transpose(NewCollection([]*Collection[int]{nil, NewCollection([]int{1})})).Count(); got != 1 {
+		t.Errorf("Nil rows should be skipped, got %d columns", got)
+	}
+	if got := Combinations(none, 0).Collect().Values(); !reflect.DeepEqual(got, [][]int{{}}) {
+		t.Errorf("Expected one empty combination, got %v", got)
+	}
+	if Permutations(none).Collect().Count() != 1 || CartesianProduct(none).Next() || CartesianProduct2(none, names).Next() {
+		t.Error("Nil collections should behave as empty ones")
+	}
+}
@@ -0,0 +1,228 @@
+package slice_collcection
+
+import (
+	"reflect"
+
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// Deque 基于环形缓冲区的双端队列，两端的入队与出队均为 O(1)（扩容时均摊），支持按下标访问
+// 使用 NewBoundedDeque 创建的固定容量 Deque 在满时覆盖另一端最旧的元素，适合保存“最近 N 条”记录。
+// Deque 不是并发安全的
+type Deque[T any] struct {
+	buf         []T
+	head        int // 第一个元素在 buf 中的位置
+	size        int
+	bounded     bool                   // 固定容量，满时覆盖
+	compareFunc func(a any, b any) int // ToCollection 时设置到 Collection 上
+}
+
+// minDequeCapacity 可扩容 Deque 的初始容量
+const minDequeCapacity = 8
+
+// NewDeque 创建可扩容的双端队列，values 依次从尾部入队
+func NewDeque[T any](values ...T) *Deque[T] {
+	var zero T
+	d := &Deque[T]{buf: make([]T, max(len(values), minDequeCapacity))}
+	d.size = copy(d.buf, values)
+	d.compareFunc = utils.NewCompareFuncOf(reflect.TypeOf(zero))
+
+	return d
+}
+
+// NewBoundedDeque 创建固定容量的双端队列，容量小于 1 时为 1
+// 满时 PushBack 覆盖最前面的元素，PushFront 覆盖最后面的元素
+//
+// 使用示例：
+//
+//	recent := slice_collcection.NewBoundedDeque[Event](100)
+//	recent.PushBack(e) // 只保留最近 100 条
+func NewBoundedDeque[T any](capacity int) *Deque[T] {
+	d := NewDeque[T]()
+	d.buf = make([]T, max(capacity, 1))
+	d.bounded = true
+
+	return d
+}
+
+// NewDequeFrom 使用 Collection 的元素与比较函数创建可扩容的双端队列，复制一次元素，不修改原 Collection
+func NewDequeFrom[T any](c *Collection[T]) *Deque[T] {
+	d := NewDeque(c.value...)
+	d.compareFunc = c.compareFunc
+
+	return d
+}
+
+// PushBack 从尾部入队
+func (d *Deque[T]) PushBack(item T) *Deque[T] {
+	if d.size == len(d.buf) {
+		if d.bounded {
+			d.buf[d.head] = item
+			d.head = d.wrap(d.head + 1)
+			return d
+		}
+		d.grow()
+	}
+	d.buf[d.wrap(d.head+d.size)] = item
+	d.size++
+
+	return d
+}
+
+// PushFront 从头部入队
+func (d *Deque[T]) PushFront(item T) *Deque[T] {
+	if d.size == len(d.buf) {
+		if d.bounded {
+			d.head = d.wrap(d.head - 1 + len(d.buf))
+			d.buf[d.head] = item
+			return d
+		}
+		d.grow()
+	}
+	d.head = d.wrap(d.head - 1 + len(d.buf))
+	d.buf[d.head] = item
+	d.size++
+
+	return d
+}
+
+// PopFront 从头部出队，队列为空时返回 false
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	item := d.buf[d.head]
+	d.buf[d.head] = zero // 释放引用
+	d.head = d.wrap(d.head + 1)
+	d.size--
+
+	return item, true
+}
+
+// PopBack 从尾部出队，队列为空时返回 false
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := d.wrap(d.head + d.size - 1)
+	item := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+
+	return item, true
+}
+
+// Front 返回第一个元素，队列为空时返回 false
+func (d *Deque[T]) Front() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.head], true
+}
+
+// Back 返回最后一个元素，队列为空时返回 false
+func (d *Deque[T]) Back() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.wrap(d.head+d.size-1)], true
+}
+
+// Index 获取某个下标（从头部开始计数），越界时返回零值
+func (d *Deque[T]) Index(i int) T {
+	var zero T
+	if i < 0 || i >= d.size {
+		return zero
+	}
+	return d.buf[d.wrap(d.head+i)]
+}
+
+// SetIndex 设置某个下标，越界时不做修改
+func (d *Deque[T]) SetIndex(i int, item T) *Deque[T] {
+	if i >= 0 && i < d.size {
+		d.buf[d.wrap(d.head+i)] = item
+	}
+	return d
+}
+
+// Count 获取元素数量
+func (d *Deque[T]) Count() int {
+	return d.size
+}
+
+// Cap 获取当前容量，固定容量的 Deque 为创建时的容量
+func (d *Deque[T]) Cap() int {
+	return len(d.buf)
+}
+
+// IsEmpty 是否为空
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// IsFull 固定容量的 Deque 是否已满，可扩容的 Deque 始终返回 false
+func (d *Deque[T]) IsFull() bool {
+	return d.bounded && d.size == len(d.buf)
+}
+
+// Clear 清空元素，保留容量
+func (d *Deque[T]) Clear() *Deque[T] {
+	clear(d.buf)
+	d.head, d.size = 0, 0
+	return d
+}
+
+// Each 从头到尾遍历，当 f 返回 false 时终止
+func (d *Deque[T]) Each(f func(item T, key int) bool) {
+	for i := 0; i < d.size; i++ {
+		if !f(d.buf[d.wrap(d.head+i)], i) {
+			return
+		}
+	}
+}
+
+// Foreach 从头到尾遍历所有元素
+func (d *Deque[T]) Foreach(f func(item T, key int)) {
+	for i := 0; i < d.size; i++ {
+		f(d.buf[d.wrap(d.head+i)], i)
+	}
+}
+
+// Values 按从头到尾的顺序返回元素的副本
+func (d *Deque[T]) Values() []T {
+	values := make([]T, d.size)
+	// 环形缓冲区最多分为两段，各复制一次
+	n := copy(values, d.buf[d.head:min(d.head+d.size, len(d.buf))])
+	copy(values[n:], d.buf[:d.size-n])
+
+	return values
+}
+
+// ToCollection 按从头到尾的顺序转换为 Collection，复制一次元素
+func (d *Deque[T]) ToCollection() *Collection[T] {
+	coll := NewCollection(d.Values())
+	coll.compareFunc = d.compareFunc
+
+	return coll
+}
+
+// grow 容量翻倍，并将元素整理到缓冲区开头
+func (d *Deque[T]) grow() {
+	buf := make([]T, max(len(d.buf)*2, minDequeCapacity))
+	n := copy(buf, d.buf[d.head:])
+	copy(buf[n:], d.buf[:d.head])
+	d.buf = buf
+	d.head = 0
+}
+
+// wrap 将位置折回缓冲区范围内，i 需要小于两倍容量
+func (d *Deque[T]) wrap(i int) int {
+	if i >= len(d.buf) {
+		return i - len(d.buf)
+	}
+	return i
+}
@@ -0,0 +1,150 @@
+package slice_collcection
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	d := NewDeque(3, 4)
+	d.PushFront(2).PushFront(1).PushBack(5)
+	if !reflect.DeepEqual(d.Values(), []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("Unexpected values %v", d.Values())
+	}
+	if front, _ := d.Front(); front != 1 {
+		t.Errorf("Unexpected front %d", front)
+	}
+	if back, _ := d.Back(); back != 5 {
+		t.Errorf("Unexpected back %d", back)
+	}
+	if d.Index(2) != 3 || d.Index(5) != 0 {
+		t.Errorf("Unexpected index access")
+	}
+	d.SetIndex(2, 30)
+
+	if v, ok := d.PopFront(); !ok || v != 1 {
+		t.Errorf("Unexpected PopFront %d", v)
+	}
+	if v, ok := d.PopBack(); !ok || v != 5 {
+		t.Errorf("Unexpected PopBack %d", v)
+	}
+
+	keys := make([]int, 0)
+	items := make([]int, 0)
+	d.Each(func(item int, key int) bool {
+		keys = append(keys, key)
+		items = append(items, item)
+		return item != 30
+	})
+	if !reflect.DeepEqual(keys, []int{0, 1}) || !reflect.DeepEqual(items, []int{2, 30}) {
+		t.Errorf("Unexpected iteration %v %v", keys, items)
+	}
+
+	d.Clear()
+	if _, ok := d.PopFront(); ok || !d.IsEmpty() {
+		t.Error("Pop on empty deque should return false")
+	}
+	if _, ok := d.PopBack(); ok {
+		t.Error("Pop on empty deque should return false")
+	}
+}
+
+func TestDequeGrowAcrossWrap(t *testing.T) {
+	d := NewDeque[int]()
+	// 让头部位于缓冲区中间后再扩容
+	for i := 0; i < 6; i++ {
+		d.PushBack(i)
+	}
+	for i := 0; i < 4; i++ {
+		d.PopFront()
+	}
+	for i := 6; i < 20; i++ {
+		d.PushBack(i)
+	}
+	d.PushFront(3)
+
+	expected := []int{3}
+	for i := 4; i < 20; i++ {
+		expected = append(expected, i)
+	}
+	if !reflect.DeepEqual(d.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, d.Values())
+	}
+	if d.Cap() < d.Count() || d.IsFull() {
+		t.Errorf("Unexpected capacity %d for %d elements", d.Cap(), d.Count())
+	}
+}
+
+func TestBoundedDeque(t *testing.T) {
+	recent := NewBoundedDeque[int](3)
+	for i := 1; i <= 5; i++ {
+		recent.PushBack(i)
+	}
+	if !reflect.DeepEqual(recent.Values(), []int{3, 4, 5}) || !recent.IsFull() || recent.Cap() != 3 {
+		t.Errorf("Unexpected bounded values %v", recent.Values())
+	}
+
+	recent.PushFront(0)
+	if !reflect.DeepEqual(recent.Values(), []int{0, 3, 4}) {
+		t.Errorf("Unexpected values after PushFront %v", recent.Values())
+	}
+
+	recent.PopBack()
+	recent.PushBack(9)
+	if !reflect.DeepEqual(recent.Values(), []int{0, 3, 9}) {
+		t.Errorf("Unexpected values after PopBack %v", recent.Values())
+	}
+}
+
+func TestDequeCollectionConversion(t *testing.T) {
+	c := NewCollection([]int{5, 1, 3})
+	d := NewDequeFrom(c)
+	d.PushFront(9)
+	if !reflect.DeepEqual(c.Values(), []int{5, 1, 3}) {
+		t.Error("NewDequeFrom should not share the collection's slice")
+	}
+
+	back := d.ToCollection()
+	if !reflect.DeepEqual(back.Values(), []int{9, 5, 1, 3}) {
+		t.Errorf("Unexpected collection %v", back.Values())
+	}
+	if maxValue, err := back.Max(); err != nil || maxValue != 9 {
+		t.Errorf("ToCollection should keep the compare func: %v %v", maxValue, err)
+	}
+}
+
+func TestDequeRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	d := NewDeque[int]()
+	model := make([]int, 0)
+	for i := 0; i < 5000; i++ {
+		switch r.Intn(4) {
+		case 0:
+			d.PushBack(i)
+			model = append(model, i)
+		case 1:
+			d.PushFront(i)
+			model = append([]int{i}, model...)
+		case 2:
+			v, ok := d.PopFront()
+			if ok != (len(model) > 0) || ok && v != model[0] {
+				t.Fatalf("PopFront mismatch at step %d", i)
+			}
+			if ok {
+				model = model[1:]
+			}
+		default:
+			v, ok := d.PopBack()
+			if ok != (len(model) > 0) || ok && v != model[len(model)-1] {
+				t.Fatalf("PopBack mismatch at step %d", i)
+			}
+			if ok {
+				model = model[:len(model)-1]
+			}
+		}
+	}
+	if !reflect.DeepEqual(d.Values(), model) {
+		t.Error("Deque diverged from model")
+	}
+}
@@ -0,0 +1,416 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// EditOp 编辑脚本中的操作类型
+type EditOp int
+
+const (
+	EditKeep    EditOp = iota // 保留
+	EditInsert                // 插入
+	EditDelete                // 删除
+	EditReplace               // 替换
+)
+
+// String 返回操作名
+func (op EditOp) String() string {
+	switch op {
+	case EditKeep:
+		return "keep"
+	case EditInsert:
+		return "insert"
+	case EditDelete:
+		return "delete"
+	case EditReplace:
+		return "replace"
+	default:
+		return fmt.Sprintf("EditOp(%d)", int(op))
+	}
+}
+
+// Edit 编辑脚本中的一步
+// AIndex/BIndex 为执行该步之前在 a、b 中的位置
+// Old 在 keep/delete/replace 时有效，New 在 keep/insert/replace 时有效
+type Edit[T any] struct {
+	Op     EditOp
+	AIndex int
+	BIndex int
+	Old    T
+	New    T
+}
+
+// DiffSeq 计算将 a 变为 b 的最小编辑脚本（Myers 算法）
+// 相邻的删除与插入会被合并为 replace
+// eq: 判断两个元素是否相等，为 nil 时使用 a 的比较函数
+func DiffSeq[T any](a, b *Collection[T], eq func(T, T) bool) ([]Edit[T], error) {
+	if eq == nil {
+		if a == nil || !a.isComparable() {
+			return nil, errorx.NoComparableError
+		}
+		compareFunc := a.compareFunc
+		eq = func(x, y T) bool {
+			return compareFunc(x, y) == 0
+		}
+	}
+
+	var av, bv []T
+	if a != nil {
+		av = a.value
+	}
+	if b != nil {
+		bv = b.value
+	}
+
+	raw := myersEdits(av, bv, eq)
+
+	return mergeReplaceEdits(raw), nil
+}
+
+// Patch 将编辑脚本应用到 a 上，返回新的 Collection（不修改 a）
+// 脚本必须按顺序完整覆盖 a 的所有元素，否则返回 errorx.InvalidPatchError
+func Patch[T any](a *Collection[T], script []Edit[T]) (*Collection[T], error) {
+	var av []T
+	if a != nil {
+		av = a.value
+	}
+
+	res := make([]T, 0, len(av))
+	cursor := 0
+	for i, edit := range script {
+		switch edit.Op {
+		case EditInsert:
+			res = append(res, edit.New)
+			continue
+		case EditKeep, EditDelete, EditReplace:
+		default:
+			return nil, fmt.Errorf("%w: unknown edit op %v at step %d", errorx.InvalidPatchError, edit.Op, i)
+		}
+
+		if edit.AIndex != cursor || cursor >= len(av) {
+			return nil, fmt.Errorf("%w: step %d expects index %d, at %d", errorx.InvalidPatchError, i, edit.AIndex, cursor)
+		}
+		switch edit.Op {
+		case EditKeep:
+			res = append(res, av[cursor])
+		case EditReplace:
+			res = append(res, edit.New)
+		}
+		cursor++
+	}
+	if cursor != len(av) {
+		return nil, fmt.Errorf("%w: script covers %d of %d elements", errorx.InvalidPatchError, cursor, len(av))
+	}
+
+	coll := NewCollection(res)
+	if a != nil {
+		coll.compareFunc = a.compareFunc
+	}
+
+	return coll, nil
+}
+
+// UnifiedDiff 将编辑脚本渲染为 unified diff 风格的文本
+// context: 每个变更块前后保留的上下文行数
+// format: 元素格式化函数，为 nil 时使用 %v
+func UnifiedDiff[T any](script []Edit[T], context int, format func(T) string) string {
+	if format == nil {
+		format = func(item T) string {
+			return fmt.Sprintf("%v", item)
+		}
+	}
+	if context < 0 {
+		context = 0
+	}
+
+	lines := unifiedLines(script, format)
+
+	var builder strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].mark == ' ' {
+			i++
+			continue
+		}
+
+		// 确定变更块范围：相邻变更间距不超过 2*context 时合并到同一块
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].mark != ' ' {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		stop := end + context + 1
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+
+		aCount, bCount := 0, 0
+		for _, line := range lines[start:stop] {
+			if line.mark != '+' {
+				aCount++
+			}
+			if line.mark != '-' {
+				bCount++
+			}
+		}
+		aStart, bStart := lines[start].aIndex, lines[start].bIndex
+		if aCount > 0 {
+			aStart++
+		}
+		if bCount > 0 {
+			bStart++
+		}
+
+		builder.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount))
+		for _, line := range lines[start:stop] {
+			builder.WriteByte(line.mark)
+			builder.WriteString(line.text)
+			builder.WriteByte('\n')
+		}
+		i = stop
+	}
+
+	return builder.String()
+}
+
+// unifiedLine unified diff 中的一行
+type unifiedLine struct {
+	mark   byte // ' '、'-'、'+'
+	text   string
+	aIndex int // 该行之前在 a 中的位置
+	bIndex int // 该行之前在 b 中的位置
+}
+
+// unifiedLines 将编辑脚本展开为行，每段连续变更中先输出删除行再输出插入行
+func unifiedLines[T any](script []Edit[T], format func(T) string) []unifiedLine {
+	lines := make([]unifiedLine, 0, len(script))
+	aIndex, bIndex := 0, 0
+	for i := 0; i < len(script); {
+		if script[i].Op == EditKeep {
+			lines = append(lines, unifiedLine{mark: ' ', text: format(script[i].Old), aIndex: aIndex, bIndex: bIndex})
+			aIndex++
+			bIndex++
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(script) && script[j].Op != EditKeep {
+			j++
+		}
+		for _, edit := range script[i:j] {
+			if edit.Op == EditDelete || edit.Op == EditReplace {
+				lines = append(lines, unifiedLine{mark: '-', text: format(edit.Old), aIndex: aIndex, bIndex: bIndex})
+				aIndex++
+			}
+		}
+		for _, edit := range script[i:j] {
+			if edit.Op == EditInsert || edit.Op == EditReplace {
+				lines = append(lines, unifiedLine{mark: '+', text: format(edit.New), aIndex: aIndex, bIndex: bIndex})
+				bIndex++
+			}
+		}
+		i = j
+	}
+
+	return lines
+}
+
+// myersEdits 使用 Myers 差分算法生成仅包含 keep/insert/delete 的编辑序列
+// 采用线性空间的分治版本：每次找到最短编辑路径的中间 snake 后对两侧递归，
+// 内存为 O(n+m)，不保存每一步的 V 数组
+func myersEdits[T any](a, b []T, eq func(T, T) bool) []Edit[T] {
+	d := &myersDiff[T]{eq: eq, edits: make([]Edit[T], 0, max(len(a), len(b)))}
+	d.compare(a, b)
+	return d.edits
+}
+
+// myersDiff 分治过程中的状态，edits 按顺序追加
+type myersDiff[T any] struct {
+	eq    func(T, T) bool
+	edits []Edit[T]
+}
+
+// compare 追加将 a 变为 b 的编辑序列
+func (d *myersDiff[T]) compare(a, b []T) {
+	// 公共前缀与后缀直接保留
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && d.eq(a[prefix], b[prefix]) {
+		prefix++
+	}
+	d.keep(a[:prefix], b[:prefix])
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && d.eq(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+	suffixA, suffixB := a[len(a)-suffix:], b[len(b)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch x, y := d.bisect(a, b); {
+	case len(a) == 0 || len(b) == 0 || x < 0:
+		d.remove(a)
+		d.insert(b)
+	default:
+		d.compare(a[:x], b[:y])
+		d.compare(a[x:], b[y:])
+	}
+
+	d.keep(suffixA, suffixB)
+}
+
+// bisect 同时从两端搜索最短编辑路径，返回两个方向相遇的位置 (x, y)；两侧没有任何公共元素时返回 -1
+// 调用前已去除公共前缀与后缀；a 或 b 为空时直接返回 -1
+func (d *myersDiff[T]) bisect(a, b []T) (int, int) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return -1, -1
+	}
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// delta 为奇数时在正向搜索中检查相遇，否则在反向搜索中检查
+	checkForward := delta%2 != 0
+	// 超出编辑图边界的对角线不再扩展
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for step := 0; step < maxD; step++ {
+		for k := -step + fStart; k <= step-fEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -step || (k != step && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.eq(a[x], b[y]) {
+				x++
+				y++
+			}
+			forward[i] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case checkForward:
+				if j := offset + delta - k; j >= 0 && j < len(backward) && backward[j] != -1 && x >= n-backward[j] {
+					return x, y
+				}
+			}
+		}
+
+		for k := -step + bStart; k <= step-bEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -step || (k != step && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.eq(a[n-x-1], b[m-y-1]) {
+				x++
+				y++
+			}
+			backward[i] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !checkForward:
+				if j := offset + delta - k; j >= 0 && j < len(forward) && forward[j] != -1 {
+					fx := forward[j]
+					if fx >= n-x {
+						return fx, fx - (j - offset)
+					}
+				}
+			}
+		}
+	}
+
+	return -1, -1
+}
+
+func (d *myersDiff[T]) keep(a, b []T) {
+	for i := range a {
+		d.edits = append(d.edits, Edit[T]{Op: EditKeep, Old: a[i], New: b[i]})
+	}
+}
+
+func (d *myersDiff[T]) remove(a []T) {
+	for _, item := range a {
+		d.edits = append(d.edits, Edit[T]{Op: EditDelete, Old: item})
+	}
+}
+
+func (d *myersDiff[T]) insert(b []T) {
+	for _, item := range b {
+		d.edits = append(d.edits, Edit[T]{Op: EditInsert, New: item})
+	}
+}
+
+// mergeReplaceEdits 将每段连续变更中的删除与插入两两合并为 replace，并重新计算位置
+func mergeReplaceEdits[T any](edits []Edit[T]) []Edit[T] {
+	res := make([]Edit[T], 0, len(edits))
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditKeep {
+			res = append(res, edits[i])
+			i++
+			continue
+		}
+
+		deletes := make([]Edit[T], 0)
+		inserts := make([]Edit[T], 0)
+		for ; i < len(edits) && edits[i].Op != EditKeep; i++ {
+			if edits[i].Op == EditDelete {
+				deletes = append(deletes, edits[i])
+			} else {
+				inserts = append(inserts, edits[i])
+			}
+		}
+
+		pairs := min(len(deletes), len(inserts))
+		for p := 0; p < pairs; p++ {
+			res = append(res, Edit[T]{Op: EditReplace, Old: deletes[p].Old, New: inserts[p].New})
+		}
+		res = append(res, deletes[pairs:]...)
+		res = append(res, inserts[pairs:]...)
+	}
+
+	aIndex, bIndex := 0, 0
+	for i := range res {
+		res[i].AIndex = aIndex
+		res[i].BIndex = bIndex
+		switch res[i].Op {
+		case EditKeep, EditReplace:
+			aIndex++
+			bIndex++
+		case EditDelete:
+			aIndex++
+		case EditInsert:
+			bIndex++
+		}
+	}
+
+	return res
+}
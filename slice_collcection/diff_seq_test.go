@@ -0,0 +1,230 @@
+package slice_collcection
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+func editOps[T any](script []Edit[T]) []EditOp {
+	ops := make([]EditOp, 0, len(script))
+	for _, edit := range script {
+		ops = append(ops, edit.Op)
+	}
+	return ops
+}
+
+func TestDiffSeq(t *testing.T) {
+	a := NewCollection([]string{"login", "verify", "pay", "ship"})
+	b := NewCollection([]string{"login", "pay", "review", "ship", "notify"})
+
+	script, err := DiffSeq(a, b, func(x, y string) bool { return x == y })
+	if err != nil {
+		t.Fatalf("DiffSeq returned error: %v", err)
+	}
+
+	expected := []EditOp{EditKeep, EditDelete, EditKeep, EditInsert, EditKeep, EditInsert}
+	if !reflect.DeepEqual(editOps(script), expected) {
+		t.Errorf("Expected ops %v, got %v", expected, editOps(script))
+	}
+	if script[1].Old != "verify" || script[1].AIndex != 1 {
+		t.Errorf("Unexpected delete edit: %+v", script[1])
+	}
+	if script[3].New != "review" || script[3].AIndex != 3 || script[3].BIndex != 2 {
+		t.Errorf("Unexpected insert edit: %+v", script[3])
+	}
+}
+
+func TestDiffSeqReplace(t *testing.T) {
+	a := NewCollection([]int{1, 2, 3})
+	b := NewCollection([]int{1, 9, 3})
+
+	script, err := DiffSeq(a, b, nil)
+	if err != nil {
+		t.Fatalf("DiffSeq returned error: %v", err)
+	}
+
+	expected := []EditOp{EditKeep, EditReplace, EditKeep}
+	if !reflect.DeepEqual(editOps(script), expected) {
+		t.Errorf("Expected ops %v, got %v", expected, editOps(script))
+	}
+	if script[1].Old != 2 || script[1].New != 9 {
+		t.Errorf("Unexpected replace edit: %+v", script[1])
+	}
+}
+
+func TestDiffSeqWithEq(t *testing.T) {
+	type step struct {
+		ID   int
+		Name string
+	}
+	a := NewCollection([]step{{1, "a"}, {2, "b"}})
+	b := NewCollection([]step{{2, "B"}, {3, "c"}})
+
+	script, err := DiffSeq(a, b, func(x, y step) bool { return x.ID == y.ID })
+	if err != nil {
+		t.Fatalf("DiffSeq returned error: %v", err)
+	}
+
+	expected := []EditOp{EditDelete, EditKeep, EditInsert}
+	if !reflect.DeepEqual(editOps(script), expected) {
+		t.Errorf("Expected ops %v, got %v", expected, editOps(script))
+	}
+
+	if _, err := DiffSeq(a, b, nil); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+}
+
+func TestDiffSeqEmpty(t *testing.T) {
+	script, err := DiffSeq(NewCollection([]int{}), NewCollection([]int{1, 2}), nil)
+	if err != nil {
+		t.Fatalf("DiffSeq returned error: %v", err)
+	}
+	if !reflect.DeepEqual(editOps(script), []EditOp{EditInsert, EditInsert}) {
+		t.Errorf("Unexpected ops %v", editOps(script))
+	}
+
+	script, _ = DiffSeq(NewCollection([]int{}), NewCollection([]int{}), nil)
+	if len(script) != 0 {
+		t.Errorf("Expected empty script, got %v", script)
+	}
+}
+
+func TestDiffSeqMinimalRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	randomInts := func() []int {
+		res := make([]int, r.Intn(30))
+		for i := range res {
+			res[i] = r.Intn(4)
+		}
+		return res
+	}
+	lcs := func(a, b []int) int {
+		dp := make([][]int, len(a)+1)
+		for i := range dp {
+			dp[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					dp[i][j] = dp[i+1][j+1] + 1
+				} else {
+					dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+				}
+			}
+		}
+		return dp[0][0]
+	}
+
+	for i := 0; i < 500; i++ {
+		av, bv := randomInts(), randomInts()
+		a, b := NewCollection(av), NewCollection(bv)
+		script, err := DiffSeq(a, b, nil)
+		if err != nil {
+			t.Fatalf("DiffSeq returned error: %v", err)
+		}
+		keeps := 0
+		for _, edit := range script {
+			if edit.Op == EditKeep {
+				keeps++
+			}
+		}
+		if expected := lcs(av, bv); keeps != expected {
+			t.Fatalf("Script for %v -> %v keeps %d elements, expected %d", av, bv, keeps, expected)
+		}
+		patched, err := Patch(a, script)
+		if err != nil || !reflect.DeepEqual(patched.Values(), b.Values()) {
+			t.Fatalf("Patch(%v) = %v (%v), expected %v", av, patched, err, bv)
+		}
+	}
+}
+
+func TestDiffSeqLargeDisjoint(t *testing.T) {
+	av, bv := make([]int, 5000), make([]int, 5000)
+	for i := range av {
+		av[i], bv[i] = i, -i-1
+	}
+
+	// 内存需要与输入长度线性相关，而不是 O((n+m)·D)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	script, err := DiffSeq(NewCollection(av), NewCollection(bv), func(x, y int) bool { return x == y })
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("DiffSeq returned error: %v", err)
+	}
+	if len(script) != 5000 || script[0].Op != EditReplace || script[4999].Op != EditReplace {
+		t.Errorf("Expected 5000 replace edits, got %d", len(script))
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 32<<20 {
+		t.Errorf("DiffSeq allocated %d bytes for disjoint inputs", allocated)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	cases := [][2][]int{
+		{{1, 2, 3, 4, 5}, {1, 3, 4, 6, 5, 7}},
+		{{}, {1, 2}},
+		{{1, 2}, {}},
+		{{5, 4, 3, 2, 1}, {1, 2, 3, 4, 5}},
+		{{1, 1, 2, 2}, {2, 2, 1, 1}},
+	}
+	for _, tc := range cases {
+		a, b := NewCollection(tc[0]), NewCollection(tc[1])
+		script, err := DiffSeq(a, b, nil)
+		if err != nil {
+			t.Fatalf("DiffSeq returned error: %v", err)
+		}
+
+		patched, err := Patch(a, script)
+		if err != nil {
+			t.Fatalf("Patch returned error: %v", err)
+		}
+		if !reflect.DeepEqual(patched.Values(), tc[1]) && !(len(tc[1]) == 0 && patched.Count() == 0) {
+			t.Errorf("Patch(%v) expected %v, got %v", tc[0], tc[1], patched.Values())
+		}
+	}
+
+	// 脚本与 a 不匹配时报错
+	script, _ := DiffSeq(NewCollection([]int{1, 2}), NewCollection([]int{1}), nil)
+	if _, err := Patch(NewCollection([]int{1, 2, 3}), script); !errors.Is(err, errorx.InvalidPatchError) {
+		t.Errorf("Expected InvalidPatchError, got %v", err)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := NewCollection([]string{"a", "b", "c", "d", "e", "f", "g", "h"})
+	b := NewCollection([]string{"a", "B", "c", "d", "e", "f", "g", "h", "i"})
+
+	script, err := DiffSeq(a, b, func(x, y string) bool { return x == y })
+	if err != nil {
+		t.Fatalf("DiffSeq returned error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"@@ -1,3 +1,3 @@",
+		" a",
+		"-b",
+		"+B",
+		" c",
+		"@@ -8,1 +8,2 @@",
+		" h",
+		"+i",
+		"",
+	}, "\n")
+	if got := UnifiedDiff(script, 1, nil); got != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	// 上下文足够大时合并为一个变更块
+	got := UnifiedDiff(script, 3, nil)
+	if strings.Count(got, "@@ -") != 1 || !strings.HasPrefix(got, "@@ -1,8 +1,9 @@") {
+		t.Errorf("Expected a single hunk, got:\n%s", got)
+	}
+}
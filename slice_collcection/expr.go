@@ -0,0 +1,751 @@
+package slice_collcection
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// Expr 编译后的过滤表达式，可并发使用
+//
+// 表达式语法：
+//   - 字面量：数字、"字符串" 或 '字符串'、true、false、nil（null）、列表 [1, 2, 3]
+//   - 字段：字段名或 json tag，嵌套结构体使用 a.b；指针字段自动解引用，nil 指针的值为 nil
+//   - 比较：== != <> < <= > >=，数值、字符串、time.Time 之间可比较大小；time.Time 可以与 RFC3339 或 2006-01-02 格式的字符串字面量比较
+//   - 逻辑：&& || !（也可写作 and or not），短路求值
+//   - 集合：in、not in（右侧为列表字面量或切片字段），切片字段 contains 元素
+//   - 字符串：startsWith、endsWith、contains、matches（正则，右侧需为字符串字面量）
+//   - 算术：+ - * / %，字符串可以用 + 拼接
+//   - 函数：lower(s)、upper(s)、trim(s)、len(s 或切片)、abs(n)
+//
+// 所有数值在运算时都转换为 float64；nil 只与 nil 相等（因此 nil != "x" 为 true），与 nil 比较大小、除以 0 等无法求值的情况结果为 nil，作为条件时视为 false。
+// 表达式在编译时按 T 的字段做类型检查，字段不存在时返回 errorx.FieldNotFoundError，类型不匹配时返回 errorx.InvalidTypeError，
+// 语法错误时返回 errorx.InvalidExpressionError。
+type Expr[T any] struct {
+	program *exprProgram
+}
+
+// exprProgram 与元素类型无关的编译结果
+type exprProgram struct {
+	src  string
+	eval exprEval
+}
+
+// exprEval 求值函数，返回 float64、string、bool、time.Time、[]any 或 nil
+type exprEval func(item reflect.Value) any
+
+// exprCacheKey 编译缓存的 key
+type exprCacheKey struct {
+	typ reflect.Type
+	src string
+}
+
+// maxExprCacheSize 编译缓存最多保留的表达式数量，超出后淘汰最久未使用的
+const maxExprCacheSize = 1024
+
+// exprCache 缓存最近使用的编译结果，表达式来自外部输入时内存占用也有上限
+var exprCache = newExprLRU(maxExprCacheSize)
+
+// exprLRU 并发安全的 LRU 缓存
+type exprLRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // 元素为 *exprCacheEntry，越靠前越近使用
+	entries  map[exprCacheKey]*list.Element
+}
+
+type exprCacheEntry struct {
+	key     exprCacheKey
+	program *exprProgram
+}
+
+func newExprLRU(capacity int) *exprLRU {
+	return &exprLRU{capacity: capacity, order: list.New(), entries: make(map[exprCacheKey]*list.Element)}
+}
+
+// get 读取缓存并标记为最近使用
+func (l *exprLRU) get(key exprCacheKey) (*exprProgram, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*exprCacheEntry).program, true
+}
+
+// add 写入缓存，已存在时返回已有的结果；超出容量时淘汰最久未使用的
+func (l *exprLRU) add(key exprCacheKey, program *exprProgram) *exprProgram {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok {
+		l.order.MoveToFront(e)
+		return e.Value.(*exprCacheEntry).program
+	}
+	l.entries[key] = l.order.PushFront(&exprCacheEntry{key: key, program: program})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*exprCacheEntry).key)
+	}
+	return program
+}
+
+// len 返回缓存的表达式数量
+func (l *exprLRU) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+// CompileExpr 按 T 的字段编译表达式，最近使用的编译结果会被缓存（最多 1024 个），返回的 Expr 可以长期持有、重复使用
+func CompileExpr[T any](src string) (*Expr[T], error) {
+	program, err := compileExprFor(reflect.TypeOf((*T)(nil)).Elem(), src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expr[T]{program: program}, nil
+}
+
+// Match 判断元素是否满足表达式
+func (e *Expr[T]) Match(item T) bool {
+	return e.program.eval(reflect.ValueOf(item)) == true
+}
+
+// String 返回表达式原文
+func (e *Expr[T]) String() string {
+	return e.program.src
+}
+
+// FilterExpr 使用字符串表达式过滤元素（不修改原 Collection，返回新的 Collection）
+// 表达式语法见 Expr，例如：
+//
+//	c.FilterExpr(`status == "active" && (age > 30 || vip) && name startsWith "A"`)
+func (c *Collection[T]) FilterExpr(src string) (*Collection[T], error) {
+	program, err := compileExprFor(c.typ, src)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]T, 0)
+	for _, item := range c.value {
+		if program.eval(reflect.ValueOf(item)) == true {
+			res = append(res, item)
+		}
+	}
+	coll := NewCollection(res)
+	coll.compareFunc = c.compareFunc
+
+	return coll, nil
+}
+
+// compileExprFor 编译表达式，优先读取缓存
+func compileExprFor(elemType reflect.Type, src string) (*exprProgram, error) {
+	key := exprCacheKey{typ: elemType, src: src}
+	if cached, ok := exprCache.get(key); ok {
+		return cached, nil
+	}
+
+	if structType := derefTypeOrNil(elemType); structType == nil || structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: collection `T` must be a struct or pointer to struct", errorx.InvalidTypeError)
+	}
+	node, err := parseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	compiler := &exprCompiler{elemType: elemType}
+	typ, eval, err := compiler.compile(node)
+	if err != nil {
+		return nil, err
+	}
+	if typ.kind != exprBool {
+		return nil, fmt.Errorf("%w: expression must be a boolean, got %s", errorx.InvalidTypeError, typ)
+	}
+
+	return exprCache.add(key, &exprProgram{src: src, eval: eval}), nil
+}
+
+// exprKind 表达式的静态类型
+type exprKind int
+
+const (
+	exprNil exprKind = iota
+	exprNumber
+	exprString
+	exprBool
+	exprTime
+	exprList
+	exprOther // 其它类型的字段，只能与 nil 比较
+)
+
+// exprType 静态类型，elem 为列表的元素类型
+type exprType struct {
+	kind exprKind
+	elem *exprType
+}
+
+func (t exprType) String() string {
+	switch t.kind {
+	case exprNil:
+		return "nil"
+	case exprNumber:
+		return "number"
+	case exprString:
+		return "string"
+	case exprBool:
+		return "bool"
+	case exprTime:
+		return "time"
+	case exprList:
+		if t.elem == nil {
+			return "list"
+		}
+		return "list of " + t.elem.String()
+	default:
+		return "value"
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// maxExprTypeDepth 类型映射时最多展开的指针与切片层数，避免 type S []S 这样的自引用类型无限递归
+const maxExprTypeDepth = 32
+
+// exprTypeOf 将 Go 类型映射为表达式类型
+func exprTypeOf(typ reflect.Type) exprType {
+	return exprTypeAt(typ, 0)
+}
+
+func exprTypeAt(typ reflect.Type, depth int) exprType {
+	for ; typ.Kind() == reflect.Pointer; depth++ {
+		if depth >= maxExprTypeDepth {
+			return exprType{kind: exprOther}
+		}
+		typ = typ.Elem()
+	}
+	if typ == timeType {
+		return exprType{kind: exprTime}
+	}
+	switch kind := typ.Kind(); {
+	case kind == reflect.String:
+		return exprType{kind: exprString}
+	case kind == reflect.Bool:
+		return exprType{kind: exprBool}
+	case utils.IsComputableKind(kind):
+		return exprType{kind: exprNumber}
+	case kind == reflect.Slice || kind == reflect.Array:
+		if depth >= maxExprTypeDepth {
+			return exprType{kind: exprList}
+		}
+		elem := exprTypeAt(typ.Elem(), depth+1)
+		return exprType{kind: exprList, elem: &elem}
+	default:
+		return exprType{kind: exprOther}
+	}
+}
+
+// exprValueOf 将反射值转换为表达式的运行时值
+func exprValueOf(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = exprValueOf(v.Index(i))
+		}
+		return items
+	default:
+		if isNilValue(v) {
+			return nil
+		}
+		return v.Interface()
+	}
+}
+
+// exprCompiler 类型检查并将语法树编译为闭包
+type exprCompiler struct {
+	elemType reflect.Type
+}
+
+func (c *exprCompiler) errorf(node exprNode, format string, args ...any) error {
+	return fmt.Errorf("%w: %s at %d", errorx.InvalidTypeError, fmt.Sprintf(format, args...), node.position())
+}
+
+func (c *exprCompiler) compile(node exprNode) (exprType, exprEval, error) {
+	switch n := node.(type) {
+	case *exprLiteral:
+		return c.compileLiteral(n)
+	case *exprField:
+		return c.compileField(n)
+	case *exprListLit:
+		return c.compileList(n)
+	case *exprUnary:
+		return c.compileUnary(n)
+	case *exprBinary:
+		return c.compileBinary(n)
+	case *exprCall:
+		return c.compileCall(n)
+	default:
+		return exprType{}, nil, fmt.Errorf("%w: unknown node %T", errorx.InvalidExpressionError, node)
+	}
+}
+
+func (c *exprCompiler) compileLiteral(n *exprLiteral) (exprType, exprEval, error) {
+	value := n.value
+	eval := func(reflect.Value) any { return value }
+	switch value.(type) {
+	case float64:
+		return exprType{kind: exprNumber}, eval, nil
+	case string:
+		return exprType{kind: exprString}, eval, nil
+	case bool:
+		return exprType{kind: exprBool}, eval, nil
+	case time.Time:
+		return exprType{kind: exprTime}, eval, nil
+	default:
+		return exprType{kind: exprNil}, eval, nil
+	}
+}
+
+// compileField 逐级解析嵌套字段
+func (c *exprCompiler) compileField(n *exprField) (exprType, exprEval, error) {
+	current := c.elemType
+	indexes := make([][]int, 0, len(n.path))
+	for i, part := range n.path {
+		if i > 0 && derefType(current).Kind() != reflect.Struct {
+			return exprType{}, nil, c.errorf(n, "%s is not a struct", strings.Join(n.path[:i], "."))
+		}
+		accessor, err := resolveField(current, part)
+		if err != nil {
+			return exprType{}, nil, fmt.Errorf("%w at %d", err, n.pos)
+		}
+		indexes = append(indexes, accessor.index)
+		current = accessor.typ
+	}
+
+	eval := func(item reflect.Value) any {
+		v := item
+		for _, index := range indexes {
+			for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+				if v.IsNil() {
+					return nil
+				}
+				v = v.Elem()
+			}
+			field, err := v.FieldByIndexErr(index)
+			if err != nil {
+				return nil
+			}
+			v = field
+		}
+		return exprValueOf(v)
+	}
+
+	return exprTypeOf(current), eval, nil
+}
+
+func (c *exprCompiler) compileList(n *exprListLit) (exprType, exprEval, error) {
+	var elem *exprType
+	evals := make([]exprEval, 0, len(n.items))
+	for _, item := range n.items {
+		typ, eval, err := c.compile(item)
+		if err != nil {
+			return exprType{}, nil, err
+		}
+		if typ.kind == exprList {
+			return exprType{}, nil, c.errorf(item, "nested lists are not supported")
+		}
+		if typ.kind != exprNil {
+			if elem != nil && elem.kind != typ.kind {
+				return exprType{}, nil, c.errorf(item, "list mixes %s and %s", elem, typ)
+			}
+			elem = &typ
+		}
+		evals = append(evals, eval)
+	}
+
+	return exprType{kind: exprList, elem: elem}, func(item reflect.Value) any {
+		values := make([]any, len(evals))
+		for i, eval := range evals {
+			values[i] = eval(item)
+		}
+		return values
+	}, nil
+}
+
+func (c *exprCompiler) compileUnary(n *exprUnary) (exprType, exprEval, error) {
+	typ, x, err := c.compile(n.x)
+	if err != nil {
+		return exprType{}, nil, err
+	}
+
+	if n.op == "!" {
+		if typ.kind != exprBool {
+			return exprType{}, nil, c.errorf(n, "operator ! requires bool, got %s", typ)
+		}
+		return typ, func(item reflect.Value) any {
+			return x(item) != true
+		}, nil
+	}
+
+	if typ.kind != exprNumber {
+		return exprType{}, nil, c.errorf(n, "operator - requires number, got %s", typ)
+	}
+	return typ, func(item reflect.Value) any {
+		if v, ok := x(item).(float64); ok {
+			return -v
+		}
+		return nil
+	}, nil
+}
+
+func (c *exprCompiler) compileBinary(n *exprBinary) (exprType, exprEval, error) {
+	xt, x, err := c.compile(n.x)
+	if err != nil {
+		return exprType{}, nil, err
+	}
+	yt, y, err := c.compile(n.y)
+	if err != nil {
+		return exprType{}, nil, err
+	}
+	boolType := exprType{kind: exprBool}
+
+	switch n.op {
+	case "&&", "||":
+		if xt.kind != exprBool || yt.kind != exprBool {
+			return exprType{}, nil, c.errorf(n, "operator %s requires bool operands, got %s and %s", n.op, xt, yt)
+		}
+		if n.op == "&&" {
+			return boolType, func(item reflect.Value) any {
+				return x(item) == true && y(item) == true
+			}, nil
+		}
+		return boolType, func(item reflect.Value) any {
+			return x(item) == true || y(item) == true
+		}, nil
+
+	case "==", "!=":
+		xt, x, yt, y = c.coerceTime(n.x, xt, x, n.y, yt, y)
+		if xt.kind != exprNil && yt.kind != exprNil &&
+			(xt.kind != yt.kind || xt.kind == exprList || xt.kind == exprOther) {
+			return exprType{}, nil, c.errorf(n, "cannot compare %s with %s", xt, yt)
+		}
+		negate := n.op == "!="
+		return boolType, func(item reflect.Value) any {
+			return exprEqual(x(item), y(item)) != negate
+		}, nil
+
+	case "<", "<=", ">", ">=":
+		xt, x, yt, y = c.coerceTime(n.x, xt, x, n.y, yt, y)
+		if xt.kind != yt.kind || xt.kind != exprNumber && xt.kind != exprString && xt.kind != exprTime {
+			return exprType{}, nil, c.errorf(n, "cannot order %s and %s", xt, yt)
+		}
+		accept := exprOrderAccept(n.op)
+		return boolType, func(item reflect.Value) any {
+			a, b := x(item), y(item)
+			if a == nil || b == nil {
+				return false
+			}
+			cmp, err := utils.CompareAny(a, b)
+			return err == nil && accept(cmp)
+		}, nil
+
+	case "in", "not in":
+		if yt.kind != exprList {
+			return exprType{}, nil, c.errorf(n, "operator %s requires a list on the right, got %s", n.op, yt)
+		}
+		if yt.elem != nil && xt.kind != exprNil && xt.kind != yt.elem.kind {
+			return exprType{}, nil, c.errorf(n, "cannot search %s in %s", xt, yt)
+		}
+		negate := n.op == "not in"
+		return boolType, func(item reflect.Value) any {
+			return exprContains(y(item), x(item)) != negate
+		}, nil
+
+	case "contains":
+		if xt.kind == exprList {
+			if xt.elem != nil && yt.kind != exprNil && yt.kind != xt.elem.kind {
+				return exprType{}, nil, c.errorf(n, "cannot search %s in %s", yt, xt)
+			}
+			return boolType, func(item reflect.Value) any {
+				return exprContains(x(item), y(item))
+			}, nil
+		}
+		return c.compileStringOp(n, xt, x, yt, y, strings.Contains)
+
+	case "startsWith":
+		return c.compileStringOp(n, xt, x, yt, y, strings.HasPrefix)
+
+	case "endsWith":
+		return c.compileStringOp(n, xt, x, yt, y, strings.HasSuffix)
+
+	case "matches":
+		literal, ok := n.y.(*exprLiteral)
+		pattern, isString := literal.valueString()
+		if !ok || !isString {
+			return exprType{}, nil, c.errorf(n, "operator matches requires a string literal pattern")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return exprType{}, nil, fmt.Errorf("%w: invalid pattern %q at %d: %v", errorx.InvalidExpressionError, pattern, n.pos, err)
+		}
+		if xt.kind != exprString {
+			return exprType{}, nil, c.errorf(n, "operator matches requires string, got %s", xt)
+		}
+		return boolType, func(item reflect.Value) any {
+			s, ok := x(item).(string)
+			return ok && re.MatchString(s)
+		}, nil
+
+	case "+":
+		if xt.kind == exprString && yt.kind == exprString {
+			return xt, func(item reflect.Value) any {
+				a, okA := x(item).(string)
+				b, okB := y(item).(string)
+				if !okA || !okB {
+					return nil
+				}
+				return a + b
+			}, nil
+		}
+		return c.compileArithmetic(n, xt, x, yt, y, func(a, b float64) any { return a + b })
+
+	case "-":
+		return c.compileArithmetic(n, xt, x, yt, y, func(a, b float64) any { return a - b })
+
+	case "*":
+		return c.compileArithmetic(n, xt, x, yt, y, func(a, b float64) any { return a * b })
+
+	case "/":
+		return c.compileArithmetic(n, xt, x, yt, y, func(a, b float64) any {
+			if b == 0 {
+				return nil
+			}
+			return a / b
+		})
+
+	case "%":
+		return c.compileArithmetic(n, xt, x, yt, y, func(a, b float64) any {
+			if b == 0 {
+				return nil
+			}
+			return math.Mod(a, b)
+		})
+	}
+
+	return exprType{}, nil, fmt.Errorf("%w: %q at %d", errorx.InvalidOperatorError, n.op, n.pos)
+}
+
+// compileStringOp 编译 startsWith/endsWith/contains 等字符串运算
+func (c *exprCompiler) compileStringOp(n *exprBinary, xt exprType, x exprEval, yt exprType, y exprEval, fn func(s, sub string) bool) (exprType, exprEval, error) {
+	if xt.kind != exprString || yt.kind != exprString {
+		return exprType{}, nil, c.errorf(n, "operator %s requires string operands, got %s and %s", n.op, xt, yt)
+	}
+
+	return exprType{kind: exprBool}, func(item reflect.Value) any {
+		s, okS := x(item).(string)
+		sub, okSub := y(item).(string)
+		return okS && okSub && fn(s, sub)
+	}, nil
+}
+
+// compileArithmetic 编译数值运算，任一操作数为 nil 时结果为 nil
+func (c *exprCompiler) compileArithmetic(n *exprBinary, xt exprType, x exprEval, yt exprType, y exprEval, fn func(a, b float64) any) (exprType, exprEval, error) {
+	if xt.kind != exprNumber || yt.kind != exprNumber {
+		return exprType{}, nil, c.errorf(n, "operator %s requires number operands, got %s and %s", n.op, xt, yt)
+	}
+
+	return xt, func(item reflect.Value) any {
+		a, okA := x(item).(float64)
+		b, okB := y(item).(float64)
+		if !okA || !okB {
+			return nil
+		}
+		return fn(a, b)
+	}, nil
+}
+
+// coerceTime time.Time 与字符串字面量比较时，在编译期将字面量解析为时间
+func (c *exprCompiler) coerceTime(xn exprNode, xt exprType, x exprEval, yn exprNode, yt exprType, y exprEval) (exprType, exprEval, exprType, exprEval) {
+	toTime := func(node exprNode) (exprType, exprEval, bool) {
+		literal, ok := node.(*exprLiteral)
+		if !ok {
+			return exprType{}, nil, false
+		}
+		s, ok := literal.valueString()
+		if !ok {
+			return exprType{}, nil, false
+		}
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if t, err := time.Parse(layout, s); err == nil {
+				typ, eval, _ := c.compileLiteral(&exprLiteral{pos: literal.pos, value: t})
+				return typ, eval, true
+			}
+		}
+		return exprType{}, nil, false
+	}
+
+	if xt.kind == exprTime && yt.kind == exprString {
+		if typ, eval, ok := toTime(yn); ok {
+			return xt, x, typ, eval
+		}
+	}
+	if yt.kind == exprTime && xt.kind == exprString {
+		if typ, eval, ok := toTime(xn); ok {
+			return typ, eval, yt, y
+		}
+	}
+	return xt, x, yt, y
+}
+
+// valueString 返回字符串字面量的值，不是字符串字面量时返回 false
+func (n *exprLiteral) valueString() (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	s, ok := n.value.(string)
+	return s, ok
+}
+
+func (c *exprCompiler) compileCall(n *exprCall) (exprType, exprEval, error) {
+	if len(n.args) != 1 {
+		return exprType{}, nil, fmt.Errorf("%w: function %s expects 1 argument, got %d at %d", errorx.InvalidExpressionError, n.name, len(n.args), n.pos)
+	}
+	typ, x, err := c.compile(n.args[0])
+	if err != nil {
+		return exprType{}, nil, err
+	}
+
+	stringFunc := func(fn func(string) string) (exprType, exprEval, error) {
+		if typ.kind != exprString {
+			return exprType{}, nil, c.errorf(n, "function %s requires string, got %s", n.name, typ)
+		}
+		return typ, func(item reflect.Value) any {
+			if s, ok := x(item).(string); ok {
+				return fn(s)
+			}
+			return nil
+		}, nil
+	}
+
+	switch n.name {
+	case "lower":
+		return stringFunc(strings.ToLower)
+	case "upper":
+		return stringFunc(strings.ToUpper)
+	case "trim":
+		return stringFunc(strings.TrimSpace)
+	case "len":
+		if typ.kind != exprString && typ.kind != exprList {
+			return exprType{}, nil, c.errorf(n, "function len requires string or list, got %s", typ)
+		}
+		return exprType{kind: exprNumber}, func(item reflect.Value) any {
+			switch v := x(item).(type) {
+			case string:
+				return float64(len([]rune(v)))
+			case []any:
+				return float64(len(v))
+			default:
+				return float64(0)
+			}
+		}, nil
+	case "abs":
+		if typ.kind != exprNumber {
+			return exprType{}, nil, c.errorf(n, "function abs requires number, got %s", typ)
+		}
+		return typ, func(item reflect.Value) any {
+			if v, ok := x(item).(float64); ok {
+				return math.Abs(v)
+			}
+			return nil
+		}, nil
+	}
+
+	return exprType{}, nil, fmt.Errorf("%w: unknown function %s at %d", errorx.InvalidExpressionError, n.name, n.pos)
+}
+
+// exprEqual 运行时相等判断
+func exprEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	if !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// exprContains 列表 list 中是否存在与 value 相等的元素
+func exprContains(list any, value any) bool {
+	items, ok := list.([]any)
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if exprEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// exprOrderAccept 返回比较结果的判定函数
+func exprOrderAccept(op string) func(cmp int) bool {
+	switch op {
+	case "<":
+		return func(cmp int) bool { return cmp < 0 }
+	case "<=":
+		return func(cmp int) bool { return cmp <= 0 }
+	case ">":
+		return func(cmp int) bool { return cmp > 0 }
+	default:
+		return func(cmp int) bool { return cmp >= 0 }
+	}
+}
+
+// derefTypeOrNil 返回指针指向的基础类型，typ 为 nil 时返回 nil
+func derefTypeOrNil(typ reflect.Type) reflect.Type {
+	if typ == nil {
+		return nil
+	}
+	return derefType(typ)
+}
@@ -0,0 +1,447 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// exprTokenKind 词法单元类型
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp // 运算符与标点
+)
+
+// exprToken 词法单元
+type exprToken struct {
+	kind exprTokenKind
+	text string // 标识符/运算符原文，字符串为解码后的内容
+	num  float64
+	pos  int // 在表达式中的字节偏移
+}
+
+// exprOperators 按长度降序排列，保证优先匹配较长的运算符
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<>", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ","}
+
+// lexExpr 将表达式拆分为词法单元
+func lexExpr(src string) ([]exprToken, error) {
+	tokens := make([]exprToken, 0)
+	for i := 0; i < len(src); {
+		ch := rune(src[i])
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '"' || ch == '\'':
+			text, next, err := lexExprString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: text, pos: i})
+			i = next
+		case ch >= '0' && ch <= '9' || ch == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			num, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid number %q at %d", errorx.InvalidExpressionError, src[start:i], start)
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: src[start:i], num: num, pos: start})
+		case ch == '_' || unicode.IsLetter(ch):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '.' || src[i] >= '0' && src[i] <= '9' || unicode.IsLetter(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q at %d", errorx.InvalidExpressionError, ch, i)
+			}
+		}
+	}
+
+	return append(tokens, exprToken{kind: tokEOF, pos: len(src)}), nil
+}
+
+// lexExprString 读取以单引号或双引号包裹的字符串，支持 \" \' \\ \n \t 转义
+func lexExprString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var builder strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case quote:
+			return builder.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(src) {
+				break
+			}
+			i++
+			switch src[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			default:
+				builder.WriteByte(src[i])
+			}
+		default:
+			builder.WriteByte(src[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated string at %d", errorx.InvalidExpressionError, start)
+}
+
+// exprNode 语法树节点
+type exprNode interface {
+	position() int
+}
+
+// exprLiteral 字面量：float64、string、bool 或 nil
+type exprLiteral struct {
+	pos   int
+	value any
+}
+
+// exprField 字段引用，path 为以 . 分隔的嵌套字段
+type exprField struct {
+	pos  int
+	path []string
+}
+
+// exprUnary 一元运算
+type exprUnary struct {
+	pos int
+	op  string
+	x   exprNode
+}
+
+// exprBinary 二元运算
+type exprBinary struct {
+	pos  int
+	op   string
+	x, y exprNode
+}
+
+// exprCall 函数调用
+type exprCall struct {
+	pos  int
+	name string
+	args []exprNode
+}
+
+// exprListLit 列表字面量
+type exprListLit struct {
+	pos   int
+	items []exprNode
+}
+
+func (n *exprLiteral) position() int { return n.pos }
+func (n *exprField) position() int   { return n.pos }
+func (n *exprUnary) position() int   { return n.pos }
+func (n *exprBinary) position() int  { return n.pos }
+func (n *exprCall) position() int    { return n.pos }
+func (n *exprListLit) position() int { return n.pos }
+
+// exprParser 递归下降解析器
+//
+// 优先级从低到高：
+//
+//	or:         and ( ("||" | "or") and )*
+//	and:        cmp ( ("&&" | "and") cmp )*
+//	cmp:        add [ ("==" | "!=" | "<>" | "<" | "<=" | ">" | ">=" | "in" | "not in" |
+//	                  "startsWith" | "endsWith" | "contains" | "matches") add ]
+//	add:        mul ( ("+" | "-") mul )*
+//	mul:        unary ( ("*" | "/" | "%") unary )*
+//	unary:      ("!" | "not" | "-") unary | primary
+//	primary:    number | string | true | false | nil | field | func "(" args ")" | "(" or ")" | "[" args "]"
+type exprParser struct {
+	tokens []exprToken
+	cur    int
+}
+
+// exprKeywordOps 以标识符形式出现的二元运算符
+var exprKeywordOps = map[string]bool{
+	"in": true, "startsWith": true, "endsWith": true, "contains": true, "matches": true,
+}
+
+// parseExpr 解析表达式为语法树
+func parseExpr(src string) (exprNode, error) {
+	tokens, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+
+	return node, nil
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.cur]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.cur]
+	if tok.kind != tokEOF {
+		p.cur++
+	}
+	return tok
+}
+
+// accept 当前词法单元为给定的运算符或关键字时前进并返回 true
+func (p *exprParser) accept(texts ...string) (exprToken, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp && tok.kind != tokIdent {
+		return tok, false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.cur++
+			return tok, true
+		}
+	}
+	return tok, false
+}
+
+func (p *exprParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		tok := p.peek()
+		if tok.kind == tokEOF {
+			return fmt.Errorf("%w: expected %q at end of expression", errorx.InvalidExpressionError, text)
+		}
+		return fmt.Errorf("%w: expected %q, got %q at %d", errorx.InvalidExpressionError, text, tok.text, tok.pos)
+	}
+	return nil
+}
+
+func (p *exprParser) unexpected(tok exprToken) error {
+	if tok.kind == tokEOF {
+		return fmt.Errorf("%w: unexpected end of expression", errorx.InvalidExpressionError)
+	}
+	return fmt.Errorf("%w: unexpected %q at %d", errorx.InvalidExpressionError, tok.text, tok.pos)
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.accept("||", "or")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprBinary{pos: tok.pos, op: "||", x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	x, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.accept("&&", "and")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprBinary{pos: tok.pos, op: "&&", x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	x, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	op := ""
+	switch {
+	case tok.kind == tokOp && (tok.text == "==" || tok.text == "!=" || tok.text == "<>" ||
+		tok.text == "<" || tok.text == "<=" || tok.text == ">" || tok.text == ">="):
+		op = tok.text
+		if op == "<>" {
+			op = "!="
+		}
+		p.cur++
+	case tok.kind == tokIdent && exprKeywordOps[tok.text]:
+		op = tok.text
+		p.cur++
+	case tok.kind == tokIdent && tok.text == "not" && p.tokens[p.cur+1].kind == tokIdent && p.tokens[p.cur+1].text == "in":
+		op = "not in"
+		p.cur += 2
+	default:
+		return x, nil
+	}
+
+	y, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &exprBinary{pos: tok.pos, op: op, x: x, y: y}, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	x, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.accept("+", "-")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprBinary{pos: tok.pos, op: tok.text, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.accept("*", "/", "%")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &exprBinary{pos: tok.pos, op: tok.text, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	tok := p.peek()
+	if tok.kind == tokOp && (tok.text == "!" || tok.text == "-") || tok.kind == tokIdent && tok.text == "not" {
+		p.cur++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		op := tok.text
+		if op == "not" {
+			op = "!"
+		}
+		return &exprUnary{pos: tok.pos, op: op, x: x}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return &exprLiteral{pos: tok.pos, value: tok.num}, nil
+	case tokString:
+		return &exprLiteral{pos: tok.pos, value: tok.text}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return &exprLiteral{pos: tok.pos, value: true}, nil
+		case "false":
+			return &exprLiteral{pos: tok.pos, value: false}, nil
+		case "nil", "null":
+			return &exprLiteral{pos: tok.pos, value: nil}, nil
+		}
+		if next := p.peek(); next.kind == tokOp && next.text == "(" {
+			p.cur++
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			return &exprCall{pos: tok.pos, name: tok.text, args: args}, nil
+		}
+		path := strings.Split(tok.text, ".")
+		for _, part := range path {
+			if part == "" {
+				return nil, fmt.Errorf("%w: invalid field %q at %d", errorx.InvalidExpressionError, tok.text, tok.pos)
+			}
+		}
+		return &exprField{pos: tok.pos, path: path}, nil
+	case tokOp:
+		switch tok.text {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			items, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return &exprListLit{pos: tok.pos, items: items}, nil
+		}
+	}
+
+	return nil, p.unexpected(tok)
+}
+
+// parseArgs 解析以逗号分隔、以 closing 结尾的表达式列表（起始括号已被读取）
+func (p *exprParser) parseArgs(closing string) ([]exprNode, error) {
+	args := make([]exprNode, 0)
+	if _, ok := p.accept(closing); ok {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if _, ok := p.accept(","); ok {
+			continue
+		}
+		if err := p.expect(closing); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+}
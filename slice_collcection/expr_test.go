@@ -0,0 +1,178 @@
+package slice_collcection
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+type exprAddress struct {
+	City string `json:"city"`
+}
+
+type exprMember struct {
+	Name     string   `json:"name"`
+	Age      int      `json:"age"`
+	Status   string   `json:"status"`
+	VIP      bool     `json:"vip"`
+	Balance  *float64 `json:"balance"`
+	Tags     []string `json:"tags"`
+	Address  *exprAddress
+	JoinedAt time.Time
+}
+
+// TestFilterExpr tests the FilterExpr method of the Collection struct
+func TestFilterExpr(t *testing.T) {
+	balance := func(v float64) *float64 { return &v }
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	members := NewCollection([]exprMember{
+		{Name: "Alice", Age: 35, Status: "active", Balance: balance(120), Tags: []string{"beta"}, Address: &exprAddress{City: "Shanghai"}, JoinedAt: day(1)},
+		{Name: "Aaron", Age: 22, Status: "active", VIP: true, Tags: []string{"beta", "early"}, JoinedAt: day(10)},
+		{Name: "Bob", Age: 41, Status: "active", VIP: true, Balance: balance(-5), Address: &exprAddress{City: "Beijing"}, JoinedAt: day(20)},
+		{Name: "Amy", Age: 28, Status: "inactive", Balance: balance(0), JoinedAt: day(5)},
+	})
+
+	cases := []struct {
+		src      string
+		expected []string
+	}{
+		{`status == "active" && (age > 30 || vip) && name startsWith "A"`, []string{"Alice", "Aaron"}},
+		{`status in ["inactive", "banned"] or Age >= 41`, []string{"Bob", "Amy"}},
+		{`status not in ['active']`, []string{"Amy"}},
+		{`not vip and balance != nil`, []string{"Alice", "Amy"}},
+		{`balance == null`, []string{"Aaron"}},
+		{`balance * 2 + 10 > 200 || balance < 0`, []string{"Alice", "Bob"}},
+		{`age % 2 == 0 && -age < -25`, []string{"Amy"}},
+		{`tags contains "early" || "beta" in tags && len(tags) == 1`, []string{"Alice", "Aaron"}},
+		{`lower(name) endsWith "y" || upper(name) contains "OB"`, []string{"Bob", "Amy"}},
+		{`name + "!" == "Bob!"`, []string{"Bob"}},
+		{`name matches "^A[a-z]{2}$"`, []string{"Amy"}},
+		{`Address.city == "Beijing"`, []string{"Bob"}},
+		{`Address.city != "Beijing"`, []string{"Alice", "Aaron", "Amy"}},
+		{`JoinedAt >= "2024-03-05" && JoinedAt < "2024-03-20T00:00:00Z"`, []string{"Aaron", "Amy"}},
+		{`abs(balance) == 5 || trim("  x ") == "y"`, []string{"Bob"}},
+		{`age / 0 > 1`, []string{}},
+	}
+	for _, tc := range cases {
+		res, err := members.FilterExpr(tc.src)
+		if err != nil {
+			t.Errorf("%s: FilterExpr returned error: %v", tc.src, err)
+			continue
+		}
+		names, _ := res.PluckString("Name")
+		if !reflect.DeepEqual(names.Values(), tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.src, tc.expected, names.Values())
+		}
+	}
+
+	if members.Count() != 4 {
+		t.Error("FilterExpr should not modify original collection")
+	}
+}
+
+// TestCompileExpr tests the CompileExpr function and its compile cache
+func TestCompileExpr(t *testing.T) {
+	expr, err := CompileExpr[*exprMember](`vip && age < 30`)
+	if err != nil {
+		t.Fatalf("CompileExpr returned error: %v", err)
+	}
+	if !expr.Match(&exprMember{VIP: true, Age: 20}) || expr.Match(&exprMember{VIP: true, Age: 40}) {
+		t.Error("Unexpected match result")
+	}
+	if expr.Match(nil) {
+		t.Error("nil element should not match")
+	}
+	if expr.String() != `vip && age < 30` {
+		t.Errorf("Unexpected String(): %s", expr)
+	}
+
+	again, err := CompileExpr[*exprMember](`vip && age < 30`)
+	if err != nil || again.program != expr.program {
+		t.Error("Compiled expression should be cached")
+	}
+}
+
+// TestExprCacheBounded tests that the compile cache evicts the least recently used expressions
+func TestExprCacheBounded(t *testing.T) {
+	first, err := CompileExpr[exprMember](`age > 0`)
+	if err != nil {
+		t.Fatalf("CompileExpr returned error: %v", err)
+	}
+	for i := 0; i < maxExprCacheSize+10; i++ {
+		if _, err := CompileExpr[exprMember](fmt.Sprintf("age > %d", i+1)); err != nil {
+			t.Fatalf("CompileExpr returned error: %v", err)
+		}
+	}
+	if n := exprCache.len(); n > maxExprCacheSize {
+		t.Errorf("Cache should hold at most %d expressions, got %d", maxExprCacheSize, n)
+	}
+	again, err := CompileExpr[exprMember](`age > 0`)
+	if err != nil || again.program == first.program {
+		t.Error("Least recently used expression should be evicted")
+	}
+	if !first.Match(exprMember{Age: 1}) {
+		t.Error("Evicted expressions should keep working")
+	}
+}
+
+type exprTree []exprTree
+
+// TestExprSelfReferentialType tests compiling against a recursive slice type
+func TestExprSelfReferentialType(t *testing.T) {
+	type node struct {
+		Name     string
+		Children exprTree
+	}
+	c := NewCollection([]node{{Name: "leaf"}, {Name: "root", Children: exprTree{{}, {}}}})
+
+	res, err := c.FilterExpr(`len(Children) > 1`)
+	if err != nil {
+		t.Fatalf("FilterExpr returned error: %v", err)
+	}
+	if res.Count() != 1 || res.First().Name != "root" {
+		t.Errorf("Unexpected result %v", res.Values())
+	}
+}
+
+// TestFilterExprErrors tests the errors reported by the FilterExpr method of the Collection struct
+func TestFilterExprErrors(t *testing.T) {
+	members := NewCollection([]exprMember{{Name: "Alice", Age: 35, Status: "active"}})
+
+	cases := []struct {
+		src string
+		err error
+	}{
+		{`status == `, errorx.InvalidExpressionError},
+		{`(age > 1`, errorx.InvalidExpressionError},
+		{`age > 1 age`, errorx.InvalidExpressionError},
+		{`name == "unterminated`, errorx.InvalidExpressionError},
+		{`age # 1`, errorx.InvalidExpressionError},
+		{`unknown(name)`, errorx.InvalidExpressionError},
+		{`name matches "["`, errorx.InvalidExpressionError},
+		{`missing == 1`, errorx.FieldNotFoundError},
+		{`Address.zip == "1"`, errorx.FieldNotFoundError},
+		{`name.first == "A"`, errorx.InvalidTypeError},
+		{`age == "1"`, errorx.InvalidTypeError},
+		{`age > name`, errorx.InvalidTypeError},
+		{`age && vip`, errorx.InvalidTypeError},
+		{`name startsWith 1`, errorx.InvalidTypeError},
+		{`age in ["a", "b"]`, errorx.InvalidTypeError},
+		{`[1, "a"] contains 1`, errorx.InvalidTypeError},
+		{`name + 1 == "a"`, errorx.InvalidTypeError},
+		{`age + 1`, errorx.InvalidTypeError},
+		{`name matches status`, errorx.InvalidTypeError},
+	}
+	for _, tc := range cases {
+		if _, err := members.FilterExpr(tc.src); !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", tc.src, tc.err, err)
+		}
+	}
+
+	if _, err := NewCollection([]int{1}).FilterExpr(`x > 1`); !errors.Is(err, errorx.InvalidTypeError) {
+		t.Errorf("Expected InvalidTypeError for non-struct collection, got %v", err)
+	}
+}
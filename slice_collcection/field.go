@@ -0,0 +1,89 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// structFieldsCache 缓存结构体的字段解析结果，key 为 reflect.Type，value 为 map[string][]int
+var structFieldsCache sync.Map
+
+// fieldAccessor 通过缓存的字段下标读取元素的字段值
+type fieldAccessor struct {
+	name  string       // 查询时使用的字段名
+	index []int        // reflect.Value.FieldByIndex 使用的下标
+	typ   reflect.Type // 字段类型
+}
+
+// resolveField 在元素类型 elemType（结构体或结构体指针）中按字段名或 json tag 查找字段
+func resolveField(elemType reflect.Type, name string) (*fieldAccessor, error) {
+	structType := elemType
+	if structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: collection `T` must be a struct or pointer to struct", errorx.InvalidTypeError)
+	}
+
+	index, ok := structFieldIndexes(structType)[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s.%s", errorx.FieldNotFoundError, structType.Name(), name)
+	}
+
+	return &fieldAccessor{name: name, index: index, typ: structType.FieldByIndex(index).Type}, nil
+}
+
+// structFieldIndexes 返回结构体的字段名/json tag 到字段下标的映射（包括嵌入结构体提升的字段）
+// 字段名优先于 json tag
+func structFieldIndexes(structType reflect.Type) map[string][]int {
+	if cached, ok := structFieldsCache.Load(structType); ok {
+		return cached.(map[string][]int)
+	}
+
+	indexes := make(map[string][]int)
+	tags := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+		if _, exists := indexes[field.Name]; !exists {
+			indexes[field.Name] = field.Index
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			if _, exists := tags[tag]; !exists {
+				tags[tag] = field.Index
+			}
+		}
+	}
+	for tag, index := range tags {
+		if _, exists := indexes[tag]; !exists {
+			indexes[tag] = index
+		}
+	}
+
+	cached, _ := structFieldsCache.LoadOrStore(structType, indexes)
+	return cached.(map[string][]int)
+}
+
+// get 读取元素的字段值，元素为 nil 指针或经过 nil 的嵌入指针时返回 false
+func (f *fieldAccessor) get(item any) (reflect.Value, bool) {
+	val := reflect.ValueOf(item)
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+
+	field, err := val.FieldByIndexErr(f.index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return field, true
+}
@@ -0,0 +1,298 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FuzzyAlgorithm 模糊匹配的相似度算法，相似度范围为 [0, 1]，1 表示完全相同
+type FuzzyAlgorithm int
+
+const (
+	FuzzyLevenshtein FuzzyAlgorithm = iota // 编辑距离（插入、删除、替换），相似度为 1 - 距离/较长字符串的长度
+	FuzzyDamerau                           // 在编辑距离基础上允许相邻字符交换（optimal string alignment），适合键盘输入错误
+	FuzzyJaroWinkler                       // Jaro-Winkler，对相同前缀加分，适合人名等短字符串
+	FuzzyNGram                             // 二元组（bigram）的 Dice 系数，对词序变化不敏感，适合较长的文本
+)
+
+// FuzzyMatch 模糊匹配的结果
+type FuzzyMatch[T any] struct {
+	Item  T       `json:"item"`  // 元素
+	Index int     `json:"index"` // 元素在原 Collection 中的下标
+	Score float64 `json:"score"` // 相似度
+}
+
+// FuzzyCluster DedupeFuzzy 的结果，Members 包含 Representative 本身
+type FuzzyCluster[T any] struct {
+	Representative T   `json:"representative"` // 簇中第一个出现的元素
+	Members        []T `json:"members"`        // 簇中的所有元素，保持原有顺序
+}
+
+// FuzzyOption 模糊匹配选项
+type FuzzyOption func(*fuzzyOptions)
+
+// fuzzyOptions 模糊匹配选项
+type fuzzyOptions struct {
+	algorithm     FuzzyAlgorithm
+	threshold     float64
+	topK          int
+	caseSensitive bool
+}
+
+// WithFuzzyAlgorithm 设置相似度算法，默认为 FuzzyLevenshtein
+func WithFuzzyAlgorithm(algorithm FuzzyAlgorithm) FuzzyOption {
+	return func(o *fuzzyOptions) {
+		o.algorithm = algorithm
+	}
+}
+
+// WithFuzzyThreshold 设置最低相似度，FuzzyFind 默认为 0（不过滤），DedupeFuzzy 默认为 0.85
+func WithFuzzyThreshold(threshold float64) FuzzyOption {
+	return func(o *fuzzyOptions) {
+		o.threshold = threshold
+	}
+}
+
+// WithFuzzyTopK 最多返回相似度最高的 k 个结果，默认返回全部
+func WithFuzzyTopK(k int) FuzzyOption {
+	return func(o *fuzzyOptions) {
+		o.topK = k
+	}
+}
+
+// WithFuzzyCaseSensitive 区分大小写，默认比较前统一转为小写
+func WithFuzzyCaseSensitive() FuzzyOption {
+	return func(o *fuzzyOptions) {
+		o.caseSensitive = true
+	}
+}
+
+// FuzzyFind 按与 query 的相似度从高到低返回不低于阈值的元素，相似度相同时保持原有顺序
+// extractor 返回用于比较的文本；为 nil 时 string 元素直接使用，其它类型使用 fmt.Sprint
+//
+// 使用示例：
+//
+//	matches := slice_collcection.FuzzyFind(customers, "Jonh Smtih", func(c Customer) string { return c.Name },
+//	    slice_collcection.WithFuzzyAlgorithm(slice_collcection.FuzzyJaroWinkler),
+//	    slice_collcection.WithFuzzyThreshold(0.8),
+//	    slice_collcection.WithFuzzyTopK(5))
+func FuzzyFind[T any](c *Collection[T], query string, extractor func(item T) string, opts ...FuzzyOption) *Collection[FuzzyMatch[T]] {
+	o := newFuzzyOptions(0, opts)
+	extract := fuzzyExtractor(extractor)
+	target := o.normalize(query)
+
+	matches := make([]FuzzyMatch[T], 0)
+	for i, item := range c.value {
+		score := o.algorithm.similarity(target, o.normalize(extract(item)))
+		if score >= o.threshold {
+			matches = append(matches, FuzzyMatch[T]{Item: item, Index: i, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if o.topK > 0 && o.topK < len(matches) {
+		matches = matches[:o.topK]
+	}
+
+	return NewCollection(matches)
+}
+
+// DedupeFuzzy 将相似度不低于阈值（默认 0.85）的元素聚为一簇，用于合并近似重复的名称
+// 按原有顺序依次处理，元素加入第一个与其代表元素足够相似的簇，否则成为新簇的代表元素；复杂度 O(n·簇数)
+func DedupeFuzzy[T any](c *Collection[T], extractor func(item T) string, opts ...FuzzyOption) *Collection[FuzzyCluster[T]] {
+	o := newFuzzyOptions(0.85, opts)
+	extract := fuzzyExtractor(extractor)
+
+	clusters := make([]FuzzyCluster[T], 0)
+	keys := make([][]rune, 0)
+	for _, item := range c.value {
+		key := o.normalize(extract(item))
+		joined := false
+		for i := range clusters {
+			if o.algorithm.similarity(keys[i], key) >= o.threshold {
+				clusters[i].Members = append(clusters[i].Members, item)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			clusters = append(clusters, FuzzyCluster[T]{Representative: item, Members: []T{item}})
+			keys = append(keys, key)
+		}
+	}
+
+	return NewCollection(clusters)
+}
+
+// Similarity 返回两个字符串的相似度（区分大小写）
+func (a FuzzyAlgorithm) Similarity(s, t string) float64 {
+	return a.similarity([]rune(s), []rune(t))
+}
+
+// similarity 按算法计算相似度
+func (a FuzzyAlgorithm) similarity(s, t []rune) float64 {
+	if len(s) == 0 && len(t) == 0 {
+		return 1
+	}
+	switch a {
+	case FuzzyDamerau:
+		return 1 - float64(damerauDistance(s, t))/float64(max(len(s), len(t)))
+	case FuzzyJaroWinkler:
+		return jaroWinkler(s, t)
+	case FuzzyNGram:
+		return bigramDice(s, t)
+	default:
+		return 1 - float64(levenshteinDistance(s, t))/float64(max(len(s), len(t)))
+	}
+}
+
+// newFuzzyOptions 应用选项
+func newFuzzyOptions(threshold float64, opts []FuzzyOption) *fuzzyOptions {
+	o := &fuzzyOptions{algorithm: FuzzyLevenshtein, threshold: threshold}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// normalize 按选项归一化文本
+func (o *fuzzyOptions) normalize(s string) []rune {
+	if !o.caseSensitive {
+		s = strings.ToLower(s)
+	}
+	return []rune(s)
+}
+
+// fuzzyExtractor 返回文本提取函数
+func fuzzyExtractor[T any](extractor func(item T) string) func(item T) string {
+	if extractor != nil {
+		return extractor
+	}
+	return func(item T) string {
+		if s, ok := any(item).(string); ok {
+			return s
+		}
+		return fmt.Sprint(item)
+	}
+}
+
+// levenshteinDistance 编辑距离，使用两行滚动数组
+func levenshteinDistance(s, t []rune) int {
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(t)]
+}
+
+// damerauDistance 允许相邻字符交换的编辑距离（optimal string alignment），使用三行滚动数组
+func damerauDistance(s, t []rune) int {
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(t)]
+}
+
+// jaroWinkler Jaro-Winkler 相似度，前缀最多计 4 个字符，缩放系数为 0.1
+func jaroWinkler(s, t []rune) float64 {
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+	window := max(max(len(s), len(t))/2-1, 0)
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions/2))/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// bigramDice 二元组的 Dice 系数 2|A∩B|/(|A|+|B|)，按多重集合计算；长度为 1 的字符串作为一个整体
+func bigramDice(s, t []rune) float64 {
+	grams := func(r []rune) map[string]int {
+		res := make(map[string]int)
+		if len(r) == 1 {
+			res[string(r)]++
+		}
+		for i := 0; i+1 < len(r); i++ {
+			res[string(r[i:i+2])]++
+		}
+		return res
+	}
+	gs, gt := grams(s), grams(t)
+	total, common := 0, 0
+	for g, n := range gs {
+		total += n
+		common += min(n, gt[g])
+	}
+	for _, n := range gt {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2 * float64(common) / float64(total)
+}
@@ -0,0 +1,92 @@
+package slice_collcection
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFuzzySimilarity(t *testing.T) {
+	cases := []struct {
+		algorithm FuzzyAlgorithm
+		s, t      string
+		expected  float64
+	}{
+		{FuzzyLevenshtein, "kitten", "sitting", 1 - 3.0/7},
+		{FuzzyLevenshtein, "", "", 1},
+		{FuzzyLevenshtein, "abc", "", 0},
+		{FuzzyLevenshtein, "张三丰", "张三", 1 - 1.0/3},
+		{FuzzyDamerau, "ca", "ac", 0.5},
+		{FuzzyLevenshtein, "ca", "ac", 0},
+		{FuzzyDamerau, "smtih", "smith", 0.8},
+		{FuzzyJaroWinkler, "MARTHA", "MARHTA", 0.961},
+		{FuzzyJaroWinkler, "DIXON", "DICKSONX", 0.813},
+		{FuzzyJaroWinkler, "abc", "xyz", 0},
+		{FuzzyNGram, "night", "nacht", 0.25},
+		{FuzzyNGram, "a", "a", 1},
+	}
+	for _, tc := range cases {
+		if got := tc.algorithm.Similarity(tc.s, tc.t); math.Abs(got-tc.expected) > 0.001 {
+			t.Errorf("Similarity(%d, %q, %q): expected %.3f, got %.3f", tc.algorithm, tc.s, tc.t, tc.expected, got)
+		}
+	}
+}
+
+func TestFuzzyFind(t *testing.T) {
+	names := NewCollection([]string{"Jon Smith", "John Smith", "Jane Smyth", "Bob Stone", "JOHN SMITH"})
+
+	matches := FuzzyFind(names, "jonh smith", nil, WithFuzzyAlgorithm(FuzzyDamerau), WithFuzzyThreshold(0.8)).Values()
+	got := make([]string, 0, len(matches))
+	for _, m := range matches {
+		got = append(got, m.Item)
+	}
+	// 相似度相同时保持原有顺序
+	if !reflect.DeepEqual(got, []string{"Jon Smith", "John Smith", "JOHN SMITH"}) {
+		t.Errorf("Unexpected matches %v", got)
+	}
+	if matches[1].Index != 1 || matches[0].Score != 0.9 {
+		t.Errorf("Unexpected matches %+v", matches)
+	}
+
+	// 区分大小写时大小写的差异也计入编辑距离
+	sensitive := FuzzyFind(names, "jonh smith", nil, WithFuzzyAlgorithm(FuzzyDamerau), WithFuzzyThreshold(0.8), WithFuzzyCaseSensitive())
+	if sensitive.Count() != 0 {
+		t.Errorf("Expected no case-sensitive matches, got %v", sensitive.Values())
+	}
+
+	top := FuzzyFind(names, "john", nil, WithFuzzyAlgorithm(FuzzyJaroWinkler), WithFuzzyTopK(2))
+	if top.Count() != 2 || top.First().Score < top.Index(1).Score {
+		t.Errorf("Unexpected top-k matches %v", top.Values())
+	}
+
+	type customer struct {
+		ID   int
+		Name string
+	}
+	customers := NewCollection([]customer{{1, "王小明"}, {2, "王晓明"}, {3, "李华"}})
+	found := FuzzyFind(customers, "王小明", func(c customer) string { return c.Name }, WithFuzzyThreshold(0.5))
+	if found.Count() != 2 || found.First().Item.ID != 1 || found.Index(1).Item.ID != 2 {
+		t.Errorf("Unexpected struct matches %v", found.Values())
+	}
+}
+
+func TestDedupeFuzzy(t *testing.T) {
+	companies := NewCollection([]string{"Acme Inc", "ACME Inc.", "Globex", "Acme Inc", "Globex Corp", "Initech"})
+
+	clusters := DedupeFuzzy(companies, nil, WithFuzzyAlgorithm(FuzzyJaroWinkler), WithFuzzyThreshold(0.9)).Values()
+	reps := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		reps = append(reps, cluster.Representative)
+	}
+	if !reflect.DeepEqual(reps, []string{"Acme Inc", "Globex", "Initech"}) {
+		t.Errorf("Unexpected representatives %v", reps)
+	}
+	if !reflect.DeepEqual(clusters[0].Members, []string{"Acme Inc", "ACME Inc.", "Acme Inc"}) {
+		t.Errorf("Unexpected members %v", clusters[0].Members)
+	}
+
+	// 默认阈值 0.85 的编辑距离相似度不会合并 Globex 与 Globex Corp
+	if n := DedupeFuzzy(companies, nil).Count(); n != 4 {
+		t.Errorf("Expected 4 clusters, got %d", n)
+	}
+}
@@ -0,0 +1,251 @@
+package slice_collcection
+
+// JoinKind 连接类型
+type JoinKind int
+
+const (
+	JoinInner JoinKind = iota // 只保留两侧都匹配的行
+	JoinLeft                  // 保留左侧所有行，右侧无匹配时 Right 为 nil
+	JoinRight                 // 保留右侧所有行，左侧无匹配时 Left 为 nil
+	JoinFull                  // 保留两侧所有行
+	JoinAnti                  // 只保留右侧没有匹配的左侧行
+)
+
+// JoinRow 连接结果的一行，未匹配的一侧为 nil
+// Left/Right 指向元素的副本，修改它们不会影响原 Collection
+type JoinRow[A any, B any] struct {
+	Left  *A `json:"left"`
+	Right *B `json:"right"`
+}
+
+// JoinOption 连接选项
+type JoinOption[K any] func(*joinOptions[K])
+
+// joinOptions 连接选项
+type joinOptions[K any] struct {
+	keyCompare func(a, b K) int // 不为 nil 时尝试使用 sort-merge
+}
+
+// WithSortMerge 两侧已按 key 升序排列时使用 sort-merge 连接，不再构建哈希表
+// compareFunc 返回 0 时视为 key 相等，需要与 key 的 == 语义一致；任一侧不是按 compareFunc 升序排列时自动退回哈希连接，结果依然正确；
+// 使用 sort-merge 时结果按 key 的顺序输出（全连接中未匹配的右侧行按 key 穿插在结果中，而不是追加在末尾）
+func WithSortMerge[K any](compareFunc func(a, b K) int) JoinOption[K] {
+	return func(o *joinOptions[K]) {
+		o.keyCompare = compareFunc
+	}
+}
+
+// InnerJoin 内连接，按 leftKey/rightKey 提取的 key 相等进行匹配（哈希连接，O(n+m)）
+// 结果按左侧顺序输出，同一左侧行的多个匹配按右侧顺序输出
+func InnerJoin[A any, B any, K comparable](left *Collection[A], right *Collection[B], leftKey func(A) K, rightKey func(B) K, opts ...JoinOption[K]) *Collection[JoinRow[A, B]] {
+	return JoinWith(JoinInner, left, right, leftKey, rightKey, newJoinRow[A, B], opts...)
+}
+
+// LeftJoin 左连接，左侧行没有匹配时 Right 为 nil
+func LeftJoin[A any, B any, K comparable](left *Collection[A], right *Collection[B], leftKey func(A) K, rightKey func(B) K, opts ...JoinOption[K]) *Collection[JoinRow[A, B]] {
+	return JoinWith(JoinLeft, left, right, leftKey, rightKey, newJoinRow[A, B], opts...)
+}
+
+// RightJoin 右连接，右侧行没有匹配时 Left 为 nil；结果按右侧顺序输出
+func RightJoin[A any, B any, K comparable](left *Collection[A], right *Collection[B], leftKey func(A) K, rightKey func(B) K, opts ...JoinOption[K]) *Collection[JoinRow[A, B]] {
+	return JoinWith(JoinRight, left, right, leftKey, rightKey, newJoinRow[A, B], opts...)
+}
+
+// FullJoin 全连接，先按左连接输出，再追加右侧未匹配的行
+func FullJoin[A any, B any, K comparable](left *Collection[A], right *Collection[B], leftKey func(A) K, rightKey func(B) K, opts ...JoinOption[K]) *Collection[JoinRow[A, B]] {
+	return JoinWith(JoinFull, left, right, leftKey, rightKey, newJoinRow[A, B], opts...)
+}
+
+// AntiJoin 反连接，返回右侧没有匹配的左侧元素（保留左侧的比较函数）
+func AntiJoin[A any, B any, K comparable](left *Collection[A], right *Collection[B], leftKey func(A) K, rightKey func(B) K, opts ...JoinOption[K]) *Collection[A] {
+	res := JoinWith(JoinAnti, left, right, leftKey, rightKey, func(a *A, _ *B) A { return *a }, opts...)
+	res.compareFunc = left.compareFunc
+
+	return res
+}
+
+// JoinWith 按 kind 连接两个 Collection，并使用 combine 将每一对匹配转换为结果元素
+// combine 中未匹配的一侧为 nil；两个参数都指向元素的副本
+//
+// 使用示例：
+//
+//	orders := slice_collcection.JoinWith(slice_collcection.JoinLeft, apiOrders, dbRows,
+//	    func(o Order) int64 { return o.ID },
+//	    func(r Row) int64 { return r.OrderID },
+//	    func(o *Order, r *Row) View { return NewView(o, r) })
+func JoinWith[A any, B any, K comparable, R any](kind JoinKind, left *Collection[A], right *Collection[B], leftKey func(A) K, rightKey func(B) K, combine func(a *A, b *B) R, opts ...JoinOption[K]) *Collection[R] {
+	options := &joinOptions[K]{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	res := make([]R, 0)
+	emit := func(a A, hasA bool, b B, hasB bool) {
+		var pa *A
+		var pb *B
+		if hasA {
+			pa = &a
+		}
+		if hasB {
+			pb = &b
+		}
+		res = append(res, combine(pa, pb))
+	}
+
+	leftKeys := make([]K, len(left.value))
+	for i, a := range left.value {
+		leftKeys[i] = leftKey(a)
+	}
+	rightKeys := make([]K, len(right.value))
+	for i, b := range right.value {
+		rightKeys[i] = rightKey(b)
+	}
+
+	if options.keyCompare != nil && isSortedKeys(leftKeys, options.keyCompare) && isSortedKeys(rightKeys, options.keyCompare) {
+		mergeJoin(kind, left.value, right.value, leftKeys, rightKeys, options.keyCompare, emit)
+	} else {
+		hashJoin(kind, left.value, right.value, leftKeys, rightKeys, emit)
+	}
+
+	return NewCollection(res)
+}
+
+// newJoinRow 默认的 combine，生成 JoinRow
+func newJoinRow[A any, B any](a *A, b *B) JoinRow[A, B] {
+	return JoinRow[A, B]{Left: a, Right: b}
+}
+
+// hashJoin 哈希连接：右连接对左侧建哈希表并按右侧顺序输出，其余对右侧建哈希表并按左侧顺序输出
+func hashJoin[A any, B any, K comparable](kind JoinKind, left []A, right []B, leftKeys, rightKeys []K, emit func(a A, hasA bool, b B, hasB bool)) {
+	var zeroA A
+	var zeroB B
+
+	if kind == JoinRight {
+		index := buildJoinIndex(leftKeys)
+		for j, b := range right {
+			matches := index[rightKeys[j]]
+			if len(matches) == 0 {
+				emit(zeroA, false, b, true)
+				continue
+			}
+			for _, i := range matches {
+				emit(left[i], true, b, true)
+			}
+		}
+		return
+	}
+
+	index := buildJoinIndex(rightKeys)
+	var matchedRight []bool
+	if kind == JoinFull {
+		matchedRight = make([]bool, len(right))
+	}
+	for i, a := range left {
+		matches := index[leftKeys[i]]
+		if kind == JoinAnti {
+			if len(matches) == 0 {
+				emit(a, true, zeroB, false)
+			}
+			continue
+		}
+		if len(matches) == 0 {
+			if kind == JoinLeft || kind == JoinFull {
+				emit(a, true, zeroB, false)
+			}
+			continue
+		}
+		for _, j := range matches {
+			emit(a, true, right[j], true)
+			if matchedRight != nil {
+				matchedRight[j] = true
+			}
+		}
+	}
+
+	for j, matched := range matchedRight {
+		if !matched {
+			emit(zeroA, false, right[j], true)
+		}
+	}
+}
+
+// mergeJoin sort-merge 连接，要求两侧都已按 compareFunc 升序排列
+func mergeJoin[A any, B any, K any](kind JoinKind, left []A, right []B, leftKeys, rightKeys []K, compareFunc func(a, b K) int, emit func(a A, hasA bool, b B, hasB bool)) {
+	var zeroA A
+	var zeroB B
+	keepLeft := kind == JoinLeft || kind == JoinFull || kind == JoinAnti
+	keepRight := kind == JoinRight || kind == JoinFull
+
+	i, j := 0, 0
+	for i < len(left) || j < len(right) {
+		cmp := 0
+		switch {
+		case j >= len(right):
+			cmp = -1
+		case i >= len(left):
+			cmp = 1
+		default:
+			cmp = compareFunc(leftKeys[i], rightKeys[j])
+		}
+
+		if cmp < 0 {
+			if keepLeft {
+				emit(left[i], true, zeroB, false)
+			}
+			i++
+			continue
+		}
+		if cmp > 0 {
+			if keepRight {
+				emit(zeroA, false, right[j], true)
+			}
+			j++
+			continue
+		}
+
+		// key 相等的一组，输出两侧的笛卡尔积
+		iEnd := i + 1
+		for iEnd < len(left) && compareFunc(leftKeys[iEnd], rightKeys[j]) == 0 {
+			iEnd++
+		}
+		jEnd := j + 1
+		for jEnd < len(right) && compareFunc(leftKeys[i], rightKeys[jEnd]) == 0 {
+			jEnd++
+		}
+		switch kind {
+		case JoinAnti:
+		case JoinRight:
+			for rj := j; rj < jEnd; rj++ {
+				for li := i; li < iEnd; li++ {
+					emit(left[li], true, right[rj], true)
+				}
+			}
+		default:
+			for li := i; li < iEnd; li++ {
+				for rj := j; rj < jEnd; rj++ {
+					emit(left[li], true, right[rj], true)
+				}
+			}
+		}
+		i, j = iEnd, jEnd
+	}
+}
+
+// buildJoinIndex 构建 key -> 下标列表 的哈希表，下标保持原有顺序
+func buildJoinIndex[K comparable](keys []K) map[K][]int {
+	index := make(map[K][]int, len(keys))
+	for i, k := range keys {
+		index[k] = append(index[k], i)
+	}
+	return index
+}
+
+// isSortedKeys 判断 keys 是否按 compareFunc 升序排列
+func isSortedKeys[K any](keys []K, compareFunc func(a, b K) int) bool {
+	for i := 1; i < len(keys); i++ {
+		if compareFunc(keys[i-1], keys[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
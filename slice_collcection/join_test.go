@@ -0,0 +1,135 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type joinOrder struct {
+	ID     int
+	UserID int
+}
+
+type joinUser struct {
+	ID   int
+	Name string
+}
+
+func orderUserKey(o joinOrder) int { return o.UserID }
+func userKey(u joinUser) int       { return u.ID }
+
+// TestHashJoins tests the hash based InnerJoin, LeftJoin, RightJoin, FullJoin and AntiJoin functions
+func TestHashJoins(t *testing.T) {
+	orders := NewCollection([]joinOrder{{1, 10}, {2, 20}, {3, 10}, {4, 40}})
+	users := NewCollection([]joinUser{{10, "ann"}, {20, "ben"}, {30, "cat"}, {20, "ben2"}})
+
+	cases := []struct {
+		name     string
+		rows     *Collection[JoinRow[joinOrder, joinUser]]
+		expected []string
+	}{
+		{"inner", InnerJoin(orders, users, orderUserKey, userKey), []string{"1:ann", "2:ben", "2:ben2", "3:ann"}},
+		{"left", LeftJoin(orders, users, orderUserKey, userKey), []string{"1:ann", "2:ben", "2:ben2", "3:ann", "4:-"}},
+		{"right", RightJoin(orders, users, orderUserKey, userKey), []string{"1:ann", "3:ann", "2:ben", "-:cat", "2:ben2"}},
+		{"full", FullJoin(orders, users, orderUserKey, userKey), []string{"1:ann", "2:ben", "2:ben2", "3:ann", "4:-", "-:cat"}},
+	}
+	for _, tc := range cases {
+		got := make([]string, 0, tc.rows.Count())
+		for _, row := range tc.rows.Values() {
+			left, right := "-", "-"
+			if row.Left != nil {
+				left = fmt.Sprint(row.Left.ID)
+			}
+			if row.Right != nil {
+				right = row.Right.Name
+			}
+			got = append(got, left+":"+right)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+
+	anti := AntiJoin(orders, users, orderUserKey, userKey)
+	if !reflect.DeepEqual(anti.Values(), []joinOrder{{4, 40}}) {
+		t.Errorf("anti: unexpected %v", anti.Values())
+	}
+}
+
+// TestJoinRowsAreCopies tests that JoinRow holds copies of the joined elements
+func TestJoinRowsAreCopies(t *testing.T) {
+	orders := NewCollection([]joinOrder{{1, 10}, {2, 20}, {3, 10}, {4, 40}})
+	users := NewCollection([]joinUser{{10, "ann"}, {20, "ben"}, {30, "cat"}, {20, "ben2"}})
+
+	rows := InnerJoin(orders, users, orderUserKey, userKey)
+	rows.Values()[0].Left.ID = 100
+	if orders.First().ID != 1 || rows.Values()[1].Left.ID != 2 {
+		t.Error("JoinRow should hold copies of the elements")
+	}
+}
+
+// TestJoinWithCombiner tests the JoinWith function
+func TestJoinWithCombiner(t *testing.T) {
+	orders := NewCollection([]joinOrder{{1, 10}, {2, 20}, {3, 10}, {4, 40}})
+	users := NewCollection([]joinUser{{10, "ann"}, {20, "ben"}, {30, "cat"}, {20, "ben2"}})
+
+	names := JoinWith(JoinLeft, orders, users, orderUserKey, userKey, func(o *joinOrder, u *joinUser) string {
+		if u == nil {
+			return fmt.Sprintf("%d:unknown", o.ID)
+		}
+		return fmt.Sprintf("%d:%s", o.ID, u.Name)
+	})
+	expected := []string{"1:ann", "2:ben", "2:ben2", "3:ann", "4:unknown"}
+	if !reflect.DeepEqual(names.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, names.Values())
+	}
+}
+
+// TestSortMergeJoin tests the join functions with the WithSortMerge option
+func TestSortMergeJoin(t *testing.T) {
+	orders := NewCollection([]joinOrder{{1, 10}, {3, 10}, {2, 20}, {4, 40}})
+	users := NewCollection([]joinUser{{10, "ann"}, {20, "ben"}, {20, "ben2"}, {30, "cat"}})
+	cmp := func(a, b int) int { return a - b }
+
+	cases := []struct {
+		name     string
+		rows     *Collection[JoinRow[joinOrder, joinUser]]
+		expected []string
+	}{
+		{"inner", InnerJoin(orders, users, orderUserKey, userKey, WithSortMerge(cmp)), []string{"1:ann", "3:ann", "2:ben", "2:ben2"}},
+		{"left", LeftJoin(orders, users, orderUserKey, userKey, WithSortMerge(cmp)), []string{"1:ann", "3:ann", "2:ben", "2:ben2", "4:-"}},
+		{"right", RightJoin(orders, users, orderUserKey, userKey, WithSortMerge(cmp)), []string{"1:ann", "3:ann", "2:ben", "2:ben2", "-:cat"}},
+		{"full", FullJoin(orders, users, orderUserKey, userKey, WithSortMerge(cmp)), []string{"1:ann", "3:ann", "2:ben", "2:ben2", "-:cat", "4:-"}},
+	}
+	for _, tc := range cases {
+		got := make([]string, 0, tc.rows.Count())
+		for _, row := range tc.rows.Values() {
+			left, right := "-", "-"
+			if row.Left != nil {
+				left = fmt.Sprint(row.Left.ID)
+			}
+			if row.Right != nil {
+				right = row.Right.Name
+			}
+			got = append(got, left+":"+right)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+
+	anti := AntiJoin(orders, users, orderUserKey, userKey, WithSortMerge(cmp))
+	if !reflect.DeepEqual(anti.Values(), []joinOrder{{4, 40}}) {
+		t.Errorf("anti: unexpected %v", anti.Values())
+	}
+
+	// 输入未排序时退回哈希连接
+	unsorted := NewCollection([]joinOrder{{2, 20}, {1, 10}})
+	names := JoinWith(JoinInner, unsorted, users, orderUserKey, userKey, func(o *joinOrder, u *joinUser) string {
+		return fmt.Sprintf("%d:%s", o.ID, u.Name)
+	}, WithSortMerge(cmp))
+	if expected := []string{"2:ben", "2:ben2", "1:ann"}; !reflect.DeepEqual(names.Values(), expected) {
+		t.Errorf("unsorted fallback: expected %v, got %v", expected, names.Values())
+	}
+}
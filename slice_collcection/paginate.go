@@ -0,0 +1,273 @@
+package slice_collcection
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// Paginator 偏移分页的结果，字段带有 json tag，可直接作为接口响应返回
+type Paginator[T any] struct {
+	Items       []T  `json:"items"`        // 当前页的元素
+	Total       int  `json:"total"`        // 元素总数
+	PerPage     int  `json:"per_page"`     // 每页数量
+	CurrentPage int  `json:"current_page"` // 当前页码，从 1 开始
+	LastPage    int  `json:"last_page"`    // 最后一页的页码，没有元素时为 1
+	From        int  `json:"from"`         // 当前页第一个元素的序号，从 1 开始，当前页为空时为 0
+	To          int  `json:"to"`           // 当前页最后一个元素的序号，当前页为空时为 0
+	HasMore     bool `json:"has_more"`     // 是否还有下一页
+}
+
+// CursorPage 游标分页的结果
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`                 // 当前页的元素
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页的游标，没有下一页时为空
+	HasMore    bool   `json:"has_more"`              // 是否还有下一页
+}
+
+// CursorOption 游标分页选项
+type CursorOption func(*cursorOptions)
+
+// cursorOptions 游标分页选项
+type cursorOptions struct {
+	secret []byte
+}
+
+// WithCursorSecret 设置游标签名使用的密钥
+// 未设置时使用进程启动时随机生成的密钥，游标在进程重启后失效；多实例部署时需要设置相同的密钥
+func WithCursorSecret(secret []byte) CursorOption {
+	return func(o *cursorOptions) {
+		o.secret = secret
+	}
+}
+
+var (
+	defaultCursorSecret     []byte
+	defaultCursorSecretOnce sync.Once
+)
+
+// cursorPayload 游标中保存的排序条件与最后一行的排序字段值
+type cursorPayload struct {
+	Keys   []string          `json:"k"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// Paginate 按页码分页，page 从 1 开始，返回带有总数、页码等信息的 Paginator
+// page 或 perPage 不大于 0 时返回 errorx.InvalidArgumentError；页码超出范围时返回空的 Items，不返回错误
+func (c *Collection[T]) Paginate(page int, perPage int) (*Paginator[T], error) {
+	if page <= 0 || perPage <= 0 {
+		return nil, fmt.Errorf("%w: page %d, perPage %d", errorx.InvalidArgumentError, page, perPage)
+	}
+
+	total := len(c.value)
+	paginator := &Paginator[T]{
+		Items:       make([]T, 0),
+		Total:       total,
+		PerPage:     perPage,
+		CurrentPage: page,
+		LastPage:    max((total+perPage-1)/perPage, 1),
+	}
+
+	start := (page - 1) * perPage
+	if start < total {
+		end := min(start+perPage, total)
+		paginator.Items = append(paginator.Items, c.value[start:end]...)
+		paginator.From = start + 1
+		paginator.To = end
+	}
+	paginator.HasMore = page < paginator.LastPage
+
+	return paginator, nil
+}
+
+// CursorPaginate 按排序字段进行游标（keyset）分页，cursor 为空时返回第一页
+//
+// orderKeys 为字段名或 json tag，前缀 "-" 表示降序，nil 值无论升序降序都排在最前。
+// 游标记录上一页最后一行的排序字段值并使用 HMAC 签名，被篡改或与 orderKeys 不一致时返回 errorx.InvalidCursorError，limit 不大于 0 或 orderKeys 为空时返回 errorx.InvalidArgumentError。
+// 与偏移分页不同，两次请求之间插入或删除元素不会导致重复或遗漏；
+// orderKeys 的组合需要唯一（通常以 ID 结尾），否则排序字段值相同的元素可能在翻页时被跳过。
+//
+// 使用示例：
+//
+//	page, err := orders.CursorPaginate([]string{"-CreatedAt", "ID"}, req.Cursor, 20)
+//	// 下一次请求传入 page.NextCursor
+func (c *Collection[T]) CursorPaginate(orderKeys []string, cursor string, limit int, opts ...CursorOption) (*CursorPage[T], error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit %d", errorx.InvalidArgumentError, limit)
+	}
+	if len(orderKeys) == 0 {
+		return nil, fmt.Errorf("%w: order keys is empty", errorx.InvalidArgumentError)
+	}
+	options := &cursorOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if len(options.secret) == 0 {
+		options.secret = cursorSecret()
+	}
+
+	orders := make([]queryOrder, 0, len(orderKeys))
+	for _, key := range orderKeys {
+		order, err := parseCursorOrder(c.typ, key)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	rows := make([]T, len(c.value))
+	copy(rows, c.value)
+	sort.SliceStable(rows, func(i, j int) bool {
+		return compareByOrders(orders, rows[i], rows[j]) < 0
+	})
+
+	start := 0
+	if cursor != "" {
+		values, oks, err := decodeCursor(cursor, orderKeys, orders, options.secret)
+		if err != nil {
+			return nil, err
+		}
+		// 从第一个排在游标之后的元素开始
+		start = sort.Search(len(rows), func(i int) bool {
+			for k, order := range orders {
+				v, ok := orderValue(order.field, rows[i])
+				if cmp := compareOrderValue(order, v, ok, values[k], oks[k]); cmp != 0 {
+					return cmp > 0
+				}
+			}
+			return false
+		})
+	}
+
+	end := min(start+limit, len(rows))
+	page := &CursorPage[T]{
+		Items:   append(make([]T, 0, end-start), rows[start:end]...),
+		HasMore: end < len(rows),
+	}
+	if page.HasMore {
+		next, err := encodeCursor(rows[end-1], orderKeys, orders, options.secret)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+
+	return page, nil
+}
+
+// parseCursorOrder 解析排序字段，前缀 "-" 表示降序
+func parseCursorOrder(typ reflect.Type, key string) (queryOrder, error) {
+	name, desc := strings.CutPrefix(key, "-")
+	accessor, err := resolveField(typ, name)
+	if err != nil {
+		return queryOrder{}, err
+	}
+	if _, err := orderableValue(accessor); err != nil {
+		return queryOrder{}, err
+	}
+
+	return queryOrder{field: accessor, desc: desc}, nil
+}
+
+// compareByOrders 按排序条件依次比较两个元素
+func compareByOrders(orders []queryOrder, a, b any) int {
+	for _, order := range orders {
+		va, okA := orderValue(order.field, a)
+		vb, okB := orderValue(order.field, b)
+		if cmp := compareOrderValue(order, va, okA, vb, okB); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// encodeCursor 将元素的排序字段值编码为签名后的游标：base64(payload).base64(hmac)
+func encodeCursor(item any, orderKeys []string, orders []queryOrder, secret []byte) (string, error) {
+	payload := cursorPayload{Keys: orderKeys, Values: make([]json.RawMessage, 0, len(orders))}
+	for _, order := range orders {
+		v, ok := orderValue(order.field, item)
+		if !ok {
+			payload.Values = append(payload.Values, json.RawMessage("null"))
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("%w: field %s: %v", errorx.InvalidCursorError, order.field.name, err)
+		}
+		payload.Values = append(payload.Values, raw)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errorx.InvalidCursorError, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(signCursor(data, secret)), nil
+}
+
+// decodeCursor 校验游标签名并按字段类型还原排序字段值，ok 为 false 表示值为 nil
+func decodeCursor(cursor string, orderKeys []string, orders []queryOrder, secret []byte) ([]any, []bool, error) {
+	encoded, signature, found := strings.Cut(cursor, ".")
+	if !found {
+		return nil, nil, fmt.Errorf("%w: malformed token", errorx.InvalidCursorError)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: malformed token", errorx.InvalidCursorError)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, signCursor(data, secret)) {
+		return nil, nil, fmt.Errorf("%w: signature mismatch", errorx.InvalidCursorError)
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errorx.InvalidCursorError, err)
+	}
+	if strings.Join(payload.Keys, ",") != strings.Join(orderKeys, ",") || len(payload.Values) != len(orders) {
+		return nil, nil, fmt.Errorf("%w: cursor was created with order keys %v", errorx.InvalidCursorError, payload.Keys)
+	}
+
+	values := make([]any, len(orders))
+	oks := make([]bool, len(orders))
+	for i, order := range orders {
+		if bytes.Equal(payload.Values[i], []byte("null")) {
+			continue
+		}
+		v := reflect.New(derefType(order.field.typ))
+		if err := json.Unmarshal(payload.Values[i], v.Interface()); err != nil {
+			return nil, nil, fmt.Errorf("%w: field %s: %v", errorx.InvalidCursorError, order.field.name, err)
+		}
+		values[i], oks[i] = v.Elem().Interface(), true
+	}
+
+	return values, oks, nil
+}
+
+// signCursor 计算游标内容的 HMAC-SHA256
+func signCursor(data []byte, secret []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// cursorSecret 返回进程内随机生成的默认密钥
+func cursorSecret() []byte {
+	defaultCursorSecretOnce.Do(func() {
+		defaultCursorSecret = make([]byte, 32)
+		if _, err := rand.Read(defaultCursorSecret); err != nil {
+			panic(fmt.Sprintf("generate cursor secret: %v", err))
+		}
+	})
+	return defaultCursorSecret
+}
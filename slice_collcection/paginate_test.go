@@ -0,0 +1,178 @@
+package slice_collcection
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+type pageOrder struct {
+	ID        int
+	CreatedAt time.Time `json:"created_at"`
+	Score     *int
+}
+
+func TestPaginate(t *testing.T) {
+	c := NewCollection([]int{1, 2, 3, 4, 5, 6, 7})
+
+	p, err := c.Paginate(2, 3)
+	if err != nil {
+		t.Fatalf("Paginate returned error: %v", err)
+	}
+	expected := &Paginator[int]{Items: []int{4, 5, 6}, Total: 7, PerPage: 3, CurrentPage: 2, LastPage: 3, From: 4, To: 6, HasMore: true}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, p)
+	}
+
+	last, _ := c.Paginate(3, 3)
+	if !reflect.DeepEqual(last.Items, []int{7}) || last.HasMore || last.From != 7 || last.To != 7 {
+		t.Errorf("Unexpected last page %+v", last)
+	}
+
+	beyond, _ := c.Paginate(5, 3)
+	if len(beyond.Items) != 0 || beyond.From != 0 || beyond.To != 0 || beyond.HasMore {
+		t.Errorf("Unexpected page beyond range %+v", beyond)
+	}
+
+	empty, _ := NewCollection[int](nil).Paginate(1, 10)
+	data, _ := json.Marshal(empty)
+	if string(data) != `{"items":[],"total":0,"per_page":10,"current_page":1,"last_page":1,"from":0,"to":0,"has_more":false}` {
+		t.Errorf("Unexpected json %s", data)
+	}
+
+	if _, err := c.Paginate(0, 3); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for invalid page, got %v", err)
+	}
+}
+
+func TestCursorPaginate(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := []pageOrder{
+		{ID: 1, CreatedAt: base},
+		{ID: 2, CreatedAt: base.Add(time.Hour)},
+		{ID: 3, CreatedAt: base.Add(time.Hour)},
+		{ID: 4, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 5, CreatedAt: base.Add(3 * time.Hour)},
+	}
+	keys := []string{"-created_at", "ID"}
+	ids := func(items []pageOrder) []int {
+		res := make([]int, 0, len(items))
+		for _, item := range items {
+			res = append(res, item.ID)
+		}
+		return res
+	}
+
+	first, err := NewCollection(orders).CursorPaginate(keys, "", 2)
+	if err != nil {
+		t.Fatalf("CursorPaginate returned error: %v", err)
+	}
+	if !reflect.DeepEqual(ids(first.Items), []int{5, 4}) || !first.HasMore || first.NextCursor == "" {
+		t.Fatalf("Unexpected first page %+v", first)
+	}
+
+	// 两次请求之间插入新元素，不影响后续页
+	orders = append(orders, pageOrder{ID: 6, CreatedAt: base.Add(4 * time.Hour)}, pageOrder{ID: 7, CreatedAt: base.Add(time.Hour)})
+	c := NewCollection(orders)
+
+	second, err := c.CursorPaginate(keys, first.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("CursorPaginate returned error: %v", err)
+	}
+	if !reflect.DeepEqual(ids(second.Items), []int{2, 3}) || !second.HasMore {
+		t.Fatalf("Unexpected second page %+v", second)
+	}
+
+	third, _ := c.CursorPaginate(keys, second.NextCursor, 2)
+	if !reflect.DeepEqual(ids(third.Items), []int{7, 1}) || third.HasMore || third.NextCursor != "" {
+		t.Errorf("Unexpected third page %+v", third)
+	}
+}
+
+func TestCursorPaginateNilValues(t *testing.T) {
+	one, two := 1, 2
+	c := NewCollection([]pageOrder{{ID: 1, Score: &two}, {ID: 2}, {ID: 3, Score: &one}, {ID: 4}})
+
+	var got []int
+	cursor := ""
+	for {
+		page, err := c.CursorPaginate([]string{"Score", "ID"}, cursor, 1)
+		if err != nil {
+			t.Fatalf("CursorPaginate returned error: %v", err)
+		}
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+		}
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if !reflect.DeepEqual(got, []int{2, 4, 3, 1}) {
+		t.Errorf("Unexpected order %v", got)
+	}
+
+	// 降序时 nil 值同样排在最前
+	got = got[:0]
+	cursor = ""
+	for {
+		page, err := c.CursorPaginate([]string{"-Score", "ID"}, cursor, 1)
+		if err != nil {
+			t.Fatalf("CursorPaginate returned error: %v", err)
+		}
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+		}
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if !reflect.DeepEqual(got, []int{2, 4, 1, 3}) {
+		t.Errorf("Unexpected descending order %v", got)
+	}
+}
+
+func TestCursorPaginateErrors(t *testing.T) {
+	c := NewCollection([]pageOrder{{ID: 1}, {ID: 2}, {ID: 3}})
+	page, _ := c.CursorPaginate([]string{"ID"}, "", 1)
+
+	// 篡改游标内容
+	encoded, signature, _ := strings.Cut(page.NextCursor, ".")
+	data, _ := base64.RawURLEncoding.DecodeString(encoded)
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(data), "[1]", "[2]", 1))) + "." + signature
+	if _, err := c.CursorPaginate([]string{"ID"}, tampered, 1); !errors.Is(err, errorx.InvalidCursorError) {
+		t.Errorf("Expected InvalidCursorError for tampered cursor, got %v", err)
+	}
+	if _, err := c.CursorPaginate([]string{"ID"}, "garbage", 1); !errors.Is(err, errorx.InvalidCursorError) {
+		t.Errorf("Expected InvalidCursorError for malformed cursor, got %v", err)
+	}
+	if _, err := c.CursorPaginate([]string{"-ID"}, page.NextCursor, 1); !errors.Is(err, errorx.InvalidCursorError) {
+		t.Errorf("Expected InvalidCursorError for mismatched keys, got %v", err)
+	}
+	if _, err := c.CursorPaginate([]string{"ID"}, page.NextCursor, 1, WithCursorSecret([]byte("other"))); !errors.Is(err, errorx.InvalidCursorError) {
+		t.Errorf("Expected InvalidCursorError for different secret, got %v", err)
+	}
+	if _, err := c.CursorPaginate([]string{"Missing"}, "", 1); !errors.Is(err, errorx.FieldNotFoundError) {
+		t.Errorf("Expected FieldNotFoundError, got %v", err)
+	}
+	if _, err := c.CursorPaginate([]string{"ID"}, "", 0); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for invalid limit, got %v", err)
+	}
+	if _, err := c.CursorPaginate(nil, "", 1); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for empty order keys, got %v", err)
+	}
+
+	secret := WithCursorSecret([]byte("shared"))
+	signed, _ := c.CursorPaginate([]string{"ID"}, "", 1, secret)
+	next, err := c.CursorPaginate([]string{"ID"}, signed.NextCursor, 1, secret)
+	if err != nil || next.Items[0].ID != 2 {
+		t.Errorf("Unexpected page with shared secret %+v, %v", next, err)
+	}
+}
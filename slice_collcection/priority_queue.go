@@ -0,0 +1,334 @@
+package slice_collcection
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// PriorityQueue 基于二叉堆的优先队列，比较函数与 Collection.SetCompare 相同（-1：小于，0：等于，1：大于）
+// Pop 按比较函数从大到小弹出元素（与 Sort() 后 Pop() 的结果一致），优先级相同时先入队的先弹出；
+// 需要从小到大弹出时传入相反的比较函数。PriorityQueue 不是并发安全的，并发场景使用 BlockingPriorityQueue
+type PriorityQueue[T any] struct {
+	items       []*PriorityHandle[T]
+	compareFunc func(a any, b any) int
+	seq         uint64
+}
+
+// PriorityHandle 入队元素的句柄，用于 Update 与 Remove
+type PriorityHandle[T any] struct {
+	value T
+	index int // 在堆中的下标，出队后为 -1
+	seq   uint64
+	queue *PriorityQueue[T]
+}
+
+// Value 返回句柄对应的元素
+func (h *PriorityHandle[T]) Value() T {
+	return h.value
+}
+
+// InQueue 元素是否仍在队列中
+func (h *PriorityHandle[T]) InQueue() bool {
+	return h.index >= 0
+}
+
+// NewPriorityQueue 创建优先队列，compareFunc 为 nil 时使用元素类型的默认比较函数（与 NewCollection 相同）
+// 没有可用的比较函数时返回 errorx.NoComparableError；values 使用 O(n) 的建堆
+//
+// 使用示例：
+//
+//	pq, _ := slice_collcection.NewPriorityQueue(func(a, b any) int {
+//	    return b.(Job).RunAt.Compare(a.(Job).RunAt) // 越早执行的优先级越高
+//	})
+//	h := pq.Push(job)
+//	pq.Update(h, rescheduled)
+func NewPriorityQueue[T any](compareFunc func(a any, b any) int, values ...T) (*PriorityQueue[T], error) {
+	if compareFunc == nil {
+		var zero T
+		compareFunc = utils.NewCompareFuncOf(reflect.TypeOf(zero))
+	}
+	if compareFunc == nil {
+		return nil, errorx.NoComparableError
+	}
+
+	pq := &PriorityQueue[T]{compareFunc: compareFunc, items: make([]*PriorityHandle[T], 0, len(values))}
+	for _, v := range values {
+		pq.items = append(pq.items, pq.newHandle(v, len(pq.items)))
+	}
+	pq.heapify()
+
+	return pq, nil
+}
+
+// NewPriorityQueueFrom 使用 Collection 的元素与比较函数创建优先队列，O(n) 建堆，不修改原 Collection
+func NewPriorityQueueFrom[T any](c *Collection[T]) (*PriorityQueue[T], error) {
+	if !c.isComparable() {
+		return nil, errorx.NoComparableError
+	}
+	return NewPriorityQueue(c.compareFunc, c.value...)
+}
+
+// SetCompare 设置比较函数并重新建堆，compareFunc 为 nil 时不做修改
+func (pq *PriorityQueue[T]) SetCompare(compareFunc func(a any, b any) int) *PriorityQueue[T] {
+	if compareFunc != nil {
+		pq.compareFunc = compareFunc
+		pq.heapify()
+	}
+	return pq
+}
+
+// Push 入队，返回元素的句柄
+func (pq *PriorityQueue[T]) Push(value T) *PriorityHandle[T] {
+	h := pq.newHandle(value, len(pq.items))
+	pq.items = append(pq.items, h)
+	pq.up(h.index)
+	return h
+}
+
+// Pop 弹出优先级最高的元素，队列为空时返回 false
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.removeAt(0).value, true
+}
+
+// Peek 返回优先级最高的元素但不出队，队列为空时返回 false
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.items[0].value, true
+}
+
+// Len 返回队列中的元素数量
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// IsEmpty 队列是否为空
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return len(pq.items) == 0
+}
+
+// Update 修改句柄对应的元素并调整其位置，O(log n)；元素已出队或句柄不属于该队列时返回 false
+func (pq *PriorityQueue[T]) Update(h *PriorityHandle[T], value T) bool {
+	if !pq.owns(h) {
+		return false
+	}
+	h.value = value
+	if !pq.up(h.index) {
+		pq.down(h.index)
+	}
+	return true
+}
+
+// Remove 将句柄对应的元素移出队列，O(log n)；元素已出队或句柄不属于该队列时返回 false
+func (pq *PriorityQueue[T]) Remove(h *PriorityHandle[T]) (T, bool) {
+	if !pq.owns(h) {
+		var zero T
+		return zero, false
+	}
+	return pq.removeAt(h.index).value, true
+}
+
+// ToCollection 按出队顺序返回所有元素组成的 Collection，不修改队列
+func (pq *PriorityQueue[T]) ToCollection() *Collection[T] {
+	handles := make([]*PriorityHandle[T], len(pq.items))
+	copy(handles, pq.items)
+	sort.Slice(handles, func(i, j int) bool {
+		return pq.before(handles[i], handles[j])
+	})
+
+	values := make([]T, 0, len(handles))
+	for _, h := range handles {
+		values = append(values, h.value)
+	}
+	coll := NewCollection(values)
+	coll.compareFunc = pq.compareFunc
+
+	return coll
+}
+
+// newHandle 创建句柄
+func (pq *PriorityQueue[T]) newHandle(value T, index int) *PriorityHandle[T] {
+	pq.seq++
+	return &PriorityHandle[T]{value: value, index: index, seq: pq.seq, queue: pq}
+}
+
+// owns 句柄是否属于该队列且仍在队列中
+func (pq *PriorityQueue[T]) owns(h *PriorityHandle[T]) bool {
+	return h != nil && h.queue == pq && h.index >= 0 && h.index < len(pq.items) && pq.items[h.index] == h
+}
+
+// before a 是否应先于 b 出队
+func (pq *PriorityQueue[T]) before(a, b *PriorityHandle[T]) bool {
+	if c := pq.compareFunc(a.value, b.value); c != 0 {
+		return c > 0
+	}
+	return a.seq < b.seq
+}
+
+// heapify 自底向上建堆，O(n)
+func (pq *PriorityQueue[T]) heapify() {
+	for i := len(pq.items)/2 - 1; i >= 0; i-- {
+		pq.down(i)
+	}
+}
+
+// removeAt 移除第 i 个元素
+func (pq *PriorityQueue[T]) removeAt(i int) *PriorityHandle[T] {
+	last := len(pq.items) - 1
+	h := pq.items[i]
+	pq.swap(i, last)
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i < last && !pq.up(i) {
+		pq.down(i)
+	}
+	h.index = -1
+	return h
+}
+
+// up 上浮第 i 个元素，返回是否发生了移动
+func (pq *PriorityQueue[T]) up(i int) bool {
+	moved := false
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.before(pq.items[i], pq.items[parent]) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+		moved = true
+	}
+	return moved
+}
+
+// down 下沉第 i 个元素
+func (pq *PriorityQueue[T]) down(i int) {
+	for {
+		best := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(pq.items) && pq.before(pq.items[child], pq.items[best]) {
+				best = child
+			}
+		}
+		if best == i {
+			return
+		}
+		pq.swap(i, best)
+		i = best
+	}
+}
+
+// swap 交换两个元素并更新下标
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+// BlockingPriorityQueue 并发安全的优先队列，PopWait 在队列为空时阻塞等待
+// 句柄的 Value/InQueue 不加锁，并发修改同一元素时应以 Pop/Remove 的返回值为准
+type BlockingPriorityQueue[T any] struct {
+	mu     sync.Mutex
+	pq     *PriorityQueue[T]
+	notify chan struct{} // 有元素入队时关闭并替换，用于唤醒所有等待者
+}
+
+// NewBlockingPriorityQueue 使用已有的优先队列创建并发安全的优先队列，之后不应再直接使用 pq
+func NewBlockingPriorityQueue[T any](pq *PriorityQueue[T]) *BlockingPriorityQueue[T] {
+	return &BlockingPriorityQueue[T]{pq: pq, notify: make(chan struct{})}
+}
+
+// Push 入队并唤醒等待中的 PopWait，返回元素的句柄
+func (bq *BlockingPriorityQueue[T]) Push(value T) *PriorityHandle[T] {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	h := bq.pq.Push(value)
+	bq.wake()
+	return h
+}
+
+// Pop 弹出优先级最高的元素，队列为空时立即返回 false
+func (bq *BlockingPriorityQueue[T]) Pop() (T, bool) {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	return bq.pq.Pop()
+}
+
+// PopWait 弹出优先级最高的元素，队列为空时阻塞直到有元素入队或 ctx 结束（返回 ctx.Err()）
+func (bq *BlockingPriorityQueue[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		bq.mu.Lock()
+		if value, ok := bq.pq.Pop(); ok {
+			bq.mu.Unlock()
+			return value, nil
+		}
+		notify := bq.notify
+		bq.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// Peek 返回优先级最高的元素但不出队
+func (bq *BlockingPriorityQueue[T]) Peek() (T, bool) {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	return bq.pq.Peek()
+}
+
+// Len 返回队列中的元素数量
+func (bq *BlockingPriorityQueue[T]) Len() int {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	return bq.pq.Len()
+}
+
+// Update 修改句柄对应的元素并调整其位置
+func (bq *BlockingPriorityQueue[T]) Update(h *PriorityHandle[T], value T) bool {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	return bq.pq.Update(h, value)
+}
+
+// Remove 将句柄对应的元素移出队列
+func (bq *BlockingPriorityQueue[T]) Remove(h *PriorityHandle[T]) (T, bool) {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	return bq.pq.Remove(h)
+}
+
+// ToCollection 按出队顺序返回所有元素组成的 Collection，不修改队列
+func (bq *BlockingPriorityQueue[T]) ToCollection() *Collection[T] {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	return bq.pq.ToCollection()
+}
+
+// wake 唤醒所有等待者，调用方需要持有锁
+func (bq *BlockingPriorityQueue[T]) wake() {
+	close(bq.notify)
+	bq.notify = make(chan struct{})
+}
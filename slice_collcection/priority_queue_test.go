@@ -0,0 +1,192 @@
+package slice_collcection
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+type pqJob struct {
+	Name     string
+	Priority int
+}
+
+func pqJobCompare(a, b any) int {
+	return a.(pqJob).Priority - b.(pqJob).Priority
+}
+
+func drain[T any](pq *PriorityQueue[T]) []T {
+	res := make([]T, 0, pq.Len())
+	for {
+		v, ok := pq.Pop()
+		if !ok {
+			return res
+		}
+		res = append(res, v)
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	pq, err := NewPriorityQueue[int](nil, 5, 1, 9, 3)
+	if err != nil {
+		t.Fatalf("NewPriorityQueue returned error: %v", err)
+	}
+	pq.Push(7)
+	if top, ok := pq.Peek(); !ok || top != 9 || pq.Len() != 5 {
+		t.Errorf("Unexpected peek %v %v", top, ok)
+	}
+	if got := drain(pq); !reflect.DeepEqual(got, []int{9, 7, 5, 3, 1}) {
+		t.Errorf("Unexpected pop order %v", got)
+	}
+	if _, ok := pq.Pop(); ok || !pq.IsEmpty() {
+		t.Error("Pop on empty queue should return false")
+	}
+
+	// 相反的比较函数得到从小到大的顺序
+	pq.SetCompare(func(a, b any) int { return b.(int) - a.(int) })
+	for _, v := range []int{4, 2, 8} {
+		pq.Push(v)
+	}
+	if got := drain(pq); !reflect.DeepEqual(got, []int{2, 4, 8}) {
+		t.Errorf("Unexpected min-first order %v", got)
+	}
+
+	if _, err := NewPriorityQueue[pqJob](nil); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+}
+
+func TestPriorityQueueHandles(t *testing.T) {
+	pq, _ := NewPriorityQueue[pqJob](pqJobCompare)
+	a := pq.Push(pqJob{"a", 1})
+	b := pq.Push(pqJob{"b", 5})
+	c := pq.Push(pqJob{"c", 5})
+	d := pq.Push(pqJob{"d", 3})
+
+	if !pq.Update(a, pqJob{"a", 10}) {
+		t.Error("Update should succeed")
+	}
+	if removed, ok := pq.Remove(d); !ok || removed.Name != "d" || d.InQueue() {
+		t.Errorf("Unexpected remove result %v %v", removed, ok)
+	}
+	if _, ok := pq.Remove(d); ok {
+		t.Error("Removing twice should fail")
+	}
+	if got := pq.ToCollection().Values(); !reflect.DeepEqual(got, []pqJob{{"a", 10}, {"b", 5}, {"c", 5}}) {
+		t.Errorf("Unexpected collection %v", got)
+	}
+
+	pq.Update(b, pqJob{"b", 0})
+	// 优先级相同时先入队的先出队
+	names := make([]string, 0)
+	for _, job := range drain(pq) {
+		names = append(names, job.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "c", "b"}) {
+		t.Errorf("Unexpected order %v", names)
+	}
+	if pq.Update(c, pqJob{"c", 1}) || c.InQueue() || c.Value().Name != "c" {
+		t.Error("Update after pop should fail")
+	}
+
+	other, _ := NewPriorityQueue[pqJob](pqJobCompare)
+	h := other.Push(pqJob{"x", 1})
+	if _, ok := pq.Remove(h); ok {
+		t.Error("Handle from another queue should be rejected")
+	}
+}
+
+func TestPriorityQueueRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = r.Intn(100)
+	}
+	coll := NewCollection(values)
+	pq, err := NewPriorityQueueFrom(coll)
+	if err != nil {
+		t.Fatalf("NewPriorityQueueFrom returned error: %v", err)
+	}
+
+	handles := make([]*PriorityHandle[int], 0)
+	expected := append([]int(nil), values...)
+	for i := 0; i < 200; i++ {
+		v := r.Intn(100)
+		handles = append(handles, pq.Push(v))
+		expected = append(expected, v)
+	}
+	// 修改一半句柄，删除另一半
+	for i, h := range handles {
+		old := h.Value()
+		for j := len(expected) - 1; j >= 0; j-- {
+			if expected[j] == old {
+				expected = append(expected[:j], expected[j+1:]...)
+				break
+			}
+		}
+		if i%2 == 0 {
+			v := r.Intn(100)
+			pq.Update(h, v)
+			expected = append(expected, v)
+		} else {
+			pq.Remove(h)
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(expected)))
+	if got := drain(pq); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected drain result")
+	}
+	if coll.Count() != 500 {
+		t.Error("NewPriorityQueueFrom should not modify original collection")
+	}
+}
+
+func TestBlockingPriorityQueue(t *testing.T) {
+	pq, _ := NewPriorityQueue[int](nil)
+	bq := NewBlockingPriorityQueue(pq)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := bq.PopWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	const workers, perWorker = 4, 50
+	results := make(chan int, workers*perWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				v, err := bq.PopWait(context.Background())
+				if err != nil {
+					t.Errorf("PopWait returned error: %v", err)
+					return
+				}
+				results <- v
+			}
+		}()
+	}
+	for i := 0; i < workers*perWorker; i++ {
+		bq.Push(i)
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[int]bool)
+	for v := range results {
+		seen[v] = true
+	}
+	if len(seen) != workers*perWorker || bq.Len() != 0 {
+		t.Errorf("Expected every element to be popped once, got %d", len(seen))
+	}
+}
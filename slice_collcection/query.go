@@ -0,0 +1,583 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// Query 针对结构体 Collection 的查询构建器，参考 Laravel 的 where 系列方法
+//
+// 字段可以使用字段名或 json tag，字段与操作符在构建时校验一次，执行时不再重复解析。
+// 条件之间的组合与 SQL 一致：Where 之间为 AND，OrWhere 开启一个新的 OR 分支，AND 优先于 OR。
+// 构建过程中的第一个错误会被记录，并在 Get/First/Count 时返回。
+//
+// 使用示例：
+//
+//	adults, err := users.Where("Age", ">=", 18).
+//	    WhereIn("status", []string{"active", "vip"}).
+//	    OrderBy("Age", "desc").
+//	    Limit(10).
+//	    Get()
+type Query[T any] struct {
+	coll *Collection[T]
+
+	groups [][]queryPredicate // 析取范式：组内为 AND，组间为 OR
+	orders []queryOrder
+	offset int
+	limit  int // 小于 0 时不限制
+	err    error
+}
+
+// queryPredicate 编译后的条件，接收元素本身
+type queryPredicate func(item any) bool
+
+// queryOrder 排序条件
+type queryOrder struct {
+	field *fieldAccessor
+	desc  bool
+}
+
+// Query 创建查询构建器
+func (c *Collection[T]) Query() *Query[T] {
+	return &Query[T]{coll: c, groups: [][]queryPredicate{{}}, limit: -1}
+}
+
+// Where 创建查询构建器并添加条件，等同于 c.Query().Where(field, op, value)
+func (c *Collection[T]) Where(field string, op string, value any) *Query[T] {
+	return c.Query().Where(field, op, value)
+}
+
+// Where 添加 AND 条件
+// op 支持 =、==、!=、<>、>、>=、<、<=；value 为 nil 时 = 等同于 WhereNull，!= 等同于 WhereNotNull
+func (q *Query[T]) Where(field string, op string, value any) *Query[T] {
+	return q.and(q.compileComparison(field, op, value))
+}
+
+// OrWhere 以 OR 连接一个新的条件
+func (q *Query[T]) OrWhere(field string, op string, value any) *Query[T] {
+	return q.or(q.compileComparison(field, op, value))
+}
+
+// WhereGroup 添加一组以括号包裹的 AND 条件
+func (q *Query[T]) WhereGroup(fn func(q *Query[T])) *Query[T] {
+	return q.and(q.compileGroup(fn))
+}
+
+// OrWhereGroup 以 OR 连接一组以括号包裹的条件
+func (q *Query[T]) OrWhereGroup(fn func(q *Query[T])) *Query[T] {
+	return q.or(q.compileGroup(fn))
+}
+
+// WhereIn 字段值在 values 中，values 需要是切片
+func (q *Query[T]) WhereIn(field string, values any) *Query[T] {
+	return q.and(q.compileIn(field, values, false))
+}
+
+// WhereNotIn 字段值不在 values 中，values 需要是切片
+func (q *Query[T]) WhereNotIn(field string, values any) *Query[T] {
+	return q.and(q.compileIn(field, values, true))
+}
+
+// WhereBetween 字段值在 [lo, hi] 区间内
+func (q *Query[T]) WhereBetween(field string, lo, hi any) *Query[T] {
+	return q.and(q.compileBetween(field, lo, hi))
+}
+
+// WhereNull 字段值为 nil（指针、接口、map、切片等）
+func (q *Query[T]) WhereNull(field string) *Query[T] {
+	return q.and(q.compileNull(field, true))
+}
+
+// WhereNotNull 字段值不为 nil
+func (q *Query[T]) WhereNotNull(field string) *Query[T] {
+	return q.and(q.compileNull(field, false))
+}
+
+// WhereLike 字符串字段按 SQL LIKE 模式匹配（% 匹配任意多个字符，_ 匹配单个字符，忽略大小写）
+func (q *Query[T]) WhereLike(field string, pattern string) *Query[T] {
+	return q.and(q.compileLike(field, pattern))
+}
+
+// OrderBy 按字段排序，direction 为 asc 或 desc；多次调用时按调用顺序依次比较
+// 字段值为 nil 的元素无论升序降序都排在最前
+func (q *Query[T]) OrderBy(field string, direction string) *Query[T] {
+	accessor, err := q.resolve(field)
+	if err != nil {
+		return q.fail(err)
+	}
+	if _, err := orderableValue(accessor); err != nil {
+		return q.fail(err)
+	}
+
+	switch strings.ToLower(direction) {
+	case "asc", "":
+		q.orders = append(q.orders, queryOrder{field: accessor})
+	case "desc":
+		q.orders = append(q.orders, queryOrder{field: accessor, desc: true})
+	default:
+		return q.fail(fmt.Errorf("%w: order direction %q", errorx.InvalidOperatorError, direction))
+	}
+
+	return q
+}
+
+// Offset 跳过前 n 个结果，n 小于 0 时记录 errorx.InvalidArgumentError
+func (q *Query[T]) Offset(n int) *Query[T] {
+	if n < 0 {
+		return q.fail(fmt.Errorf("%w: offset %d", errorx.InvalidArgumentError, n))
+	}
+	q.offset = n
+	return q
+}
+
+// Limit 最多返回 n 个结果，n 小于 0 时记录 errorx.InvalidArgumentError
+func (q *Query[T]) Limit(n int) *Query[T] {
+	if n < 0 {
+		return q.fail(fmt.Errorf("%w: limit %d", errorx.InvalidArgumentError, n))
+	}
+	q.limit = n
+	return q
+}
+
+// Err 返回构建过程中出现的第一个错误
+func (q *Query[T]) Err() error {
+	return q.err
+}
+
+// Get 执行查询，返回新的 Collection（不修改原 Collection）
+func (q *Query[T]) Get() (*Collection[T], error) {
+	if q.err != nil {
+		return NewCollection[T](nil), q.err
+	}
+
+	res := make([]T, 0)
+	for _, item := range q.coll.value {
+		if q.match(item) {
+			res = append(res, item)
+		}
+	}
+
+	if len(q.orders) > 0 {
+		sort.SliceStable(res, func(i, j int) bool {
+			return q.less(res[i], res[j])
+		})
+	}
+
+	if q.offset >= len(res) {
+		res = res[:0]
+	} else {
+		res = res[q.offset:]
+	}
+	if q.limit >= 0 && q.limit < len(res) {
+		res = res[:q.limit]
+	}
+
+	coll := NewCollection(res)
+	coll.compareFunc = q.coll.compareFunc
+
+	return coll, nil
+}
+
+// First 执行查询并返回第一个结果，没有结果时 ok 为 false
+func (q *Query[T]) First() (item T, ok bool, err error) {
+	coll, err := q.Get()
+	if err != nil || coll.IsEmpty() {
+		return item, false, err
+	}
+
+	return coll.First(), true, nil
+}
+
+// Count 执行查询并返回结果数量（受 Offset/Limit 影响）
+func (q *Query[T]) Count() (int, error) {
+	coll, err := q.Get()
+	if err != nil {
+		return 0, err
+	}
+
+	return coll.Count(), nil
+}
+
+// match 元素是否满足条件
+func (q *Query[T]) match(item any) bool {
+	for _, group := range q.groups {
+		matched := true
+		for _, predicate := range group {
+			if !predicate(item) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// less 按排序条件比较两个元素，nil 值排在最前
+func (q *Query[T]) less(a, b T) bool {
+	return compareByOrders(q.orders, a, b) < 0
+}
+
+// compareOrderValue 按排序方向比较两个字段值，ok 为 false 表示值为 nil，nil 值无论升序降序都排在最前
+func compareOrderValue(order queryOrder, va any, okA bool, vb any, okB bool) int {
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	cmp, _ := utils.CompareAny(va, vb)
+	if order.desc {
+		cmp = -cmp
+	}
+	return cmp
+}
+
+// and 向当前 AND 分组添加条件
+func (q *Query[T]) and(predicate queryPredicate) *Query[T] {
+	if predicate == nil {
+		return q
+	}
+	last := len(q.groups) - 1
+	q.groups[last] = append(q.groups[last], predicate)
+	return q
+}
+
+// or 开启新的 OR 分组
+func (q *Query[T]) or(predicate queryPredicate) *Query[T] {
+	if predicate == nil {
+		return q
+	}
+	q.groups = append(q.groups, []queryPredicate{predicate})
+	return q
+}
+
+// fail 记录第一个错误
+func (q *Query[T]) fail(err error) *Query[T] {
+	if q.err == nil {
+		q.err = err
+	}
+	return q
+}
+
+// resolve 解析字段
+func (q *Query[T]) resolve(field string) (*fieldAccessor, error) {
+	return resolveField(q.coll.typ, field)
+}
+
+// compileGroup 编译括号分组
+func (q *Query[T]) compileGroup(fn func(q *Query[T])) queryPredicate {
+	if fn == nil {
+		q.fail(errorx.NilFunc)
+		return nil
+	}
+
+	sub := q.coll.Query()
+	fn(sub)
+	if sub.err != nil {
+		q.fail(sub.err)
+		return nil
+	}
+
+	return sub.match
+}
+
+// compileComparison 编译比较条件
+func (q *Query[T]) compileComparison(field string, op string, value any) queryPredicate {
+	switch op {
+	case "=", "==":
+		if value == nil {
+			return q.compileNull(field, true)
+		}
+	case "!=", "<>":
+		if value == nil {
+			return q.compileNull(field, false)
+		}
+	case ">", ">=", "<", "<=":
+	default:
+		q.fail(fmt.Errorf("%w: %q", errorx.InvalidOperatorError, op))
+		return nil
+	}
+
+	accessor, err := q.resolve(field)
+	if err != nil {
+		q.fail(err)
+		return nil
+	}
+
+	equal, err := compileEqual(accessor, value)
+	if err != nil {
+		q.fail(err)
+		return nil
+	}
+	if op == "=" || op == "==" {
+		return func(item any) bool {
+			fv, ok := derefField(accessor, item)
+			return ok && equal(fv)
+		}
+	}
+	if op == "!=" || op == "<>" {
+		return func(item any) bool {
+			fv, ok := derefField(accessor, item)
+			return ok && !equal(fv)
+		}
+	}
+
+	if err := checkOrderable(accessor, value); err != nil {
+		q.fail(err)
+		return nil
+	}
+	var accept func(cmp int) bool
+	switch op {
+	case ">":
+		accept = func(cmp int) bool { return cmp > 0 }
+	case ">=":
+		accept = func(cmp int) bool { return cmp >= 0 }
+	case "<":
+		accept = func(cmp int) bool { return cmp < 0 }
+	default:
+		accept = func(cmp int) bool { return cmp <= 0 }
+	}
+
+	return func(item any) bool {
+		fv, ok := derefField(accessor, item)
+		if !ok {
+			return false
+		}
+		cmp, err := utils.CompareAny(fv.Interface(), value)
+		return err == nil && accept(cmp)
+	}
+}
+
+// compileIn 编译 WhereIn/WhereNotIn
+func (q *Query[T]) compileIn(field string, values any, not bool) queryPredicate {
+	accessor, err := q.resolve(field)
+	if err != nil {
+		q.fail(err)
+		return nil
+	}
+
+	list := reflect.ValueOf(values)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		q.fail(fmt.Errorf("%w: WhereIn values must be a slice, got %T", errorx.InvalidTypeError, values))
+		return nil
+	}
+
+	baseType := derefType(accessor.typ)
+	matchers := make([]func(reflect.Value) bool, 0, list.Len())
+	// 元素类型与字段类型完全一致且可哈希时使用集合查找
+	set := make(map[any]struct{}, list.Len())
+	useSet := baseType.Comparable()
+	for i := 0; i < list.Len(); i++ {
+		value := list.Index(i).Interface()
+		equal, err := compileEqual(accessor, value)
+		if err != nil {
+			q.fail(err)
+			return nil
+		}
+		matchers = append(matchers, equal)
+		if value == nil || reflect.TypeOf(value) != baseType {
+			useSet = false
+		} else if useSet {
+			set[value] = struct{}{}
+		}
+	}
+
+	return func(item any) bool {
+		fv, ok := derefField(accessor, item)
+		if !ok {
+			return false
+		}
+		found := false
+		if useSet {
+			_, found = set[fv.Interface()]
+		} else {
+			for _, equal := range matchers {
+				if equal(fv) {
+					found = true
+					break
+				}
+			}
+		}
+		return found != not
+	}
+}
+
+// compileBetween 编译 WhereBetween
+func (q *Query[T]) compileBetween(field string, lo, hi any) queryPredicate {
+	accessor, err := q.resolve(field)
+	if err != nil {
+		q.fail(err)
+		return nil
+	}
+	if err := checkOrderable(accessor, lo); err != nil {
+		q.fail(err)
+		return nil
+	}
+	if err := checkOrderable(accessor, hi); err != nil {
+		q.fail(err)
+		return nil
+	}
+
+	return func(item any) bool {
+		fv, ok := derefField(accessor, item)
+		if !ok {
+			return false
+		}
+		value := fv.Interface()
+		cmpLo, errLo := utils.CompareAny(value, lo)
+		cmpHi, errHi := utils.CompareAny(value, hi)
+		return errLo == nil && errHi == nil && cmpLo >= 0 && cmpHi <= 0
+	}
+}
+
+// compileNull 编译 WhereNull/WhereNotNull
+func (q *Query[T]) compileNull(field string, isNull bool) queryPredicate {
+	accessor, err := q.resolve(field)
+	if err != nil {
+		q.fail(err)
+		return nil
+	}
+
+	return func(item any) bool {
+		fv, ok := accessor.get(item)
+		if !ok {
+			return false
+		}
+		return isNilValue(fv) == isNull
+	}
+}
+
+// compileLike 编译 WhereLike
+func (q *Query[T]) compileLike(field string, pattern string) queryPredicate {
+	accessor, err := q.resolve(field)
+	if err != nil {
+		q.fail(err)
+		return nil
+	}
+	if derefType(accessor.typ).Kind() != reflect.String {
+		q.fail(fmt.Errorf("%w: WhereLike field %s must be a string", errorx.InvalidTypeError, field))
+		return nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			builder.WriteString(".*")
+		case '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString("$")
+	re := regexp.MustCompile(builder.String())
+
+	return func(item any) bool {
+		fv, ok := derefField(accessor, item)
+		return ok && re.MatchString(fv.String())
+	}
+}
+
+// compileEqual 编译相等判断：可排序类型按 utils.CompareAny 比较，其余类型使用 reflect.DeepEqual
+func compileEqual(accessor *fieldAccessor, value any) (func(reflect.Value) bool, error) {
+	if value == nil {
+		return nil, fmt.Errorf("%w: nil value for field %s", errorx.InvalidTypeError, accessor.name)
+	}
+
+	if sample, err := orderableValue(accessor); err == nil {
+		if _, err := utils.CompareAny(sample, value); err != nil {
+			return nil, fmt.Errorf("%w: field %s (%s) cannot compare with %T", errorx.InvalidTypeError, accessor.name, accessor.typ, value)
+		}
+		return func(fv reflect.Value) bool {
+			cmp, err := utils.CompareAny(fv.Interface(), value)
+			return err == nil && cmp == 0
+		}, nil
+	}
+
+	return func(fv reflect.Value) bool {
+		return reflect.DeepEqual(fv.Interface(), value)
+	}, nil
+}
+
+// checkOrderable 检查字段可以与 value 比较大小
+func checkOrderable(accessor *fieldAccessor, value any) error {
+	sample, err := orderableValue(accessor)
+	if err != nil {
+		return err
+	}
+	if _, err := utils.CompareAny(sample, value); err != nil {
+		return fmt.Errorf("%w: field %s (%s) cannot compare with %T", errorx.InvalidTypeError, accessor.name, accessor.typ, value)
+	}
+	return nil
+}
+
+// orderableValue 返回字段类型的零值，字段类型不可排序时返回错误
+func orderableValue(accessor *fieldAccessor) (any, error) {
+	baseType := derefType(accessor.typ)
+	if baseType == reflect.TypeOf(time.Time{}) {
+		return time.Time{}, nil
+	}
+	switch kind := baseType.Kind(); {
+	case kind == reflect.String || kind == reflect.Bool || utils.IsComputableKind(kind):
+		return reflect.Zero(baseType).Interface(), nil
+	default:
+		return nil, fmt.Errorf("%w: field %s (%s) is not orderable", errorx.KeyUnComparableError, accessor.name, accessor.typ)
+	}
+}
+
+// orderValue 读取用于排序的字段值，nil 时返回 false
+func orderValue(accessor *fieldAccessor, item any) (any, bool) {
+	fv, ok := derefField(accessor, item)
+	if !ok {
+		return nil, false
+	}
+	return fv.Interface(), true
+}
+
+// derefField 读取字段值并解引用指针，值为 nil 时返回 false
+func derefField(accessor *fieldAccessor, item any) (reflect.Value, bool) {
+	fv, ok := accessor.get(item)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return reflect.Value{}, false
+		}
+		fv = fv.Elem()
+	}
+	return fv, true
+}
+
+// derefType 返回指针指向的基础类型
+func derefType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// isNilValue 判断值是否为 nil
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,239 @@
+package slice_collcection
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+type queryUser struct {
+	Name     string `json:"name"`
+	Age      int    `json:"age"`
+	Status   string `json:"status"`
+	Score    *float64
+	JoinedAt time.Time
+	Tags     []string
+}
+
+// TestQueryWhereOrderLimit tests the Where, OrderBy, Offset and Limit methods of the Query struct
+func TestQueryWhereOrderLimit(t *testing.T) {
+	users := NewCollection([]queryUser{
+		{Name: "alice", Age: 30, Status: "active"},
+		{Name: "bob", Age: 17, Status: "pending"},
+		{Name: "carol", Age: 42, Status: "vip"},
+		{Name: "dave", Age: 30, Status: "banned"},
+		{Name: "Alina", Age: 25, Status: "active"},
+	})
+
+	res, err := users.Where("age", ">=", 18).
+		WhereIn("status", []string{"active", "vip"}).
+		OrderBy("Age", "desc").
+		OrderBy("name", "asc").
+		Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	names, _ := res.PluckString("Name")
+	expected := []string{"carol", "alice", "Alina"}
+	if !reflect.DeepEqual(names.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, names.Values())
+	}
+
+	res, err = users.Query().OrderBy("Age", "asc").OrderBy("Name", "asc").Offset(1).Limit(2).Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	names, _ = res.PluckString("Name")
+	expected = []string{"Alina", "alice"}
+	if !reflect.DeepEqual(names.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, names.Values())
+	}
+
+	if users.Count() != 5 || users.First().Name != "alice" {
+		t.Error("Query should not modify original collection")
+	}
+}
+
+// TestQueryOrWhereAndGroups tests the OrWhere, WhereGroup and OrWhereGroup methods of the Query struct
+func TestQueryOrWhereAndGroups(t *testing.T) {
+	users := NewCollection([]queryUser{
+		{Name: "alice", Age: 30, Status: "active"},
+		{Name: "bob", Age: 17, Status: "pending"},
+		{Name: "carol", Age: 42, Status: "vip"},
+		{Name: "dave", Age: 30, Status: "banned"},
+	})
+
+	// status = 'vip' OR (age < 18 AND status = 'pending')
+	res, err := users.Where("status", "=", "vip").
+		OrWhereGroup(func(q *Query[queryUser]) {
+			q.Where("age", "<", 18).Where("status", "=", "pending")
+		}).
+		Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	names, _ := res.PluckString("Name")
+	expected := []string{"bob", "carol"}
+	if !reflect.DeepEqual(names.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, names.Values())
+	}
+
+	// age = 30 AND (status = 'banned' OR status = 'vip')
+	res, err = users.Where("age", "=", 30).
+		WhereGroup(func(q *Query[queryUser]) {
+			q.Where("status", "=", "banned").OrWhere("status", "=", "vip")
+		}).
+		Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	names, _ = res.PluckString("Name")
+	expected = []string{"dave"}
+	if !reflect.DeepEqual(names.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, names.Values())
+	}
+}
+
+// TestQueryOperators tests the comparison operators supported by the Query struct
+func TestQueryOperators(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	users := NewCollection([]queryUser{
+		{Name: "alice", Age: 30, Status: "active", Score: score(88.5), JoinedAt: day(3)},
+		{Name: "bob", Age: 17, Status: "pending", JoinedAt: day(1), Tags: []string{"new"}},
+		{Name: "carol", Age: 42, Status: "vip", Score: score(95), JoinedAt: day(7)},
+		{Name: "dave", Age: 30, Status: "banned", Score: score(60), JoinedAt: day(5)},
+		{Name: "Alina", Age: 25, Status: "active", JoinedAt: day(2)},
+	})
+
+	cases := []struct {
+		name     string
+		query    *Query[queryUser]
+		expected []string
+	}{
+		{"not in", users.Query().WhereNotIn("status", []any{"active", "banned"}), []string{"bob", "carol"}},
+		{"between", users.Query().WhereBetween("age", 25, 30), []string{"alice", "dave", "Alina"}},
+		{"mixed numeric types", users.Where("Age", ">", 29.5).Where("Age", "<>", int64(42)), []string{"alice", "dave"}},
+		{"pointer field", users.Where("Score", ">", 80), []string{"alice", "carol"}},
+		{"null", users.Query().WhereNull("Score"), []string{"bob", "Alina"}},
+		{"not null via !=", users.Where("Score", "!=", nil), []string{"alice", "carol", "dave"}},
+		{"like", users.Query().WhereLike("name", "al%"), []string{"alice", "Alina"}},
+		{"like single char", users.Query().WhereLike("name", "_ob"), []string{"bob"}},
+		{"time", users.Where("JoinedAt", ">", day(4)), []string{"carol", "dave"}},
+		{"slice equality", users.Where("Tags", "=", []string{"new"}), []string{"bob"}},
+	}
+	for _, tc := range cases {
+		res, err := tc.query.Get()
+		if err != nil {
+			t.Errorf("%s: Get returned error: %v", tc.name, err)
+			continue
+		}
+		names, _ := res.PluckString("Name")
+		if !reflect.DeepEqual(names.Values(), tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, names.Values())
+		}
+	}
+}
+
+// TestQueryOrderByNil tests that nil field values sort first in both directions
+func TestQueryOrderByNil(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	users := NewCollection([]queryUser{
+		{Name: "a", Score: score(70)},
+		{Name: "b"},
+		{Name: "c", Score: score(90)},
+		{Name: "d"},
+	})
+
+	for direction, expected := range map[string][]string{
+		"asc":  {"b", "d", "a", "c"},
+		"desc": {"b", "d", "c", "a"},
+	} {
+		res, err := users.Query().OrderBy("Score", direction).OrderBy("Name", "asc").Get()
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		names, _ := res.PluckString("Name")
+		if !reflect.DeepEqual(names.Values(), expected) {
+			t.Errorf("%s: expected %v, got %v", direction, expected, names.Values())
+		}
+	}
+}
+
+// TestQueryFirstAndCount tests the First and Count methods of the Query struct
+func TestQueryFirstAndCount(t *testing.T) {
+	users := NewCollection([]queryUser{
+		{Name: "alice", Age: 30, Status: "active"},
+		{Name: "carol", Age: 42, Status: "vip"},
+		{Name: "Alina", Age: 25, Status: "active"},
+	})
+
+	user, ok, err := users.Where("status", "=", "vip").First()
+	if err != nil || !ok || user.Name != "carol" {
+		t.Errorf("Expected carol, got %v %v %v", user, ok, err)
+	}
+	_, ok, err = users.Where("age", ">", 100).First()
+	if err != nil || ok {
+		t.Errorf("Expected no result, got %v %v", ok, err)
+	}
+
+	count, err := users.Where("status", "=", "active").Count()
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2, got %d %v", count, err)
+	}
+}
+
+// TestQueryPointerElements tests that the Query struct supports pointer elements and skips nil ones
+func TestQueryPointerElements(t *testing.T) {
+	users := NewCollection([]*queryUser{{Name: "a", Age: 1}, nil, {Name: "b", Age: 2}})
+
+	res, err := users.Where("Age", ">", 1).Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if res.Count() != 1 || res.First().Name != "b" {
+		t.Errorf("Unexpected result %v", res.Values())
+	}
+}
+
+// TestQueryErrors tests the errors reported by the Query struct
+func TestQueryErrors(t *testing.T) {
+	users := NewCollection([]queryUser{{Name: "bob", Age: 17, Tags: []string{"new"}}})
+
+	if _, err := users.Where("missing", "=", 1).Get(); !errors.Is(err, errorx.FieldNotFoundError) {
+		t.Errorf("Expected FieldNotFoundError, got %v", err)
+	}
+	if _, err := users.Where("age", "~", 1).Get(); !errors.Is(err, errorx.InvalidOperatorError) {
+		t.Errorf("Expected InvalidOperatorError, got %v", err)
+	}
+	if _, err := users.Where("age", ">", "old").Get(); !errors.Is(err, errorx.InvalidTypeError) {
+		t.Errorf("Expected InvalidTypeError, got %v", err)
+	}
+	if _, err := users.Where("Tags", ">", 1).Get(); !errors.Is(err, errorx.KeyUnComparableError) {
+		t.Errorf("Expected KeyUnComparableError, got %v", err)
+	}
+	if _, err := users.Query().WhereIn("age", 1).Get(); !errors.Is(err, errorx.InvalidTypeError) {
+		t.Errorf("Expected InvalidTypeError, got %v", err)
+	}
+	if _, err := users.Query().OrderBy("age", "sideways").Get(); !errors.Is(err, errorx.InvalidOperatorError) {
+		t.Errorf("Expected InvalidOperatorError, got %v", err)
+	}
+	if _, err := users.Query().Offset(-1).Get(); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for negative offset, got %v", err)
+	}
+	if _, err := users.Query().Limit(-1).Get(); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for negative limit, got %v", err)
+	}
+	if _, err := NewCollection([]int{1}).Where("x", "=", 1).Get(); !errors.Is(err, errorx.InvalidTypeError) {
+		t.Errorf("Expected InvalidTypeError for non-struct collection, got %v", err)
+	}
+
+	// 第一个错误被保留
+	q := users.Where("missing", "=", 1).Where("age", "~", 1)
+	if !errors.Is(q.Err(), errorx.FieldNotFoundError) {
+		t.Errorf("Expected first error to be kept, got %v", q.Err())
+	}
+}
@@ -0,0 +1,255 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// TopN 返回按 compareFunc 最大的 n 个元素，按从大到小排列，不修改原 Collection
+// compareFunc 为 nil 时使用 Collection 的比较函数；相等的元素先出现的排在前面。
+// 使用大小为 n 的堆，复杂度 O(m·log n)，适合从大量数据中取前几名
+func (c *Collection[T]) TopN(n int, compareFunc func(a, b T) int) (*Collection[T], error) {
+	cmp, err := c.selectCompare(compareFunc)
+	if err != nil {
+		return NewCollection[T](nil), err
+	}
+
+	return c.topN(n, cmp), nil
+}
+
+// BottomN 返回按 compareFunc 最小的 n 个元素，按从小到大排列，不修改原 Collection
+// compareFunc 为 nil 时使用 Collection 的比较函数；相等的元素先出现的排在前面
+func (c *Collection[T]) BottomN(n int, compareFunc func(a, b T) int) (*Collection[T], error) {
+	cmp, err := c.selectCompare(compareFunc)
+	if err != nil {
+		return NewCollection[T](nil), err
+	}
+
+	return c.topN(n, func(a, b T) int { return cmp(b, a) }), nil
+}
+
+// NthElement 返回按比较函数从小到大排列后下标为 k 的元素（从 0 开始），不修改原 Collection
+// 使用快速选择，平均复杂度 O(n)；k 越界时返回 errorx.InvalidArgumentError
+func (c *Collection[T]) NthElement(k int) (T, error) {
+	var zero T
+	if !c.isComparable() {
+		return zero, errorx.NoComparableError
+	}
+	if k < 0 || k >= len(c.value) {
+		return zero, fmt.Errorf("%w: k %d", errorx.InvalidArgumentError, k)
+	}
+
+	values := make([]T, len(c.value))
+	copy(values, c.value)
+	quickselect(values, k, func(a, b T) int {
+		return c.compareFunc(a, b)
+	})
+
+	return values[k], nil
+}
+
+// Percentile 返回第 p 百分位数（0 <= p <= 100），位于两个元素之间时线性插值，不修改原 Collection
+// 使用快速选择，平均复杂度 O(n)；空 Collection 返回 0，p 不在范围内时返回 errorx.InvalidArgumentError
+func (c *Collection[T]) Percentile(p float64) (float64, error) {
+	if !c.isComputable() {
+		return 0.0, errorx.NoComputableError
+	}
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0.0, fmt.Errorf("%w: percentile %v", errorx.InvalidArgumentError, p)
+	}
+	if c.IsEmpty() {
+		return 0.0, nil
+	}
+
+	values, err := c.floatValues()
+	if err != nil {
+		return 0.0, err
+	}
+
+	rank := p / 100 * float64(len(values)-1)
+	lo := int(math.Floor(rank))
+	quickselect(values, lo, func(a, b float64) int {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		default:
+			return 0
+		}
+	})
+	lower := values[lo]
+	if frac := rank - float64(lo); frac > 0 {
+		// 快速选择后 lo 之后的元素都不小于 values[lo]，其中最小的即为下一个元素
+		upper := values[lo+1]
+		for _, v := range values[lo+2:] {
+			upper = min(upper, v)
+		}
+		return lower + (upper-lower)*frac, nil
+	}
+
+	return lower, nil
+}
+
+// selectCompare 返回选择使用的比较函数
+func (c *Collection[T]) selectCompare(compareFunc func(a, b T) int) (func(a, b T) int, error) {
+	if compareFunc != nil {
+		return compareFunc, nil
+	}
+	if !c.isComparable() {
+		return nil, errorx.NoComparableError
+	}
+	return func(a, b T) int {
+		return c.compareFunc(a, b)
+	}, nil
+}
+
+// topN 使用以最差元素为堆顶的堆保留最大的 n 个元素
+func (c *Collection[T]) topN(n int, cmp func(a, b T) int) *Collection[T] {
+	n = min(max(n, 0), len(c.value))
+	h := &selectHeap[T]{entries: make([]windowEntry[T], 0, n), cmp: cmp}
+	for i, item := range c.value {
+		entry := windowEntry[T]{item: item, index: i}
+		if len(h.entries) < n {
+			h.push(entry)
+		} else if n > 0 && h.worse(h.entries[0], entry) {
+			h.entries[0] = entry
+			h.down(0)
+		}
+	}
+
+	sort.Slice(h.entries, func(i, j int) bool {
+		return h.worse(h.entries[j], h.entries[i])
+	})
+	res := make([]T, 0, len(h.entries))
+	for _, entry := range h.entries {
+		res = append(res, entry.item)
+	}
+
+	coll := NewCollection(res)
+	coll.compareFunc = c.compareFunc
+
+	return coll
+}
+
+// selectHeap TopN 使用的最小堆，堆顶为当前保留的元素中最差的一个
+type selectHeap[T any] struct {
+	entries []windowEntry[T]
+	cmp     func(a, b T) int
+}
+
+// worse a 是否排在 b 之后：更小，或相等但出现得更晚
+func (h *selectHeap[T]) worse(a, b windowEntry[T]) bool {
+	if c := h.cmp(a.item, b.item); c != 0 {
+		return c < 0
+	}
+	return a.index > b.index
+}
+
+// push 添加元素并上浮
+func (h *selectHeap[T]) push(entry windowEntry[T]) {
+	h.entries = append(h.entries, entry)
+	for i := len(h.entries) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !h.worse(h.entries[i], h.entries[parent]) {
+			break
+		}
+		h.entries[i], h.entries[parent] = h.entries[parent], h.entries[i]
+		i = parent
+	}
+}
+
+// down 下沉第 i 个元素
+func (h *selectHeap[T]) down(i int) {
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(h.entries) && h.worse(h.entries[child], h.entries[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		h.entries[i], h.entries[smallest] = h.entries[smallest], h.entries[i]
+		i = smallest
+	}
+}
+
+// quickselect 重排 s，使 s[k] 为从小到大排列后下标为 k 的元素，且 s[:k] 不大于 s[k]、s[k+1:] 不小于 s[k]
+// 使用三数取中与三路划分，划分次数超过 2·log2(n) 时退回排序以保证最坏 O(n·log n)
+func quickselect[E any](s []E, k int, cmp func(a, b E) int) {
+	lo, hi := 0, len(s)
+	budget := 2 * bits.Len(uint(len(s)))
+	for hi-lo > 1 {
+		if budget == 0 {
+			sub := s[lo:hi]
+			sort.Slice(sub, func(i, j int) bool {
+				return cmp(sub[i], sub[j]) < 0
+			})
+			return
+		}
+		budget--
+
+		mid := lo + (hi-lo)/2
+		if cmp(s[mid], s[lo]) < 0 {
+			s[mid], s[lo] = s[lo], s[mid]
+		}
+		if cmp(s[hi-1], s[lo]) < 0 {
+			s[hi-1], s[lo] = s[lo], s[hi-1]
+		}
+		if cmp(s[hi-1], s[mid]) < 0 {
+			s[hi-1], s[mid] = s[mid], s[hi-1]
+		}
+		pivot := s[mid]
+
+		// 三路划分：[lo, lt) 小于 pivot，[lt, gt) 等于 pivot，[gt, hi) 大于 pivot
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			switch c := cmp(s[i], pivot); {
+			case c < 0:
+				s[lt], s[i] = s[i], s[lt]
+				lt++
+				i++
+			case c > 0:
+				gt--
+				s[i], s[gt] = s[gt], s[i]
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case k < lt:
+			hi = lt
+		case k >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// floatValues 将元素转换为 float64 切片
+func (c *Collection[T]) floatValues() ([]float64, error) {
+	values := make([]float64, 0, len(c.value))
+	for _, item := range c.value {
+		val := reflect.ValueOf(item)
+		switch {
+		case val.CanInt():
+			values = append(values, float64(val.Int()))
+		case val.CanUint():
+			values = append(values, float64(val.Uint()))
+		case val.CanFloat():
+			values = append(values, val.Float())
+		default:
+			return nil, fmt.Errorf("unsupported type: %T", item)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,127 @@
+package slice_collcection
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+func TestTopNBottomN(t *testing.T) {
+	c := NewCollection([]int{5, 1, 9, 3, 9, 7, 2})
+
+	top, err := c.TopN(3, nil)
+	if err != nil {
+		t.Fatalf("TopN returned error: %v", err)
+	}
+	if !reflect.DeepEqual(top.Values(), []int{9, 9, 7}) {
+		t.Errorf("Unexpected top %v", top.Values())
+	}
+	bottom, _ := c.BottomN(2, nil)
+	if !reflect.DeepEqual(bottom.Values(), []int{1, 2}) {
+		t.Errorf("Unexpected bottom %v", bottom.Values())
+	}
+	all, _ := c.TopN(100, nil)
+	if !reflect.DeepEqual(all.Values(), []int{9, 9, 7, 5, 3, 2, 1}) {
+		t.Errorf("Unexpected top of all %v", all.Values())
+	}
+	if none, _ := c.TopN(0, nil); !none.IsEmpty() {
+		t.Errorf("Expected empty result, got %v", none.Values())
+	}
+	if !reflect.DeepEqual(c.Values(), []int{5, 1, 9, 3, 9, 7, 2}) {
+		t.Error("TopN should not modify original collection")
+	}
+
+	type player struct {
+		Name  string
+		Score int
+	}
+	players := NewCollection([]player{{"a", 10}, {"b", 30}, {"c", 30}, {"d", 20}})
+	best, err := players.TopN(2, func(x, y player) int { return x.Score - y.Score })
+	if err != nil {
+		t.Fatalf("TopN returned error: %v", err)
+	}
+	// 相等的元素先出现的排在前面
+	if !reflect.DeepEqual(best.Values(), []player{{"b", 30}, {"c", 30}}) {
+		t.Errorf("Unexpected top players %v", best.Values())
+	}
+	if _, err := players.TopN(2, nil); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	c := NewCollection([]int{5, 1, 9, 3, 9, 7, 2})
+	for k, expected := range []int{1, 2, 3, 5, 7, 9, 9} {
+		if got, err := c.NthElement(k); err != nil || got != expected {
+			t.Errorf("NthElement(%d): expected %d, got %d (%v)", k, expected, got, err)
+		}
+	}
+	if _, err := c.NthElement(7); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for out of range k, got %v", err)
+	}
+	if !reflect.DeepEqual(c.Values(), []int{5, 1, 9, 3, 9, 7, 2}) {
+		t.Error("NthElement should not modify original collection")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	c := NewCollection([]int{15, 20, 35, 40, 50})
+	cases := map[float64]float64{0: 15, 25: 20, 40: 29, 50: 35, 100: 50}
+	for p, expected := range cases {
+		if got, err := c.Percentile(p); err != nil || got != expected {
+			t.Errorf("Percentile(%v): expected %v, got %v (%v)", p, expected, got, err)
+		}
+	}
+	if _, err := c.Percentile(101); !errors.Is(err, errorx.InvalidArgumentError) {
+		t.Errorf("Expected InvalidArgumentError for invalid percentile, got %v", err)
+	}
+	if _, err := NewCollection([]string{"a"}).Percentile(50); !errors.Is(err, errorx.NoComputableError) {
+		t.Errorf("Expected NoComputableError, got %v", err)
+	}
+}
+
+func TestSelectionMatchesSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		values := make([]int, r.Intn(200)+1)
+		for i := range values {
+			values[i] = r.Intn(20) // 大量重复值
+		}
+		sorted := append([]int(nil), values...)
+		sort.Ints(sorted)
+		c := NewCollection(values)
+
+		k := r.Intn(len(values))
+		if got, _ := c.NthElement(k); got != sorted[k] {
+			t.Fatalf("NthElement(%d): expected %d, got %d", k, sorted[k], got)
+		}
+
+		n := r.Intn(len(values) + 1)
+		bottom, _ := c.BottomN(n, nil)
+		if !reflect.DeepEqual(bottom.Values(), sorted[:n]) {
+			t.Fatalf("BottomN(%d): expected %v, got %v", n, sorted[:n], bottom.Values())
+		}
+
+		median, _ := c.Median()
+		expected := float64(sorted[len(sorted)/2])
+		if len(sorted)%2 == 0 {
+			expected = float64(sorted[len(sorted)/2-1]+sorted[len(sorted)/2]) / 2
+		}
+		if median != expected {
+			t.Fatalf("Median: expected %v, got %v", expected, median)
+		}
+	}
+
+	// 有序输入不会退化
+	ordered := make([]int, 100000)
+	for i := range ordered {
+		ordered[i] = i
+	}
+	if median, _ := NewCollection(ordered).Median(); median != 49999.5 {
+		t.Errorf("Unexpected median %v", median)
+	}
+}
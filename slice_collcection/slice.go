@@ -22,7 +22,7 @@ func NewCollection[T any](values []T) *Collection[T] {
 	typ := reflect.TypeOf(zero)
 	coll := &Collection[T]{value: values, typ: typ}
 
-	coll.compareFunc = utils.NewCompareFunc(typ.Kind())
+	coll.compareFunc = utils.NewCompareFuncOf(typ)
 
 	return coll
 }
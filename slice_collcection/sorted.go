@@ -0,0 +1,294 @@
+package slice_collcection
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// SortedCollection 始终按比较函数从小到大保持有序的集合，比较函数与 Collection.SetCompare 相同
+// 插入使用二分查找定位（相等的元素按插入顺序排列），查找类方法均为 O(log n)；
+// 适合“边追加边查询”的场景，替代每次 Append 后调用 Sort()。SortedCollection 不是并发安全的
+type SortedCollection[T any] struct {
+	value       []T
+	compareFunc func(a any, b any) int
+}
+
+// NewSortedCollection 创建有序集合，compareFunc 为 nil 时使用元素类型的默认比较函数（与 NewCollection 相同）
+// 没有可用的比较函数时返回 errorx.NoComparableError；values 会被复制后稳定排序
+//
+// 使用示例：
+//
+//	sc, _ := slice_collcection.NewSortedCollection[int](nil, 5, 1, 3)
+//	sc.Insert(2)               // [1 2 3 5]
+//	sc.RangeBetween(2, 4)      // [2 3]
+//	lo, hi := sc.EqualRange(3) // 2, 3
+func NewSortedCollection[T any](compareFunc func(a any, b any) int, values ...T) (*SortedCollection[T], error) {
+	if compareFunc == nil {
+		var zero T
+		compareFunc = utils.NewCompareFuncOf(reflect.TypeOf(zero))
+	}
+	if compareFunc == nil {
+		return nil, errorx.NoComparableError
+	}
+
+	sc := &SortedCollection[T]{compareFunc: compareFunc, value: append(make([]T, 0, len(values)), values...)}
+	sc.sort()
+
+	return sc, nil
+}
+
+// NewSortedCollectionFrom 使用 Collection 的元素与比较函数创建有序集合，不修改原 Collection
+func NewSortedCollectionFrom[T any](c *Collection[T]) (*SortedCollection[T], error) {
+	if !c.isComparable() {
+		return nil, errorx.NoComparableError
+	}
+	return NewSortedCollection(c.compareFunc, c.value...)
+}
+
+// SetCompare 设置比较函数并重新排序，compareFunc 为 nil 时不做修改
+func (sc *SortedCollection[T]) SetCompare(compareFunc func(a any, b any) int) *SortedCollection[T] {
+	if compareFunc != nil {
+		sc.compareFunc = compareFunc
+		sc.sort()
+	}
+	return sc
+}
+
+// Insert 插入元素，位置为 UpperBound，相等的元素排在已有元素之后
+func (sc *SortedCollection[T]) Insert(items ...T) *SortedCollection[T] {
+	for _, item := range items {
+		i := sc.UpperBound(item)
+		sc.value = append(sc.value, item)
+		copy(sc.value[i+1:], sc.value[i:])
+		sc.value[i] = item
+	}
+	return sc
+}
+
+// Remove 删除一个与 item 相等的元素（最靠前的一个），不存在时返回 false
+func (sc *SortedCollection[T]) Remove(item T) bool {
+	i := sc.IndexOf(item)
+	if i < 0 {
+		return false
+	}
+	sc.RemoveIndex(i)
+	return true
+}
+
+// RemoveIndex 删除下标为 index 的元素，下标越界时不做修改
+func (sc *SortedCollection[T]) RemoveIndex(index int) *SortedCollection[T] {
+	if index < 0 || index >= len(sc.value) {
+		return sc
+	}
+	sc.value = append(sc.value[:index], sc.value[index+1:]...)
+	return sc
+}
+
+// LowerBound 返回第一个不小于 item 的下标，所有元素都小于 item 时返回 Count()
+func (sc *SortedCollection[T]) LowerBound(item T) int {
+	return sort.Search(len(sc.value), func(i int) bool {
+		return sc.compareFunc(sc.value[i], item) >= 0
+	})
+}
+
+// UpperBound 返回第一个大于 item 的下标，没有大于 item 的元素时返回 Count()
+func (sc *SortedCollection[T]) UpperBound(item T) int {
+	return sort.Search(len(sc.value), func(i int) bool {
+		return sc.compareFunc(sc.value[i], item) > 0
+	})
+}
+
+// EqualRange 返回与 item 相等的元素所在的下标区间 [lo, hi)，不存在时 lo == hi，为 item 应插入的位置
+func (sc *SortedCollection[T]) EqualRange(item T) (int, int) {
+	return sc.LowerBound(item), sc.UpperBound(item)
+}
+
+// IndexOf 二分查找第一个与 item 相等的元素的下标，不存在时返回 -1
+func (sc *SortedCollection[T]) IndexOf(item T) int {
+	i := sc.LowerBound(item)
+	if i < len(sc.value) && sc.compareFunc(sc.value[i], item) == 0 {
+		return i
+	}
+	return -1
+}
+
+// Search 与 Collection.Search 相同，使用二分查找，不存在时返回 errorx.NotFoundError
+func (sc *SortedCollection[T]) Search(item T) (int, error) {
+	if i := sc.IndexOf(item); i >= 0 {
+		return i, nil
+	}
+	return -1, errorx.NotFoundError
+}
+
+// Contains 是否包含与 item 相等的元素
+func (sc *SortedCollection[T]) Contains(item T) bool {
+	return sc.IndexOf(item) >= 0
+}
+
+// CountOf 与 item 相等的元素数量
+func (sc *SortedCollection[T]) CountOf(item T) int {
+	lo, hi := sc.EqualRange(item)
+	return hi - lo
+}
+
+// RangeBetween 返回闭区间 [a, b] 内的元素，a 大于 b 时返回空 Collection
+func (sc *SortedCollection[T]) RangeBetween(a, b T) *Collection[T] {
+	lo, hi := sc.LowerBound(a), sc.UpperBound(b)
+	if lo >= hi {
+		return sc.newCollection(nil)
+	}
+	return sc.newCollection(append(make([]T, 0, hi-lo), sc.value[lo:hi]...))
+}
+
+// Union 归并两个有序集合的并集，O(n+m)：保留 sc 的全部元素，以及 other 中与 sc 任一元素都不相等的元素
+// 语义与 Collection.Union 相同，other 需要与 sc 使用相同的排序规则，结果使用 sc 的比较函数
+func (sc *SortedCollection[T]) Union(other *SortedCollection[T]) *SortedCollection[T] {
+	return sc.merge(other, true)
+}
+
+// Intersect 归并两个有序集合的交集，O(n+m)：保留 sc 中与 other 某个元素相等的元素
+// 语义与 Collection.Intersect 相同，other 需要与 sc 使用相同的排序规则，结果使用 sc 的比较函数
+func (sc *SortedCollection[T]) Intersect(other *SortedCollection[T]) *SortedCollection[T] {
+	return sc.merge(other, false)
+}
+
+// Index 返回下标为 i 的元素，下标越界时返回零值
+func (sc *SortedCollection[T]) Index(i int) T {
+	if i < 0 || i >= len(sc.value) {
+		var zero T
+		return zero
+	}
+	return sc.value[i]
+}
+
+// First 返回最小的元素，集合为空时返回 false
+func (sc *SortedCollection[T]) First() (T, bool) {
+	if len(sc.value) == 0 {
+		var zero T
+		return zero, false
+	}
+	return sc.value[0], true
+}
+
+// Last 返回最大的元素，集合为空时返回 false
+func (sc *SortedCollection[T]) Last() (T, bool) {
+	if len(sc.value) == 0 {
+		var zero T
+		return zero, false
+	}
+	return sc.value[len(sc.value)-1], true
+}
+
+// Count 元素数量
+func (sc *SortedCollection[T]) Count() int {
+	return len(sc.value)
+}
+
+// IsEmpty 是否为空
+func (sc *SortedCollection[T]) IsEmpty() bool {
+	return len(sc.value) == 0
+}
+
+// Clear 清空集合，保留比较函数
+func (sc *SortedCollection[T]) Clear() *SortedCollection[T] {
+	sc.value = nil
+	return sc
+}
+
+// Each 按顺序遍历，f 返回 false 时终止
+func (sc *SortedCollection[T]) Each(f func(item T, key int) bool) {
+	for i, v := range sc.value {
+		if !f(v, i) {
+			return
+		}
+	}
+}
+
+// Foreach 按顺序遍历
+func (sc *SortedCollection[T]) Foreach(f func(item T, key int)) {
+	for i, v := range sc.value {
+		f(v, i)
+	}
+}
+
+// Values 按顺序返回所有元素的副本
+func (sc *SortedCollection[T]) Values() []T {
+	return append(make([]T, 0, len(sc.value)), sc.value...)
+}
+
+// Copy 复制一个新的 SortedCollection
+func (sc *SortedCollection[T]) Copy() *SortedCollection[T] {
+	return &SortedCollection[T]{value: sc.Values(), compareFunc: sc.compareFunc}
+}
+
+// ToCollection 转换为 Collection，保留比较函数
+func (sc *SortedCollection[T]) ToCollection() *Collection[T] {
+	return sc.newCollection(sc.Values())
+}
+
+// ToJson 获取json
+func (sc *SortedCollection[T]) ToJson() ([]byte, error) {
+	return json.Marshal(sc.value)
+}
+
+// FromJson 从json中获取数据并重新排序
+func (sc *SortedCollection[T]) FromJson(data []byte) error {
+	if err := json.Unmarshal(data, &sc.value); err != nil {
+		return err
+	}
+	sc.sort()
+	return nil
+}
+
+// sort 按比较函数稳定排序
+func (sc *SortedCollection[T]) sort() {
+	sort.SliceStable(sc.value, func(i, j int) bool {
+		return sc.compareFunc(sc.value[i], sc.value[j]) < 0
+	})
+}
+
+// merge 归并 sc 与 other，相等的元素保留 sc 中的一段并跳过 other 中的一段；
+// keepOnly 为 true 时还保留只出现在一侧的元素（并集），否则丢弃（交集）
+func (sc *SortedCollection[T]) merge(other *SortedCollection[T], keepOnly bool) *SortedCollection[T] {
+	a, b := sc.value, other.value
+	res := make([]T, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch cmp := sc.compareFunc(a[i], b[j]); {
+		case cmp < 0:
+			if keepOnly {
+				res = append(res, a[i])
+			}
+			i++
+		case cmp > 0:
+			if keepOnly {
+				res = append(res, b[j])
+			}
+			j++
+		default:
+			pivot := a[i]
+			for ; i < len(a) && sc.compareFunc(a[i], pivot) == 0; i++ {
+				res = append(res, a[i])
+			}
+			for ; j < len(b) && sc.compareFunc(b[j], pivot) == 0; j++ {
+			}
+		}
+	}
+	if keepOnly {
+		res = append(res, a[i:]...)
+		res = append(res, b[j:]...)
+	}
+
+	return &SortedCollection[T]{value: res, compareFunc: sc.compareFunc}
+}
+
+// newCollection 创建使用相同比较函数的 Collection
+func (sc *SortedCollection[T]) newCollection(values []T) *Collection[T] {
+	coll := NewCollection(values)
+	coll.compareFunc = sc.compareFunc
+	return coll
+}
@@ -0,0 +1,152 @@
+package slice_collcection
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+func TestSortedCollectionInsertAndBounds(t *testing.T) {
+	sc, err := NewSortedCollection[int](nil, 5, 1, 3, 3)
+	if err != nil {
+		t.Fatalf("NewSortedCollection returned error: %v", err)
+	}
+	sc.Insert(4, 3, 0)
+	if !reflect.DeepEqual(sc.Values(), []int{0, 1, 3, 3, 3, 4, 5}) {
+		t.Fatalf("Unexpected values %v", sc.Values())
+	}
+
+	if sc.LowerBound(3) != 2 || sc.UpperBound(3) != 5 {
+		t.Errorf("Unexpected bounds %d %d", sc.LowerBound(3), sc.UpperBound(3))
+	}
+	if lo, hi := sc.EqualRange(2); lo != 2 || hi != 2 {
+		t.Errorf("Unexpected equal range for missing item %d %d", lo, hi)
+	}
+	if sc.LowerBound(9) != sc.Count() || sc.UpperBound(-1) != 0 {
+		t.Error("Unexpected bounds at the edges")
+	}
+	if sc.IndexOf(3) != 2 || sc.IndexOf(2) != -1 || sc.CountOf(3) != 3 {
+		t.Error("Unexpected IndexOf/CountOf result")
+	}
+	if _, err := sc.Search(7); !errors.Is(err, errorx.NotFoundError) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+
+	if got := sc.RangeBetween(1, 4).Values(); !reflect.DeepEqual(got, []int{1, 3, 3, 3, 4}) {
+		t.Errorf("Unexpected range %v", got)
+	}
+	if got := sc.RangeBetween(4, 1); !got.IsEmpty() {
+		t.Errorf("Expected empty range, got %v", got.Values())
+	}
+
+	if !sc.Remove(3) || sc.CountOf(3) != 2 || sc.Remove(42) {
+		t.Error("Unexpected Remove result")
+	}
+	if first, _ := sc.First(); first != 0 {
+		t.Errorf("Unexpected first %d", first)
+	}
+	if last, _ := sc.Last(); last != 5 {
+		t.Errorf("Unexpected last %d", last)
+	}
+}
+
+func TestSortedCollectionStableInsert(t *testing.T) {
+	type task struct {
+		Name     string
+		Priority int
+	}
+	byPriority := func(a, b any) int {
+		return a.(task).Priority - b.(task).Priority
+	}
+	sc, err := NewSortedCollection(byPriority, task{"b", 2}, task{"a", 1})
+	if err != nil {
+		t.Fatalf("NewSortedCollection returned error: %v", err)
+	}
+	sc.Insert(task{"c", 2}, task{"d", 1})
+
+	names := make([]string, 0, sc.Count())
+	sc.Foreach(func(item task, key int) {
+		names = append(names, item.Name)
+	})
+	if !reflect.DeepEqual(names, []string{"a", "d", "b", "c"}) {
+		t.Errorf("Equal items should keep insertion order, got %v", names)
+	}
+
+	if _, err := NewSortedCollection[task](nil); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+	if _, err := NewSortedCollectionFrom(NewCollection([]task{{"x", 1}})); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError, got %v", err)
+	}
+}
+
+func TestSortedCollectionUnionIntersect(t *testing.T) {
+	a, _ := NewSortedCollectionFrom(NewCollection([]int{1, 2, 2, 4, 6}))
+	b, _ := NewSortedCollection[int](nil, 2, 3, 3, 6, 7)
+
+	if got := a.Union(b).Values(); !reflect.DeepEqual(got, []int{1, 2, 2, 3, 3, 4, 6, 7}) {
+		t.Errorf("Unexpected union %v", got)
+	}
+	if got := a.Intersect(b).Values(); !reflect.DeepEqual(got, []int{2, 2, 6}) {
+		t.Errorf("Unexpected intersect %v", got)
+	}
+
+	// 与 Collection 的结果一致（忽略顺序）
+	r := rand.New(rand.NewSource(7))
+	for round := 0; round < 50; round++ {
+		x, y := make([]int, r.Intn(20)), make([]int, r.Intn(20))
+		for i := range x {
+			x[i] = r.Intn(10)
+		}
+		for i := range y {
+			y[i] = r.Intn(10)
+		}
+		sx, _ := NewSortedCollection[int](nil, x...)
+		sy, _ := NewSortedCollection[int](nil, y...)
+
+		union, _ := NewCollection(append([]int(nil), x...)).Union(NewCollection(y))
+		intersect, _ := NewCollection(append([]int(nil), x...)).Intersect(NewCollection(y))
+		expectedUnion, expectedIntersect := union.Values(), intersect.Values()
+		sort.Ints(expectedUnion)
+		sort.Ints(expectedIntersect)
+
+		if got := sx.Union(sy).Values(); !reflect.DeepEqual(got, expectedUnion) && len(got)+len(expectedUnion) > 0 {
+			t.Fatalf("Union mismatch for %v %v: expected %v, got %v", x, y, expectedUnion, got)
+		}
+		if got := sx.Intersect(sy).Values(); !reflect.DeepEqual(got, expectedIntersect) && len(got)+len(expectedIntersect) > 0 {
+			t.Fatalf("Intersect mismatch for %v %v: expected %v, got %v", x, y, expectedIntersect, got)
+		}
+	}
+}
+
+func TestSortedCollectionConversion(t *testing.T) {
+	desc := func(a, b any) int { return b.(int) - a.(int) }
+	c := NewCollection([]int{3, 1, 2}).SetCompare(desc)
+	sc, err := NewSortedCollectionFrom(c)
+	if err != nil {
+		t.Fatalf("NewSortedCollectionFrom returned error: %v", err)
+	}
+	if !reflect.DeepEqual(sc.Values(), []int{3, 2, 1}) || !reflect.DeepEqual(c.Values(), []int{3, 1, 2}) {
+		t.Errorf("Unexpected values %v (source %v)", sc.Values(), c.Values())
+	}
+	if minValue, err := sc.ToCollection().Min(); err != nil || minValue != 3 {
+		t.Errorf("ToCollection should keep the compare func: %v %v", minValue, err)
+	}
+
+	sc.SetCompare(nil).SetCompare(func(a, b any) int { return a.(int) - b.(int) })
+	if !reflect.DeepEqual(sc.Values(), []int{1, 2, 3}) {
+		t.Errorf("SetCompare should resort, got %v", sc.Values())
+	}
+
+	if err := sc.FromJson([]byte(`[9,4,6]`)); err != nil {
+		t.Fatalf("FromJson returned error: %v", err)
+	}
+	data, _ := sc.ToJson()
+	if string(data) != `[4,6,9]` {
+		t.Errorf("Unexpected json %s", data)
+	}
+}
@@ -0,0 +1,97 @@
+package slice_collcection
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// MeltRow 长表中的一行：ID 标识原始记录，Field 为被展开的字段名，Value 为字段值
+type MeltRow[I any, V any] struct {
+	ID    I      `json:"id"`
+	Field string `json:"field"`
+	Value V      `json:"value"`
+}
+
+// Unpivot 将宽表形式的结构体展开为长表（melt）
+// idFn 提取每条记录的标识；fields 为需要展开的字段（字段名或 json tag），
+// 为空时展开所有类型可以赋值给 V 的导出字段。字段为数值类型且 V 也为数值类型时会做类型转换。
+// 指针字段自动解引用，值为 nil 的字段不会出现在结果中。
+//
+// 使用示例：
+//
+//	// {Region: "east", Jan: 10, Feb: 20} => {east Jan 10}, {east Feb 20}
+//	long, err := slice_collcection.Unpivot[Report, string, float64](reports,
+//	    func(r Report) string { return r.Region }, "Jan", "Feb")
+func Unpivot[T any, I any, V any](c *Collection[T], idFn func(item T) I, fields ...string) (*Collection[MeltRow[I, V]], error) {
+	if idFn == nil {
+		return nil, errorx.NilFunc
+	}
+	valueType := reflect.TypeOf((*V)(nil)).Elem()
+
+	accessors, err := unpivotFields(c.typ, valueType, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]MeltRow[I, V], 0, len(c.value)*len(accessors))
+	for _, item := range c.value {
+		id := idFn(item)
+		for _, accessor := range accessors {
+			fv, ok := derefField(accessor, item)
+			if !ok {
+				continue
+			}
+			if !fv.Type().AssignableTo(valueType) {
+				fv = fv.Convert(valueType)
+			}
+			rows = append(rows, MeltRow[I, V]{ID: id, Field: accessor.name, Value: fv.Interface().(V)})
+		}
+	}
+
+	return NewCollection(rows), nil
+}
+
+// unpivotFields 解析需要展开的字段，并检查字段类型与 valueType 是否兼容
+func unpivotFields(elemType reflect.Type, valueType reflect.Type, fields []string) ([]*fieldAccessor, error) {
+	if len(fields) > 0 {
+		accessors := make([]*fieldAccessor, 0, len(fields))
+		for _, field := range fields {
+			accessor, err := resolveField(elemType, field)
+			if err != nil {
+				return nil, err
+			}
+			if !meltCompatible(derefType(accessor.typ), valueType) {
+				return nil, fmt.Errorf("%w: field %s (%s) cannot be used as %s", errorx.InvalidTypeError, field, accessor.typ, valueType)
+			}
+			accessors = append(accessors, accessor)
+		}
+		return accessors, nil
+	}
+
+	structType := derefTypeOrNil(elemType)
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: collection `T` must be a struct or pointer to struct", errorx.InvalidTypeError)
+	}
+	accessors := make([]*fieldAccessor, 0)
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+		if derefType(field.Type).AssignableTo(valueType) {
+			accessors = append(accessors, &fieldAccessor{name: field.Name, index: field.Index, typ: field.Type})
+		}
+	}
+
+	return accessors, nil
+}
+
+// meltCompatible 字段类型可以赋值给 valueType，或两者都是数值类型
+func meltCompatible(fieldType, valueType reflect.Type) bool {
+	if fieldType.AssignableTo(valueType) {
+		return true
+	}
+	return utils.IsComputableKind(fieldType.Kind()) && utils.IsComputableKind(valueType.Kind())
+}
@@ -0,0 +1,68 @@
+package slice_collcection
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+type wideReport struct {
+	Region string
+	Jan    float64 `json:"jan"`
+	Feb    int
+	Mar    *float64
+	Note   string
+}
+
+func TestUnpivot(t *testing.T) {
+	mar := 7.5
+	reports := NewCollection([]wideReport{
+		{Region: "east", Jan: 10, Feb: 20, Mar: &mar},
+		{Region: "west", Jan: 1, Feb: 2},
+	})
+
+	long, err := Unpivot[wideReport, string, float64](reports, func(r wideReport) string { return r.Region }, "jan", "Feb", "Mar")
+	if err != nil {
+		t.Fatalf("Unpivot returned error: %v", err)
+	}
+	expected := []MeltRow[string, float64]{
+		{ID: "east", Field: "jan", Value: 10},
+		{ID: "east", Field: "Feb", Value: 20},
+		{ID: "east", Field: "Mar", Value: 7.5},
+		{ID: "west", Field: "jan", Value: 1},
+		{ID: "west", Field: "Feb", Value: 2},
+	}
+	if !reflect.DeepEqual(long.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, long.Values())
+	}
+
+	// 未指定字段时展开所有可赋值给 V 的字段
+	strs, err := Unpivot[wideReport, int, string](reports, func(wideReport) int { return 0 })
+	if err != nil {
+		t.Fatalf("Unpivot returned error: %v", err)
+	}
+	fields := make([]string, 0)
+	for _, row := range strs.Values()[:2] {
+		fields = append(fields, row.Field)
+	}
+	if !reflect.DeepEqual(fields, []string{"Region", "Note"}) {
+		t.Errorf("Unexpected auto fields %v", fields)
+	}
+}
+
+func TestUnpivotErrors(t *testing.T) {
+	reports := NewCollection([]wideReport{{Region: "east"}})
+	id := func(r wideReport) string { return r.Region }
+
+	if _, err := Unpivot[wideReport, string, float64](reports, id, "Note"); !errors.Is(err, errorx.InvalidTypeError) {
+		t.Errorf("Expected InvalidTypeError, got %v", err)
+	}
+	if _, err := Unpivot[wideReport, string, float64](reports, id, "Apr"); !errors.Is(err, errorx.FieldNotFoundError) {
+		t.Errorf("Expected FieldNotFoundError, got %v", err)
+	}
+	if _, err := Unpivot[wideReport, string, float64](reports, nil); !errors.Is(err, errorx.NilFunc) {
+		t.Errorf("Expected NilFunc, got %v", err)
+	}
+}
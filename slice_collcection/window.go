@@ -0,0 +1,212 @@
+package slice_collcection
+
+import (
+	"github.com/ZHOUXING1997/collection/utils"
+)
+
+// WindowSpec 窗口定义：按 partitionBy 分区，分区内按 orderBy 排序
+// 通过 RowNumber、Rank、Lag、RunningSum 等窗口函数为每个元素计算结果
+type WindowSpec[T any, K comparable] struct {
+	partitions []*windowPartition[T, K]
+	compare    func(a, b T) int
+}
+
+// windowPartition 一个分区内排序后的元素
+type windowPartition[T any, K comparable] struct {
+	key     K
+	entries []windowEntry[T]
+}
+
+// windowEntry 元素及其在原 Collection 中的下标
+type windowEntry[T any] struct {
+	item  T
+	index int
+}
+
+// WindowRow 窗口函数的结果行
+type WindowRow[T any, K comparable, R any] struct {
+	Item      T    `json:"item"`      // 元素
+	Partition K    `json:"partition"` // 所在分区
+	Index     int  `json:"index"`     // 元素在原 Collection 中的下标
+	Value     R    `json:"value"`     // 窗口函数的结果
+	Valid     bool `json:"valid"`     // Lag/Lead 越界或 MovingAvg 窗口不足时为 false
+}
+
+// Window 定义窗口，类似 SQL 的 OVER (PARTITION BY ... ORDER BY ...)
+// partitionBy 为 nil 时所有元素位于同一分区；orderBy 为 nil 时使用 Collection 的比较函数，
+// 比较函数也未设置时保持原有顺序（此时所有元素互为并列）。
+// 分区按首次出现的顺序排列，分区内使用 SortByFunc 稳定排序，窗口函数的结果按分区、分区内顺序输出。
+//
+// 使用示例：
+//
+//	w := slice_collcection.Window(orders,
+//	    func(o Order) string { return o.UserID },
+//	    func(a, b Order) int { return a.CreatedAt.Compare(b.CreatedAt) })
+//	balances := slice_collcection.RunningSum(w, func(o Order) float64 { return o.Amount })
+func Window[T any, K comparable](c *Collection[T], partitionBy func(item T) K, orderBy func(a, b T) int) *WindowSpec[T, K] {
+	compare := orderBy
+	if compare == nil && c.compareFunc != nil {
+		compare = func(a, b T) int {
+			return c.compareFunc(a, b)
+		}
+	}
+
+	spec := &WindowSpec[T, K]{compare: compare}
+	byKey := make(map[K]*windowPartition[T, K])
+	for i, item := range c.value {
+		var key K
+		if partitionBy != nil {
+			key = partitionBy(item)
+		}
+		p, ok := byKey[key]
+		if !ok {
+			p = &windowPartition[T, K]{key: key}
+			byKey[key] = p
+			spec.partitions = append(spec.partitions, p)
+		}
+		p.entries = append(p.entries, windowEntry[T]{item: item, index: i})
+	}
+
+	if compare != nil {
+		for _, p := range spec.partitions {
+			_, _ = NewCollection(p.entries).SortByFunc(func(a, b windowEntry[T]) bool {
+				return compare(a.item, b.item) < 0
+			})
+		}
+	}
+
+	return spec
+}
+
+// RowNumber 分区内的行号，从 1 开始
+func (w *WindowSpec[T, K]) RowNumber() *Collection[WindowRow[T, K, int]] {
+	return windowApply(w, func(p *windowPartition[T, K], i int) (int, bool) {
+		return i + 1, true
+	})
+}
+
+// Rank 分区内的排名，并列的元素排名相同，之后的排名会跳过（1, 1, 3）
+func (w *WindowSpec[T, K]) Rank() *Collection[WindowRow[T, K, int]] {
+	var start int
+	return windowApply(w, func(p *windowPartition[T, K], i int) (int, bool) {
+		start = w.peerStart(p, i, start)
+		return start + 1, true
+	})
+}
+
+// DenseRank 分区内的排名，并列的元素排名相同，之后的排名连续（1, 1, 2）
+func (w *WindowSpec[T, K]) DenseRank() *Collection[WindowRow[T, K, int]] {
+	var rank int
+	return windowApply(w, func(p *windowPartition[T, K], i int) (int, bool) {
+		if i == 0 {
+			rank = 1
+		} else if !w.isPeer(p, i-1, i) {
+			rank++
+		}
+		return rank, true
+	})
+}
+
+// PercentRank 分区内的相对排名 (rank - 1) / (分区行数 - 1)，分区只有一行时为 0
+func (w *WindowSpec[T, K]) PercentRank() *Collection[WindowRow[T, K, float64]] {
+	var start int
+	return windowApply(w, func(p *windowPartition[T, K], i int) (float64, bool) {
+		start = w.peerStart(p, i, start)
+		if len(p.entries) == 1 {
+			return 0, true
+		}
+		return float64(start) / float64(len(p.entries)-1), true
+	})
+}
+
+// Lag 分区内前第 n 行的元素，不存在时 Valid 为 false
+func (w *WindowSpec[T, K]) Lag(n int) *Collection[WindowRow[T, K, T]] {
+	return w.offset(-n)
+}
+
+// Lead 分区内后第 n 行的元素，不存在时 Valid 为 false
+func (w *WindowSpec[T, K]) Lead(n int) *Collection[WindowRow[T, K, T]] {
+	return w.offset(n)
+}
+
+// FirstValue 分区内排序后的第一个元素
+func (w *WindowSpec[T, K]) FirstValue() *Collection[WindowRow[T, K, T]] {
+	return windowApply(w, func(p *windowPartition[T, K], _ int) (T, bool) {
+		return p.entries[0].item, true
+	})
+}
+
+// RunningSum 分区内从第一行到当前行的累计和（按行累计，并列的元素不会合并计算）
+func RunningSum[T any, K comparable, N utils.Number](w *WindowSpec[T, K], valueFn func(item T) N) *Collection[WindowRow[T, K, N]] {
+	var sum N
+	return windowApply(w, func(p *windowPartition[T, K], i int) (N, bool) {
+		if i == 0 {
+			sum = 0
+		}
+		sum += valueFn(p.entries[i].item)
+		return sum, true
+	})
+}
+
+// MovingAvg 分区内以当前行结尾、最多 k 行的移动平均；不足 k 行时按已有的行计算，Valid 为 false
+func MovingAvg[T any, K comparable, N utils.Number](w *WindowSpec[T, K], k int, valueFn func(item T) N) *Collection[WindowRow[T, K, float64]] {
+	if k <= 0 {
+		k = 1
+	}
+	var sum float64
+	return windowApply(w, func(p *windowPartition[T, K], i int) (float64, bool) {
+		if i == 0 {
+			sum = 0
+		}
+		sum += float64(valueFn(p.entries[i].item))
+		if i >= k {
+			sum -= float64(valueFn(p.entries[i-k].item))
+		}
+		size := min(i+1, k)
+		return sum / float64(size), size == k
+	})
+}
+
+// offset Lag/Lead 的实现
+func (w *WindowSpec[T, K]) offset(n int) *Collection[WindowRow[T, K, T]] {
+	return windowApply(w, func(p *windowPartition[T, K], i int) (T, bool) {
+		j := i + n
+		if j < 0 || j >= len(p.entries) {
+			var zero T
+			return zero, false
+		}
+		return p.entries[j].item, true
+	})
+}
+
+// peerStart 返回与第 i 行并列的第一行的位置，prev 为第 i-1 行的结果（需要按顺序调用）
+func (w *WindowSpec[T, K]) peerStart(p *windowPartition[T, K], i int, prev int) int {
+	if i == 0 || !w.isPeer(p, i-1, i) {
+		return i
+	}
+	return prev
+}
+
+// isPeer 第 i 行与第 j 行是否并列
+func (w *WindowSpec[T, K]) isPeer(p *windowPartition[T, K], i, j int) bool {
+	return w.compare == nil || w.compare(p.entries[i].item, p.entries[j].item) == 0
+}
+
+// windowApply 按分区、分区内顺序依次调用 fn，生成结果行
+func windowApply[T any, K comparable, R any](w *WindowSpec[T, K], fn func(p *windowPartition[T, K], i int) (R, bool)) *Collection[WindowRow[T, K, R]] {
+	rows := make([]WindowRow[T, K, R], 0)
+	for _, p := range w.partitions {
+		for i, entry := range p.entries {
+			value, valid := fn(p, i)
+			rows = append(rows, WindowRow[T, K, R]{
+				Item:      entry.item,
+				Partition: p.key,
+				Index:     entry.index,
+				Value:     value,
+				Valid:     valid,
+			})
+		}
+	}
+
+	return NewCollection(rows)
+}
@@ -0,0 +1,156 @@
+package slice_collcection
+
+import (
+	"reflect"
+	"testing"
+)
+
+type windowSale struct {
+	Region string
+	Rep    string
+	Amount int
+}
+
+// TestWindowRanking tests the RowNumber, Rank, DenseRank and PercentRank methods of the WindowSpec struct
+func TestWindowRanking(t *testing.T) {
+	sales := NewCollection([]windowSale{
+		{"east", "ann", 300},
+		{"west", "bob", 100},
+		{"east", "cat", 500},
+		{"east", "dan", 300},
+		{"west", "eve", 200},
+		{"east", "fay", 100},
+	})
+	w := Window(sales,
+		func(s windowSale) string { return s.Region },
+		func(a, b windowSale) int { return b.Amount - a.Amount })
+
+	rowNumbers, ranks, denseRanks, percentRanks := w.RowNumber().Values(), w.Rank().Values(), w.DenseRank().Values(), w.PercentRank().Values()
+	reps := make([]string, 0, len(rowNumbers))
+	gotRowNumbers, gotRanks, gotDenseRanks := make([]int, 0), make([]int, 0), make([]int, 0)
+	gotPercentRanks := make([]float64, 0)
+	for i := range rowNumbers {
+		reps = append(reps, rowNumbers[i].Item.Rep)
+		gotRowNumbers = append(gotRowNumbers, rowNumbers[i].Value)
+		gotRanks = append(gotRanks, ranks[i].Value)
+		gotDenseRanks = append(gotDenseRanks, denseRanks[i].Value)
+		gotPercentRanks = append(gotPercentRanks, percentRanks[i].Value)
+	}
+	if !reflect.DeepEqual(reps, []string{"cat", "ann", "dan", "fay", "eve", "bob"}) {
+		t.Errorf("Unexpected window order %v", reps)
+	}
+	if !reflect.DeepEqual(gotRowNumbers, []int{1, 2, 3, 4, 1, 2}) {
+		t.Errorf("Unexpected row numbers %v", gotRowNumbers)
+	}
+	if !reflect.DeepEqual(gotRanks, []int{1, 2, 2, 4, 1, 2}) {
+		t.Errorf("Unexpected ranks %v", gotRanks)
+	}
+	if !reflect.DeepEqual(gotDenseRanks, []int{1, 2, 2, 3, 1, 2}) {
+		t.Errorf("Unexpected dense ranks %v", gotDenseRanks)
+	}
+	if !reflect.DeepEqual(gotPercentRanks, []float64{0, 1.0 / 3, 1.0 / 3, 1, 0, 1}) {
+		t.Errorf("Unexpected percent ranks %v", gotPercentRanks)
+	}
+
+	first := rowNumbers[0]
+	if first.Partition != "east" || first.Index != 2 || !first.Valid {
+		t.Errorf("Unexpected row metadata %+v", first)
+	}
+}
+
+// TestWindowOffsets tests the Lag, Lead and FirstValue methods of the WindowSpec struct
+func TestWindowOffsets(t *testing.T) {
+	sales := NewCollection([]windowSale{
+		{"east", "ann", 300},
+		{"west", "bob", 100},
+		{"east", "cat", 500},
+		{"east", "dan", 300},
+		{"west", "eve", 200},
+		{"east", "fay", 100},
+	})
+	w := Window(sales,
+		func(s windowSale) string { return s.Region },
+		func(a, b windowSale) int { return b.Amount - a.Amount })
+
+	lag := w.Lag(1).Values()
+	if lag[0].Valid || !lag[1].Valid || lag[1].Value.Rep != "cat" || lag[4].Valid {
+		t.Errorf("Unexpected lag rows %+v", lag)
+	}
+
+	lead := w.Lead(2).Values()
+	if lead[1].Value.Rep != "fay" || lead[2].Valid || lead[4].Valid {
+		t.Errorf("Unexpected lead rows %+v", lead)
+	}
+
+	reps := make([]string, 0)
+	for _, row := range w.FirstValue().Values() {
+		reps = append(reps, row.Value.Rep)
+	}
+	if !reflect.DeepEqual(reps, []string{"cat", "cat", "cat", "cat", "eve", "eve"}) {
+		t.Errorf("Unexpected first values %v", reps)
+	}
+}
+
+// TestWindowAggregates tests the RunningSum and MovingAvg functions
+func TestWindowAggregates(t *testing.T) {
+	sales := NewCollection([]windowSale{
+		{"east", "ann", 300},
+		{"west", "bob", 100},
+		{"east", "cat", 500},
+		{"east", "dan", 300},
+		{"west", "eve", 200},
+		{"east", "fay", 100},
+	})
+	// 不分区，按原有顺序（未设置比较函数）
+	w := Window[windowSale, struct{}](sales, nil, nil)
+
+	sums := make([]int, 0)
+	for _, row := range RunningSum(w, func(s windowSale) int { return s.Amount }).Values() {
+		sums = append(sums, row.Value)
+	}
+	if !reflect.DeepEqual(sums, []int{300, 400, 900, 1200, 1400, 1500}) {
+		t.Errorf("Unexpected running sums %v", sums)
+	}
+
+	rows := MovingAvg(w, 3, func(s windowSale) int { return s.Amount }).Values()
+	avgs := make([]float64, 0, len(rows))
+	valid := make([]bool, 0, len(rows))
+	for _, row := range rows {
+		avgs = append(avgs, row.Value)
+		valid = append(valid, row.Valid)
+	}
+	if !reflect.DeepEqual(avgs, []float64{300, 200, 300, 300, 1000.0 / 3, 200}) {
+		t.Errorf("Unexpected moving averages %v", avgs)
+	}
+	if !reflect.DeepEqual(valid, []bool{false, false, true, true, true, true}) {
+		t.Errorf("Unexpected moving average validity %v", valid)
+	}
+
+	// 没有排序时所有元素并列
+	ranks := make([]int, 0)
+	for _, row := range w.Rank().Values() {
+		ranks = append(ranks, row.Value)
+	}
+	if !reflect.DeepEqual(ranks, []int{1, 1, 1, 1, 1, 1}) {
+		t.Errorf("Unexpected ranks without order %v", ranks)
+	}
+}
+
+// TestWindowUsesCollectionCompare tests that Window falls back to the compare function of the Collection
+func TestWindowUsesCollectionCompare(t *testing.T) {
+	c := NewCollection([]int{30, 10, 20, 10})
+	rows := Window[int, bool](c, nil, nil).DenseRank().Values()
+
+	items := make([]int, 0, len(rows))
+	ranks := make([]int, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, row.Item)
+		ranks = append(ranks, row.Value)
+	}
+	if !reflect.DeepEqual(items, []int{10, 10, 20, 30}) || !reflect.DeepEqual(ranks, []int{1, 1, 2, 3}) {
+		t.Errorf("Unexpected result %v %v", items, ranks)
+	}
+	if !reflect.DeepEqual(c.Values(), []int{30, 10, 20, 10}) {
+		t.Error("Window should not modify original collection")
+	}
+}
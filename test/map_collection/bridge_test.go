@@ -0,0 +1,94 @@
+package map_collection
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/map_collection"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+type bridgeUser struct {
+	ID     int
+	Tenant string
+	Amount int
+}
+
+func TestKeyBy(t *testing.T) {
+	users := slice_collcection.NewCollection([]*bridgeUser{
+		{ID: 3, Tenant: "t1"},
+		{ID: 1, Tenant: "t2"},
+		{ID: 2, Tenant: "t1"},
+	})
+
+	c := map_collection.KeyBy(users, func(u *bridgeUser) int { return u.ID })
+	if c.Count() != 3 {
+		t.Fatalf("Expected 3 entries, got %d", c.Count())
+	}
+	if u, ok := c.Get(1); !ok || u.Tenant != "t2" {
+		t.Errorf("Expected user 1 in tenant t2, got %+v", u)
+	}
+
+	// 保持首次出现的顺序
+	keys := make([]int, 0)
+	c.Foreach(func(_ *bridgeUser, k int) {
+		keys = append(keys, k)
+	})
+	if !reflect.DeepEqual(keys, []int{3, 1, 2}) {
+		t.Errorf("Expected keys in first-seen order [3 1 2], got %v", keys)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	users := slice_collcection.NewCollection([]bridgeUser{
+		{ID: 1, Tenant: "t1", Amount: 10},
+		{ID: 2, Tenant: "t2", Amount: 5},
+		{ID: 3, Tenant: "t1", Amount: 7},
+	})
+
+	sums := map_collection.ToMap(users,
+		func(u bridgeUser) string { return u.Tenant },
+		func(u bridgeUser) int { return u.Amount },
+		func(_ string, existing, incoming int) int { return existing + incoming },
+	)
+	if !reflect.DeepEqual(sums.All(), map[string]int{"t1": 17, "t2": 5}) {
+		t.Errorf("Unexpected result: %v", sums.All())
+	}
+	if k, _, _ := sums.First(); k != "t1" {
+		t.Errorf("Expected first key t1, got %s", k)
+	}
+
+	// onCollision 为 nil 时后者覆盖前者
+	last := map_collection.ToMap(users,
+		func(u bridgeUser) string { return u.Tenant },
+		func(u bridgeUser) int { return u.ID },
+		nil,
+	)
+	if last.GetValue("t1") != 3 {
+		t.Errorf("Expected t1 -> 3, got %d", last.GetValue("t1"))
+	}
+}
+
+func TestEntries(t *testing.T) {
+	c := map_collection.NewCollection(map[string]int{"b": 2, "a": 1, "c": 3},
+		map_collection.WithKeyCompare[string, int](stringCompare))
+
+	entries := c.Entries()
+	expected := []map_collection.Pair[string, int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3}}
+	if !reflect.DeepEqual(entries.Values(), expected) {
+		t.Errorf("Expected %v, got %v", expected, entries.Values())
+	}
+
+	// 往返转换
+	back := map_collection.FromEntries(entries.Filter(func(p map_collection.Pair[string, int], _ int) bool {
+		return p.Value > 1
+	}))
+	if !reflect.DeepEqual(back.All(), map[string]int{"b": 2, "c": 3}) {
+		t.Errorf("Unexpected round trip result: %v", back.All())
+	}
+
+	sc := map_collection.NewSafeCollection(map[string]int{"x": 1})
+	if sc.Entries().Count() != 1 {
+		t.Error("SafeCollection Entries returned wrong count")
+	}
+}
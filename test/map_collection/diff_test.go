@@ -0,0 +1,248 @@
+package map_collection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+)
+
+func TestDiff(t *testing.T) {
+	a := map_collection.NewCollection(map[string]int{"a": 1, "b": 2, "c": 3},
+		map_collection.WithKeyCompare[string, int](stringCompare))
+	b := map_collection.NewCollection(map[string]int{"b": 2, "c": 30, "d": 4, "e": 5},
+		map_collection.WithKeyCompare[string, int](stringCompare))
+
+	diff := map_collection.Diff(a, b, nil)
+
+	expectedAdded := []map_collection.DiffEntry[string, int]{{Key: "d", Value: 4}, {Key: "e", Value: 5}}
+	if !reflect.DeepEqual(diff.Added, expectedAdded) {
+		t.Errorf("Expected added %v, got %v", expectedAdded, diff.Added)
+	}
+	expectedRemoved := []map_collection.DiffEntry[string, int]{{Key: "a", Value: 1}}
+	if !reflect.DeepEqual(diff.Removed, expectedRemoved) {
+		t.Errorf("Expected removed %v, got %v", expectedRemoved, diff.Removed)
+	}
+	expectedChanged := []map_collection.DiffChange[string, int]{{Key: "c", Old: 3, New: 30}}
+	if !reflect.DeepEqual(diff.Changed, expectedChanged) {
+		t.Errorf("Expected changed %v, got %v", expectedChanged, diff.Changed)
+	}
+
+	if !map_collection.Diff(a, a.Copy(), nil).IsEmpty() {
+		t.Error("Diff of equal collections should be empty")
+	}
+}
+
+func TestDiffWithEq(t *testing.T) {
+	a := map_collection.NewCollection(map[string]float64{"x": 1.0001})
+	b := map_collection.NewCollection(map[string]float64{"x": 1.0002})
+
+	diff := map_collection.Diff(a, b, func(v1, v2 float64) bool {
+		return v1-v2 < 0.001 && v2-v1 < 0.001
+	})
+	if !diff.IsEmpty() {
+		t.Errorf("Expected no diff with tolerant eq, got %+v", diff)
+	}
+}
+
+func TestApplyDiff(t *testing.T) {
+	a := map_collection.NewCollection(map[string]int{"a": 1, "b": 2})
+	b := map_collection.NewCollection(map[string]int{"b": 20, "c": 3})
+	diff := map_collection.Diff(a, b, nil)
+
+	applied := a.Apply(diff)
+	if !reflect.DeepEqual(applied.All(), b.All()) {
+		t.Errorf("Expected %v, got %v", b.All(), applied.All())
+	}
+	if a.GetValue("b") != 2 {
+		t.Error("Apply should not modify original collection")
+	}
+
+	// 重放到另一个 Collection 上
+	other := map_collection.NewCollection(map[string]int{"a": 1, "z": 26})
+	other.ApplyInPlace(diff)
+	expected := map[string]int{"b": 20, "c": 3, "z": 26}
+	if !reflect.DeepEqual(other.All(), expected) {
+		t.Errorf("Expected %v, got %v", expected, other.All())
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	a := map_collection.NewCollection(map[string]any{
+		"name":  "svc",
+		"debug": true,
+		"db":    map[string]any{"host": "localhost", "port": 5432, "a/b": 1},
+	})
+	b := map_collection.NewCollection(map[string]any{
+		"name":    "svc",
+		"db":      map[string]any{"host": "db.prod", "port": 5432, "user": "app"},
+		"replica": []any{"r1", "r2"},
+	})
+
+	ops := map_collection.JSONPatch(a, b)
+	expected := []map_collection.JSONPatchOperation{
+		{Op: "remove", Path: "/debug"},
+		{Op: "remove", Path: "/db/a~1b"},
+		{Op: "replace", Path: "/db/host", Value: "db.prod"},
+		{Op: "add", Path: "/db/user", Value: "app"},
+		{Op: "add", Path: "/replica", Value: []any{"r1", "r2"}},
+	}
+	// 顶层 key 无排序函数时顺序不固定，按 path 比较
+	if len(ops) != len(expected) {
+		t.Fatalf("Expected %d ops, got %d: %+v", len(expected), len(ops), ops)
+	}
+	byPath := make(map[string]map_collection.JSONPatchOperation)
+	for _, op := range ops {
+		byPath[op.Path] = op
+	}
+	for _, op := range expected {
+		if !reflect.DeepEqual(byPath[op.Path], op) {
+			t.Errorf("Expected op %+v, got %+v", op, byPath[op.Path])
+		}
+	}
+
+	applied, err := map_collection.ApplyJSONPatch(a, ops)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch returned error: %v", err)
+	}
+	if !reflect.DeepEqual(applied.All(), b.All()) {
+		t.Errorf("Expected %v, got %v", b.All(), applied.All())
+	}
+	if a.GetValue("db").(map[string]any)["host"] != "localhost" {
+		t.Error("ApplyJSONPatch should not modify original collection")
+	}
+}
+
+func TestJSONPatchDocumentRoundTrip(t *testing.T) {
+	a := map_collection.NewCollection(map[string]any{"a": 1.0, "n": map[string]any{"x": nil}})
+	b := map_collection.NewCollection(map[string]any{"a": 2.0, "n": map[string]any{"x": nil, "y": nil}})
+
+	doc, err := map_collection.JSONPatchDocument(a, b)
+	if err != nil {
+		t.Fatalf("JSONPatchDocument returned error: %v", err)
+	}
+	expectedDoc := `[{"op":"replace","path":"/a","value":2},{"op":"add","path":"/n/y","value":null}]`
+	if string(doc) != expectedDoc {
+		t.Errorf("Expected %s, got %s", expectedDoc, doc)
+	}
+
+	applied, err := map_collection.ApplyJSONPatchDocument(a, doc)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatchDocument returned error: %v", err)
+	}
+	if !reflect.DeepEqual(applied.All(), b.All()) {
+		t.Errorf("Expected %v, got %v", b.All(), applied.All())
+	}
+}
+
+func TestJSONPatchDeterministic(t *testing.T) {
+	oldMap, newMap := map[string]any{}, map[string]any{}
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("k%02d", i)
+		switch i % 3 {
+		case 0:
+			oldMap[key] = i
+		case 1:
+			newMap[key] = i
+		default:
+			oldMap[key], newMap[key] = i, -i
+		}
+	}
+
+	// 没有 key 比较函数时也按字典序输出，多次生成的文档完全相同
+	var first []byte
+	for i := 0; i < 20; i++ {
+		doc, err := map_collection.JSONPatchDocument(map_collection.NewCollection(oldMap), map_collection.NewCollection(newMap))
+		if err != nil {
+			t.Fatalf("JSONPatchDocument returned error: %v", err)
+		}
+		if first == nil {
+			first = doc
+		} else if string(doc) != string(first) {
+			t.Fatalf("JSONPatchDocument is not deterministic:\n%s\n%s", first, doc)
+		}
+	}
+}
+
+func TestApplyJSONPatchOperations(t *testing.T) {
+	c := map_collection.NewCollection(map[string]any{
+		"tags": []any{"a", "c"},
+		"meta": map[string]any{"v": 1},
+	})
+	doc := []byte(`[
+		{"op":"test","path":"/meta/v","value":1},
+		{"op":"add","path":"/tags/1","value":"b"},
+		{"op":"add","path":"/tags/-","value":"d"},
+		{"op":"copy","from":"/meta/v","path":"/version"},
+		{"op":"move","from":"/meta","path":"/info"},
+		{"op":"replace","path":"/tags/0","value":"A"}
+	]`)
+
+	applied, err := map_collection.ApplyJSONPatchDocument(c, doc)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatchDocument returned error: %v", err)
+	}
+
+	expected := map[string]any{
+		"tags":    []any{"A", "b", "c", "d"},
+		"info":    map[string]any{"v": 1},
+		"version": 1,
+	}
+	if !reflect.DeepEqual(applied.All(), expected) {
+		t.Errorf("Expected %v, got %v", expected, applied.All())
+	}
+	if applied.Has("meta") || !c.Has("meta") {
+		t.Error("move should only affect the patched collection")
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	c := map_collection.NewCollection(map[string]any{"a": 1})
+
+	_, err := map_collection.ApplyJSONPatchDocument(c, []byte(`[{"op":"test","path":"/a","value":2}]`))
+	if !errors.Is(err, errorx.PatchTestFailedError) {
+		t.Errorf("Expected PatchTestFailedError, got %v", err)
+	}
+
+	_, err = map_collection.ApplyJSONPatchDocument(c, []byte(`[{"op":"remove","path":"/missing"}]`))
+	if !errors.Is(err, errorx.InvalidPatchError) {
+		t.Errorf("Expected InvalidPatchError, got %v", err)
+	}
+
+	_, err = map_collection.ApplyJSONPatchDocument(c, []byte(`[{"op":"add","path":"/b"}]`))
+	if !errors.Is(err, errorx.InvalidPatchError) {
+		t.Errorf("Expected InvalidPatchError for missing value, got %v", err)
+	}
+
+	var op map_collection.JSONPatchOperation
+	if err := json.Unmarshal([]byte(`{"op":"bogus","path":"/a"}`), &op); !errors.Is(err, errorx.InvalidPatchError) {
+		t.Errorf("Expected InvalidPatchError for unknown op, got %v", err)
+	}
+}
+
+func TestApplyJSONPatchTypedValues(t *testing.T) {
+	applied, err := map_collection.ApplyJSONPatchDocument(nil, []byte(`[{"op":"add","path":"/a","value":1}]`))
+	if err != nil {
+		t.Fatalf("ApplyJSONPatchDocument on nil collection returned error: %v", err)
+	}
+	if !reflect.DeepEqual(applied.All(), map[string]any{"a": 1.0}) {
+		t.Errorf("Unexpected result %v", applied.All())
+	}
+
+	stock := map[string]int{"apple": 3}
+	c := map_collection.NewCollection(map[string]any{"stock": stock})
+	applied, err = map_collection.ApplyJSONPatchDocument(c, []byte(`[{"op":"replace","path":"/stock/apple","value":5}]`))
+	if err != nil {
+		t.Fatalf("ApplyJSONPatchDocument returned error: %v", err)
+	}
+	if stock["apple"] != 3 {
+		t.Errorf("Typed nested values should not be shared with the original, got %v", stock)
+	}
+	if !reflect.DeepEqual(applied.GetValue("stock"), map[string]any{"apple": 5.0}) {
+		t.Errorf("Unexpected patched value %v", applied.GetValue("stock"))
+	}
+}
@@ -0,0 +1,326 @@
+package map_collection
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+)
+
+type indexUser struct {
+	Email  string
+	Tenant string
+	Age    int
+}
+
+func newIndexedUsers(t *testing.T) *map_collection.Collection[int, indexUser] {
+	c := map_collection.NewCollection(map[int]indexUser{
+		1: {Email: "a@x.com", Tenant: "t1", Age: 30},
+		2: {Email: "b@x.com", Tenant: "t2", Age: 25},
+		3: {Email: "c@x.com", Tenant: "t1", Age: 41},
+	}, map_collection.WithKeyCompare[int, indexUser](func(a, b int) int { return a - b }))
+
+	if err := c.CreateIndex("email", func(u indexUser) any { return u.Email }, true); err != nil {
+		t.Fatalf("CreateIndex email returned error: %v", err)
+	}
+	if err := c.CreateIndex("tenant", func(u indexUser) any { return u.Tenant }, false); err != nil {
+		t.Fatalf("CreateIndex tenant returned error: %v", err)
+	}
+	if err := c.CreateIndex("age", func(u indexUser) any { return u.Age }, false); err != nil {
+		t.Fatalf("CreateIndex age returned error: %v", err)
+	}
+
+	return c
+}
+
+func TestLookupIndex(t *testing.T) {
+	c := newIndexedUsers(t)
+
+	res, err := c.LookupIndex("tenant", "t1")
+	if err != nil {
+		t.Fatalf("LookupIndex returned error: %v", err)
+	}
+	if res.Count() != 2 || !res.Has(1) || !res.Has(3) {
+		t.Errorf("Expected keys 1 and 3, got %v", res.Keys())
+	}
+	if k, _, _ := res.First(); k != 1 {
+		t.Errorf("Expected lookup result ordered by key, first key %d", k)
+	}
+
+	res, _ = c.LookupIndex("email", "b@x.com")
+	if res.Count() != 1 || res.GetValue(2).Tenant != "t2" {
+		t.Errorf("Unexpected lookup result: %v", res.All())
+	}
+
+	if _, err := c.LookupIndex("missing", "x"); !errors.Is(err, errorx.IndexNotFoundError) {
+		t.Errorf("Expected IndexNotFoundError, got %v", err)
+	}
+}
+
+func TestIndexMaintainedOnWrites(t *testing.T) {
+	c := newIndexedUsers(t)
+
+	c.Set(4, indexUser{Email: "d@x.com", Tenant: "t2", Age: 19})
+	c.Set(1, indexUser{Email: "a@x.com", Tenant: "t2", Age: 30})
+	c.Remove(3)
+
+	res, _ := c.LookupIndex("tenant", "t1")
+	if res.Count() != 0 {
+		t.Errorf("Expected no users in t1, got %v", res.All())
+	}
+	res, _ = c.LookupIndex("tenant", "t2")
+	if res.Count() != 3 {
+		t.Errorf("Expected 3 users in t2, got %v", res.All())
+	}
+
+	c.MergeInPlace(map[int]indexUser{5: {Email: "e@x.com", Tenant: "t3"}})
+	res, _ = c.LookupIndex("email", "e@x.com")
+	if !res.Has(5) {
+		t.Error("MergeInPlace should update index")
+	}
+
+	// 返回新 Collection 的写入会按新数据重建索引
+	merged := c.Merge(map[int]indexUser{6: {Email: "f@x.com", Tenant: "t3"}})
+	res, _ = merged.LookupIndex("tenant", "t3")
+	if res.Count() != 2 {
+		t.Errorf("Expected 2 users in t3 after Merge, got %v", res.All())
+	}
+	res, _ = c.LookupIndex("tenant", "t3")
+	if res.Count() != 1 {
+		t.Error("Merge should not affect the index of the original collection")
+	}
+}
+
+func TestUniqueIndex(t *testing.T) {
+	c := newIndexedUsers(t)
+
+	err := c.TrySet(4, indexUser{Email: "a@x.com"})
+	if !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Fatalf("Expected UniqueConstraintError, got %v", err)
+	}
+	var violation *errorx.UniqueViolation
+	if !errors.As(err, &violation) || violation.Index != "email" || violation.ConflictKey != 1 {
+		t.Errorf("Unexpected violation: %+v", violation)
+	}
+	if c.Has(4) {
+		t.Error("TrySet should not write on violation")
+	}
+
+	// 更新自身或交换索引值是允许的
+	if err := c.TrySet(1, indexUser{Email: "a@x.com", Age: 31}); err != nil {
+		t.Errorf("Updating own value should be allowed, got %v", err)
+	}
+	err = c.TryMergeInPlace(map[int]indexUser{
+		1: {Email: "b@x.com"},
+		2: {Email: "a@x.com"},
+	})
+	if err != nil {
+		t.Errorf("Swapping unique values should be allowed, got %v", err)
+	}
+
+	// 同一批写入内部重复
+	err = c.TryMergeInPlace(map[int]indexUser{7: {Email: "z@x.com"}, 8: {Email: "z@x.com"}})
+	if !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Errorf("Expected UniqueConstraintError for duplicated batch, got %v", err)
+	}
+	if c.Has(7) || c.Has(8) {
+		t.Error("TryMergeInPlace should not write on violation")
+	}
+
+	if _, err := c.TryMerge(map[int]indexUser{9: {Email: "c@x.com"}}); !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Errorf("Expected UniqueConstraintError from TryMerge, got %v", err)
+	}
+	_, _, err = c.MergeWith(map[int]indexUser{9: {Email: "c@x.com"}}, map_collection.KeepRight[int, indexUser]())
+	if !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Errorf("Expected UniqueConstraintError from MergeWith, got %v", err)
+	}
+
+	// 已有重复数据时无法创建唯一索引
+	if err := c.CreateIndex("tenant_unique", func(u indexUser) any { return u.Tenant }, true); !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Errorf("Expected UniqueConstraintError on CreateIndex, got %v", err)
+	}
+	if err := c.CreateIndex("email", func(u indexUser) any { return u.Email }, true); !errors.Is(err, errorx.IndexExistsError) {
+		t.Errorf("Expected IndexExistsError, got %v", err)
+	}
+}
+
+func TestChainedWritesIgnoreUniqueIndex(t *testing.T) {
+	c := newIndexedUsers(t)
+
+	// 链式写入不检查唯一约束，重复的索引值对应的元素都能查到
+	c.Set(4, indexUser{Email: "a@x.com"}).MergeInPlace(map[int]indexUser{5: {Email: "a@x.com"}})
+	if !c.Has(4) || !c.Has(5) {
+		t.Fatalf("Chained writes should always apply, got %v", c.Keys())
+	}
+	res, _ := c.LookupIndex("email", "a@x.com")
+	keys := make([]int, 0)
+	res.Foreach(func(_ indexUser, k int) {
+		keys = append(keys, k)
+	})
+	if !reflect.DeepEqual(keys, []int{1, 4, 5}) {
+		t.Errorf("Expected keys [1 4 5], got %v", keys)
+	}
+
+	put := c.Put(6, indexUser{Email: "b@x.com"}).Merge(map[int]indexUser{7: {Email: "b@x.com"}})
+	if !put.Has(6) || !put.Has(7) || c.Has(6) {
+		t.Errorf("Put/Merge should write to the new collection only, got %v / %v", put.Keys(), c.Keys())
+	}
+
+	// Try 系列方法仍然按唯一约束拒绝写入
+	if err := c.TrySet(8, indexUser{Email: "c@x.com"}); !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Errorf("Expected UniqueConstraintError, got %v", err)
+	}
+	if err := c.TrySet(8, indexUser{Email: "h@x.com"}); err != nil || !c.Has(8) {
+		t.Errorf("TrySet with a new value should succeed, got %v", err)
+	}
+}
+
+func TestIndexRange(t *testing.T) {
+	c := newIndexedUsers(t)
+
+	res, err := c.IndexRange("age", 25, 35)
+	if err != nil {
+		t.Fatalf("IndexRange returned error: %v", err)
+	}
+	keys := make([]int, 0)
+	res.Foreach(func(_ indexUser, k int) {
+		keys = append(keys, k)
+	})
+	if !reflect.DeepEqual(keys, []int{2, 1}) {
+		t.Errorf("Expected keys ordered by age [2 1], got %v", keys)
+	}
+
+	res, _ = c.IndexRange("age", 30, nil)
+	if res.Count() != 2 {
+		t.Errorf("Expected 2 users with age >= 30, got %v", res.All())
+	}
+
+	c.Set(4, indexUser{Email: "d@x.com", Age: 28})
+	res, _ = c.IndexRange("age", nil, 29)
+	if res.Count() != 2 {
+		t.Errorf("Expected 2 users with age <= 29, got %v", res.All())
+	}
+}
+
+func TestIndexMixedValues(t *testing.T) {
+	type labelKey struct {
+		Name  string
+		Value any
+	}
+	c := map_collection.NewCollection(map[string]any{
+		"a": 3,
+		"b": "x",
+		"c": nil,
+		"d": 1.5,
+		"e": []int{1},
+		"f": labelKey{Name: "tags", Value: []string{"a"}},
+		"g": labelKey{Name: "tag", Value: "a"},
+	}, map_collection.WithKeyCompare[string, any](func(a, b string) int { return strings.Compare(a, b) }))
+
+	// 接口字段中保存切片的结构体不能作为索引值，不会进入索引
+	if err := c.CreateIndex("raw", func(v any) any { return v }, false); err != nil {
+		t.Fatalf("CreateIndex returned error: %v", err)
+	}
+	c.Set("h", labelKey{Name: "more", Value: map[string]int{}})
+	res, err := c.LookupIndex("raw", labelKey{Name: "tag", Value: "a"})
+	if err != nil || !reflect.DeepEqual(res.Keys(), []string{"g"}) {
+		t.Errorf("Unexpected lookup result %v, %v", res, err)
+	}
+	if _, err := c.LookupIndex("raw", labelKey{Value: []int{1}}); !errors.Is(err, errorx.InvalidTypeError) {
+		t.Errorf("Expected InvalidTypeError, got %v", err)
+	}
+
+	// 范围查询只比较与边界同类的索引值
+	res, err = c.IndexRange("raw", 1, 5)
+	if err != nil {
+		t.Fatalf("IndexRange returned error: %v", err)
+	}
+	keys := make([]string, 0)
+	res.Foreach(func(_ any, k string) {
+		keys = append(keys, k)
+	})
+	if !reflect.DeepEqual(keys, []string{"d", "a"}) {
+		t.Errorf("Expected keys ordered by value [d a], got %v", keys)
+	}
+	res, err = c.IndexRange("raw", "a", nil)
+	if err != nil || !reflect.DeepEqual(res.Keys(), []string{"b"}) {
+		t.Errorf("Expected [b], got %v, %v", res, err)
+	}
+	if res, err = c.IndexRange("raw", nil, nil); err != nil || res.Count() != 3 {
+		t.Errorf("Expected all orderable values, got %v, %v", res, err)
+	}
+	if _, err := c.IndexRange("raw", 1, "z"); !errors.Is(err, errorx.NoComparableError) {
+		t.Errorf("Expected NoComparableError for mismatched bounds, got %v", err)
+	}
+}
+
+func TestSafeCollectionIndex(t *testing.T) {
+	sc := map_collection.NewSafeCollection(map[string]indexUser{"u1": {Email: "a@x.com"}})
+	if err := sc.CreateIndex("email", func(u indexUser) any { return u.Email }, true); err != nil {
+		t.Fatalf("CreateIndex returned error: %v", err)
+	}
+
+	if err := sc.TrySet("u2", indexUser{Email: "a@x.com"}); !errors.Is(err, errorx.UniqueConstraintError) {
+		t.Errorf("Expected UniqueConstraintError, got %v", err)
+	}
+	if err := sc.TryMergeInPlace(map[string]indexUser{"u2": {Email: "b@x.com"}}); err != nil {
+		t.Errorf("TryMergeInPlace returned error: %v", err)
+	}
+
+	res, err := sc.LookupIndex("email", "b@x.com")
+	if err != nil || !res.Has("u2") {
+		t.Errorf("LookupIndex returned %v, %v", res, err)
+	}
+	if _, err := sc.TryMerge(map[string]indexUser{"u3": {Email: "b@x.com"}}); err == nil {
+		t.Error("Expected TryMerge to fail")
+	}
+	if _, err := sc.IndexRange("email", "a", "z"); err != nil {
+		t.Errorf("IndexRange returned error: %v", err)
+	}
+	if err := sc.DropIndex("email"); err != nil {
+		t.Errorf("DropIndex returned error: %v", err)
+	}
+}
+
+func TestSafeCollectionConcurrentIndexedWrites(t *testing.T) {
+	users := make(map[int]indexUser)
+	for i := 0; i < 100; i++ {
+		users[i] = indexUser{Email: fmt.Sprintf("u%d@x.com", i), Age: i}
+	}
+	sc := map_collection.NewSafeCollection(users)
+	if err := sc.CreateIndex("email", func(u indexUser) any { return u.Email }, true); err != nil {
+		t.Fatalf("CreateIndex returned error: %v", err)
+	}
+	// Filter 返回的 Collection 的索引在第一次使用时才会构建
+	filtered := sc.Filter(func(u indexUser, _ int) bool { return u.Age%2 == 0 })
+	other := map_collection.NewSafeCollection(map[int]indexUser{1000: {Email: "new@x.com"}})
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			filtered.Merge(map[int]indexUser{200 + i: {Email: fmt.Sprintf("m%d@x.com", i)}})
+			filtered.Put(300+i, indexUser{Email: "u0@x.com"})
+			_, _, _ = filtered.MergeWith(map[int]indexUser{400 + i: {Email: "u2@x.com"}}, map_collection.KeepRight[int, indexUser]())
+			_, _, _ = filtered.MergeCollectionWith(other, map_collection.KeepRight[int, indexUser]())
+			filtered.MergeCollection(filtered)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if res, err := filtered.LookupIndex("email", "u4@x.com"); err != nil || res.Count() != 1 {
+		t.Errorf("LookupIndex returned %v, %v", res, err)
+	}
+	if filtered.Count() != 50 {
+		t.Errorf("Non in-place writes should not modify the collection, got %d", filtered.Count())
+	}
+}
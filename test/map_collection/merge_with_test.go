@@ -0,0 +1,203 @@
+package map_collection
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+)
+
+func stringCompare(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+func TestMergeWithKeepLeft(t *testing.T) {
+	c := map_collection.NewCollection(map[string]int{"a": 1, "b": 2})
+	newC, report, err := c.MergeWith(map[string]int{"b": 20, "c": 30}, map_collection.KeepLeft[string, int]())
+	if err != nil {
+		t.Fatalf("MergeWith returned error: %v", err)
+	}
+
+	if newC.GetValue("b") != 2 || newC.GetValue("c") != 30 {
+		t.Errorf("KeepLeft merged incorrectly: %v", newC.All())
+	}
+	if !reflect.DeepEqual(report.Keys(), []string{"b"}) {
+		t.Errorf("Expected conflict keys [b], got %v", report.Keys())
+	}
+	if c.Has("c") {
+		t.Error("MergeWith should not modify original collection")
+	}
+}
+
+func TestMergeWithKeepRight(t *testing.T) {
+	c := map_collection.NewCollection(map[string]int{"a": 1, "b": 2})
+	newC, report, err := c.MergeWith(map[string]int{"b": 20}, map_collection.KeepRight[string, int]())
+	if err != nil {
+		t.Fatalf("MergeWith returned error: %v", err)
+	}
+
+	if newC.GetValue("b") != 20 {
+		t.Errorf("Expected b=20, got %d", newC.GetValue("b"))
+	}
+	conflict := report.Conflicts[0]
+	if conflict.Left != 2 || conflict.Right != 20 || conflict.Result != 20 {
+		t.Errorf("Unexpected conflict record: %+v", conflict)
+	}
+}
+
+func TestMergeWithSumValues(t *testing.T) {
+	c := map_collection.NewCollection(map[string]float64{"pv": 1.5, "uv": 2},
+		map_collection.WithKeyCompare[string, float64](stringCompare))
+	newC, report, err := c.MergeWith(map[string]float64{"uv": 3, "pv": 0.5, "clicks": 7}, map_collection.SumValues[string, float64]())
+	if err != nil {
+		t.Fatalf("MergeWith returned error: %v", err)
+	}
+
+	expected := map[string]float64{"pv": 2, "uv": 5, "clicks": 7}
+	if !reflect.DeepEqual(newC.All(), expected) {
+		t.Errorf("Expected %v, got %v", expected, newC.All())
+	}
+	// 冲突报告按结果的 key 顺序排列
+	if !reflect.DeepEqual(report.Keys(), []string{"pv", "uv"}) {
+		t.Errorf("Expected conflict keys [pv uv], got %v", report.Keys())
+	}
+}
+
+func TestMergeWithConcatValues(t *testing.T) {
+	c := map_collection.NewCollection(map[string][]string{"admin": {"read"}})
+	newC, _, err := c.MergeWith(map[string][]string{"admin": {"write"}}, map_collection.ConcatValues[string, string]())
+	if err != nil {
+		t.Fatalf("MergeWith returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(newC.GetValue("admin"), []string{"read", "write"}) {
+		t.Errorf("Expected [read write], got %v", newC.GetValue("admin"))
+	}
+}
+
+func TestMergeWithDeepMerge(t *testing.T) {
+	base := map[string]any{
+		"db": map[string]any{
+			"host": "localhost",
+			"pool": map[string]any{"max": 10, "min": 1},
+		},
+		"debug": false,
+	}
+	override := map[string]any{
+		"db": map[string]any{
+			"host": "db.prod",
+			"pool": map[string]any{"max": 50},
+		},
+		"debug": true,
+	}
+
+	c := map_collection.NewCollection(base)
+	newC, report, err := c.MergeWith(override, map_collection.DeepMerge[string, any]())
+	if err != nil {
+		t.Fatalf("MergeWith returned error: %v", err)
+	}
+
+	expected := map[string]any{
+		"db": map[string]any{
+			"host": "db.prod",
+			"pool": map[string]any{"max": 50, "min": 1},
+		},
+		"debug": true,
+	}
+	if !reflect.DeepEqual(newC.All(), expected) {
+		t.Errorf("Expected %v, got %v", expected, newC.All())
+	}
+	if len(report.Conflicts) != 2 {
+		t.Errorf("Expected 2 conflicts, got %d", len(report.Conflicts))
+	}
+	// 原始嵌套 map 不应被修改
+	if base["db"].(map[string]any)["host"] != "localhost" {
+		t.Error("DeepMerge should not modify nested maps of the original collection")
+	}
+}
+
+func TestMergeWithError(t *testing.T) {
+	c := map_collection.NewCollection(map[string]int{"a": 1})
+
+	_, _, err := c.MergeWith(map[string]int{"a": 2}, map_collection.RejectConflict[string, int]())
+	if !errors.Is(err, errorx.MergeConflictError) {
+		t.Errorf("Expected MergeConflictError, got %v", err)
+	}
+
+	_, _, err = c.MergeWith(map[string]int{"a": 2}, nil)
+	if !errors.Is(err, errorx.NilFunc) {
+		t.Errorf("Expected NilFunc error, got %v", err)
+	}
+}
+
+func TestMergeInPlaceWith(t *testing.T) {
+	c := map_collection.NewCollection(map[string]int{"a": 1, "b": 2})
+	_, report, err := c.MergeInPlaceWith(map[string]int{"a": 10, "c": 3}, map_collection.SumValues[string, int]())
+	if err != nil {
+		t.Fatalf("MergeInPlaceWith returned error: %v", err)
+	}
+	if c.GetValue("a") != 11 || c.GetValue("c") != 3 {
+		t.Errorf("MergeInPlaceWith merged incorrectly: %v", c.All())
+	}
+	if !report.HasConflicts() {
+		t.Error("Expected conflicts to be reported")
+	}
+
+	// 出错时原集合保持不变
+	_, _, err = c.MergeInPlaceWith(map[string]int{"b": 5, "d": 4}, map_collection.RejectConflict[string, int]())
+	if err == nil {
+		t.Error("Expected error from RejectConflict")
+	}
+	if c.Has("d") || c.GetValue("b") != 2 {
+		t.Errorf("MergeInPlaceWith should not modify collection on error: %v", c.All())
+	}
+}
+
+func TestMergeCollectionWith(t *testing.T) {
+	c1 := map_collection.NewCollection(map[string]int{"a": 1, "b": 2})
+	c2 := map_collection.NewCollection(map[string]int{"b": 3, "c": 4})
+
+	newC, report, err := c1.MergeCollectionWith(c2, map_collection.SumValues[string, int]())
+	if err != nil {
+		t.Fatalf("MergeCollectionWith returned error: %v", err)
+	}
+	if newC.GetValue("b") != 5 || newC.Count() != 3 {
+		t.Errorf("MergeCollectionWith merged incorrectly: %v", newC.All())
+	}
+	if !reflect.DeepEqual(report.Keys(), []string{"b"}) {
+		t.Errorf("Expected conflict keys [b], got %v", report.Keys())
+	}
+
+	newC, report, err = c1.MergeCollectionWith(nil, map_collection.SumValues[string, int]())
+	if err != nil || newC.Count() != 2 || report.HasConflicts() {
+		t.Error("MergeCollectionWith nil should return a copy without conflicts")
+	}
+}
+
+func TestSafeMergeWith(t *testing.T) {
+	sc := map_collection.NewSafeCollection(map[string]int{"a": 1})
+	newSc, report, err := sc.MergeWith(map[string]int{"a": 2}, map_collection.SumValues[string, int]())
+	if err != nil {
+		t.Fatalf("MergeWith returned error: %v", err)
+	}
+	if newSc.GetValue("a") != 3 || sc.GetValue("a") != 1 {
+		t.Error("SafeCollection MergeWith merged incorrectly")
+	}
+	if !report.HasConflicts() {
+		t.Error("Expected conflicts to be reported")
+	}
+
+	other := map_collection.NewSafeCollection(map[string]int{"a": 5})
+	merged, _, err := sc.MergeCollectionWith(other, map_collection.KeepLeft[string, int]())
+	if err != nil || merged.GetValue("a") != 1 {
+		t.Error("SafeCollection MergeCollectionWith merged incorrectly")
+	}
+
+	_, _, err = sc.MergeInPlaceWith(map[string]int{"a": 1}, map_collection.SumValues[string, int]())
+	if err != nil || sc.GetValue("a") != 2 {
+		t.Error("SafeCollection MergeInPlaceWith merged incorrectly")
+	}
+}
@@ -0,0 +1,129 @@
+package map_collection
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+type revenue struct {
+	Month  string
+	Region string
+	Amount float64
+}
+
+// TestPivotSum tests the Pivot function with the AggSum aggregator and its totals
+func TestPivotSum(t *testing.T) {
+	revenues := slice_collcection.NewCollection([]revenue{
+		{"2024-02", "west", 10},
+		{"2024-01", "east", 100},
+		{"2024-01", "west", 30},
+		{"2024-02", "east", 50},
+		{"2024-01", "east", 20},
+		{"2024-03", "north", 5},
+	})
+	table := map_collection.Pivot(revenues,
+		func(r revenue) string { return r.Month },
+		func(r revenue) string { return r.Region },
+		map_collection.AggSum(func(r revenue) float64 { return r.Amount }))
+
+	if !reflect.DeepEqual(table.Rows(), []string{"2024-02", "2024-01", "2024-03"}) {
+		t.Errorf("Unexpected row order %v", table.Rows())
+	}
+	if !reflect.DeepEqual(table.Columns(), []string{"west", "east", "north"}) {
+		t.Errorf("Unexpected column order %v", table.Columns())
+	}
+
+	if v, ok := table.Cell("2024-01", "east"); !ok || v != 120 {
+		t.Errorf("Expected 120, got %v %v", v, ok)
+	}
+	if _, ok := table.Cell("2024-03", "east"); ok {
+		t.Error("Empty cell should not exist")
+	}
+	if table.RowTotals().GetValue("2024-01") != 150 || table.ColumnTotals().GetValue("east") != 170 {
+		t.Errorf("Unexpected totals %v %v", table.RowTotals().All(), table.ColumnTotals().All())
+	}
+	if table.GrandTotal() != 215 {
+		t.Errorf("Expected grand total 215, got %v", table.GrandTotal())
+	}
+
+	// 嵌套 Collection 中的列顺序与透视表一致
+	row, _ := table.Table().Get("2024-01")
+	var columns []string
+	row.Foreach(func(_ float64, col string) { columns = append(columns, col) })
+	if !reflect.DeepEqual(columns, []string{"west", "east"}) {
+		t.Errorf("Unexpected row columns %v", columns)
+	}
+}
+
+// TestPivotSortAndUnpivot tests the SortRows, SortColumns and Unpivot methods of the PivotTable struct
+func TestPivotSortAndUnpivot(t *testing.T) {
+	revenues := slice_collcection.NewCollection([]revenue{
+		{"2024-02", "west", 10},
+		{"2024-01", "east", 100},
+		{"2024-01", "west", 30},
+		{"2024-02", "east", 50},
+		{"2024-01", "east", 20},
+		{"2024-03", "north", 5},
+	})
+	table := map_collection.Pivot(revenues,
+		func(r revenue) string { return r.Month },
+		func(r revenue) string { return r.Region },
+		map_collection.AggCount[revenue]())
+	if _, err := table.SortRows(stringCompare); err != nil {
+		t.Fatalf("SortRows returned error: %v", err)
+	}
+	if _, err := table.SortColumns(stringCompare); err != nil {
+		t.Fatalf("SortColumns returned error: %v", err)
+	}
+	if _, err := table.SortRows(nil); !errors.Is(err, errorx.NilFunc) {
+		t.Errorf("Expected NilFunc, got %v", err)
+	}
+
+	if !reflect.DeepEqual(table.Rows(), []string{"2024-01", "2024-02", "2024-03"}) {
+		t.Errorf("Unexpected row order %v", table.Rows())
+	}
+	if !reflect.DeepEqual(table.Columns(), []string{"east", "north", "west"}) {
+		t.Errorf("Unexpected column order %v", table.Columns())
+	}
+
+	cells := table.Unpivot().Values()
+	expected := []map_collection.PivotCell[string, string, int]{
+		{Row: "2024-01", Column: "east", Value: 2},
+		{Row: "2024-01", Column: "west", Value: 1},
+		{Row: "2024-02", Column: "east", Value: 1},
+		{Row: "2024-02", Column: "west", Value: 1},
+		{Row: "2024-03", Column: "north", Value: 1},
+	}
+	if !reflect.DeepEqual(cells, expected) {
+		t.Errorf("Expected %v, got %v", expected, cells)
+	}
+}
+
+// TestPivotAvgTotals tests that the totals re-aggregate all elements with the AggAvg aggregator
+func TestPivotAvgTotals(t *testing.T) {
+	revenues := slice_collcection.NewCollection([]revenue{
+		{"2024-02", "west", 10},
+		{"2024-01", "east", 100},
+		{"2024-01", "west", 30},
+		{"2024-02", "east", 50},
+		{"2024-01", "east", 20},
+		{"2024-03", "north", 5},
+	})
+	table := map_collection.Pivot(revenues,
+		func(r revenue) string { return r.Month },
+		func(r revenue) string { return r.Region },
+		map_collection.AggAvg(func(r revenue) float64 { return r.Amount }))
+
+	// 合计对全部元素重新聚合，而不是对单元格的平均值再求平均
+	if v := table.RowTotals().GetValue("2024-01"); v != 50 {
+		t.Errorf("Expected row average 50, got %v", v)
+	}
+	if v := table.ColumnTotals().GetValue("east"); v != 170.0/3 {
+		t.Errorf("Expected column average %v, got %v", 170.0/3, v)
+	}
+}
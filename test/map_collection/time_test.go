@@ -0,0 +1,201 @@
+package map_collection
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/map_collection"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+type event struct {
+	At    time.Time
+	Value int
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func eventTime(e event) time.Time { return e.At }
+
+func orderedTimeKeys[V any](c *map_collection.Collection[time.Time, V]) []string {
+	keys := make([]string, 0, c.Count())
+	c.Foreach(func(_ V, k time.Time) {
+		keys = append(keys, k.Format(time.RFC3339))
+	})
+	return keys
+}
+
+func TestTimeIntervalTruncate(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	ts := at("2024-03-06T17:47:30Z") // 周三，上海时间 2024-03-07 01:47:30（周四）
+
+	cases := []struct {
+		name     string
+		interval map_collection.TimeInterval
+		loc      *time.Location
+		expected string
+	}{
+		{"minute", map_collection.Minute, nil, "2024-03-06T17:47:00Z"},
+		{"15 minutes", map_collection.Minute.Every(15), nil, "2024-03-06T17:45:00Z"},
+		{"hour", map_collection.Hour, nil, "2024-03-06T17:00:00Z"},
+		{"6 hours", map_collection.Hour.Every(6), nil, "2024-03-06T12:00:00Z"},
+		{"day", map_collection.Day, nil, "2024-03-06T00:00:00Z"},
+		{"day in loc", map_collection.Day, shanghai, "2024-03-07T00:00:00+08:00"},
+		{"iso week", map_collection.ISOWeek, nil, "2024-03-04T00:00:00Z"},
+		{"month", map_collection.Month, nil, "2024-03-01T00:00:00Z"},
+		{"quarter", map_collection.Month.Every(3), nil, "2024-01-01T00:00:00Z"},
+	}
+	for _, tc := range cases {
+		got := tc.interval.Truncate(ts, tc.loc).Format(time.RFC3339)
+		if got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, got)
+		}
+	}
+
+	// ISO 周跨年：2021-01-01 是周五，属于 2020-12-28 开始的一周
+	if got := map_collection.ISOWeek.Truncate(at("2021-01-01T10:00:00Z"), nil); !got.Equal(at("2020-12-28T00:00:00Z")) {
+		t.Errorf("Unexpected ISO week start %v", got)
+	}
+	// 不能整除一天的分钟间隔在次日重新对齐
+	if got := map_collection.Minute.Every(7).Next(at("2024-03-06T23:55:00Z"), nil); !got.Equal(at("2024-03-07T00:00:00Z")) {
+		t.Errorf("Unexpected next bucket %v", got)
+	}
+}
+
+func TestGroupByTime(t *testing.T) {
+	events := slice_collcection.NewCollection([]event{
+		{at("2024-03-01T10:20:00Z"), 1},
+		{at("2024-03-01T08:05:00Z"), 2},
+		{at("2024-03-01T10:59:59Z"), 3},
+		{at("2024-03-01T07:00:00Z"), 4},
+	})
+
+	groups, err := map_collection.GroupByTime(events, eventTime, map_collection.Hour, nil)
+	if err != nil {
+		t.Fatalf("GroupByTime returned error: %v", err)
+	}
+	expected := []string{"2024-03-01T07:00:00Z", "2024-03-01T08:00:00Z", "2024-03-01T10:00:00Z"}
+	if got := orderedTimeKeys(groups); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if bucket := groups.GetValue(at("2024-03-01T10:00:00Z")); bucket.Count() != 2 || bucket.First().Value != 1 {
+		t.Errorf("Unexpected bucket %v", bucket.Values())
+	}
+
+	filled, err := map_collection.GroupByTime(events, eventTime, map_collection.Hour, nil, map_collection.WithFillGaps())
+	if err != nil {
+		t.Fatalf("GroupByTime returned error: %v", err)
+	}
+	expected = []string{"2024-03-01T07:00:00Z", "2024-03-01T08:00:00Z", "2024-03-01T09:00:00Z", "2024-03-01T10:00:00Z"}
+	if got := orderedTimeKeys(filled); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if !filled.GetValue(at("2024-03-01T09:00:00Z")).IsEmpty() {
+		t.Error("Filled bucket should be empty")
+	}
+}
+
+func TestResample(t *testing.T) {
+	events := slice_collcection.NewCollection([]event{
+		{at("2024-01-30T10:00:00Z"), 5},
+		{at("2024-03-15T10:00:00Z"), 7},
+		{at("2024-01-02T10:00:00Z"), 1},
+		{at("2023-12-31T23:00:00Z"), 100},
+	})
+
+	monthly, err := map_collection.Resample(events, eventTime, map_collection.Month, nil,
+		map_collection.AggSum(func(e event) int { return e.Value }),
+		map_collection.WithTimeRange(at("2024-01-01T00:00:00Z"), at("2024-04-30T00:00:00Z")))
+	if err != nil {
+		t.Fatalf("Resample returned error: %v", err)
+	}
+
+	expected := []string{"2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z", "2024-03-01T00:00:00Z", "2024-04-01T00:00:00Z"}
+	if got := orderedTimeKeys(monthly); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	values := make([]int, 0)
+	monthly.Foreach(func(v int, _ time.Time) { values = append(values, v) })
+	if !reflect.DeepEqual(values, []int{6, 0, 7, 0}) {
+		t.Errorf("Unexpected resampled values %v", values)
+	}
+}
+
+func TestTimeBucketsAcrossDSTFallBack(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// 2024-11-03 01:00-02:00 在纽约出现两次（先 EDT 后 EST）
+	events := slice_collcection.NewCollection([]event{
+		{at("2024-11-03T05:30:00Z"), 1}, // 01:30 EDT
+		{at("2024-11-03T06:30:00Z"), 2}, // 01:30 EST
+		{at("2024-11-03T07:10:00Z"), 3}, // 02:10 EST
+	})
+
+	groups, err := map_collection.GroupByTime(events, eventTime, map_collection.Hour, loc, map_collection.WithFillGaps())
+	if err != nil {
+		t.Fatalf("GroupByTime returned error: %v", err)
+	}
+	expected := []string{"2024-11-03T01:00:00-04:00", "2024-11-03T01:00:00-05:00", "2024-11-03T02:00:00-05:00"}
+	if got := orderedTimeKeys(groups); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Repeated hours should be separate buckets, expected %v, got %v", expected, got)
+	}
+
+	quarter, _ := map_collection.GroupByTime(events, eventTime, map_collection.Minute.Every(15), loc)
+	if quarter.Count() != 3 {
+		t.Errorf("Expected 3 quarter-hour buckets, got %d", quarter.Count())
+	}
+}
+
+func TestTimeBucketsLimit(t *testing.T) {
+	events := slice_collcection.NewCollection([]event{{at("2024-01-01T00:00:00Z"), 1}})
+	_, err := map_collection.GroupByTime(events, eventTime, map_collection.Minute, nil,
+		map_collection.WithTimeRange(at("2020-01-01T00:00:00Z"), at("2024-01-01T00:00:00Z")))
+	if !errors.Is(err, errorx.TooLargeError) {
+		t.Errorf("Expected TooLargeError, got %v", err)
+	}
+	if _, err := map_collection.Resample(events, eventTime, map_collection.Day, nil,
+		map_collection.AggCount[event](),
+		map_collection.WithTimeRange(at("2020-01-01T00:00:00Z"), at("2024-01-01T00:00:00Z"))); err != nil {
+		t.Errorf("Daily buckets over four years should be allowed, got %v", err)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	start := at("2024-01-01T00:00:00Z")
+	points := make([]event, 0)
+	for i := 0; i < 100; i++ {
+		value := 0
+		if i == 37 {
+			value = 50 // 峰值需要被保留
+		}
+		points = append(points, event{start.Add(time.Duration(i) * time.Minute), value})
+	}
+	series := slice_collcection.NewCollection(points)
+
+	sampled := map_collection.Downsample(series, eventTime, func(e event) int { return e.Value }, 10)
+	if sampled.Count() != 10 {
+		t.Fatalf("Expected 10 points, got %d", sampled.Count())
+	}
+	keys := orderedTimeKeys(sampled)
+	if keys[0] != "2024-01-01T00:00:00Z" || keys[9] != "2024-01-01T01:39:00Z" {
+		t.Errorf("Downsample should keep the first and last points: %v", keys)
+	}
+	if sampled.GetValue(start.Add(37*time.Minute)) != 50 {
+		t.Errorf("Downsample should keep the peak: %v", keys)
+	}
+
+	if all := map_collection.Downsample(series, eventTime, func(e event) int { return e.Value }, 200); all.Count() != 100 {
+		t.Errorf("Expected all points when below threshold, got %d", all.Count())
+	}
+}
@@ -0,0 +1,162 @@
+package map_collection
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/map_collection"
+)
+
+func TestTrieBasic(t *testing.T) {
+	trie := map_collection.NewTrie(map[string]int{"tea": 1, "ten": 2, "to": 3, "inn": 4})
+	trie.Set("in", 5).Set("tea", 10)
+
+	if trie.Count() != 5 {
+		t.Errorf("Expected 5 keys, got %d", trie.Count())
+	}
+	if v, ok := trie.Get("tea"); !ok || v != 10 {
+		t.Errorf("Unexpected Get result %d %v", v, ok)
+	}
+	if _, ok := trie.Get("te"); ok || trie.Has("t") || trie.GetValue("zzz") != 0 {
+		t.Error("Intermediate nodes should not be reported as keys")
+	}
+	if !reflect.DeepEqual(trie.Keys(), []string{"in", "inn", "tea", "ten", "to"}) {
+		t.Errorf("Unexpected keys %v", trie.Keys())
+	}
+	if !reflect.DeepEqual(trie.Values(), []int{5, 4, 10, 2, 3}) {
+		t.Errorf("Unexpected values %v", trie.Values())
+	}
+
+	trie.Remove("in").Remove("missing")
+	if trie.Has("in") || !trie.Has("inn") || trie.Count() != 4 {
+		t.Errorf("Unexpected state after Remove: %v", trie.Keys())
+	}
+
+	even := trie.Filter(func(value int, key string) bool { return value%2 == 0 })
+	if !reflect.DeepEqual(even.Keys(), []string{"inn", "tea", "ten"}) {
+		t.Errorf("Unexpected filter result %v", even.Keys())
+	}
+
+	data, err := trie.ToJSON()
+	if err != nil || data != `{"inn":4,"tea":10,"ten":2,"to":3}` {
+		t.Errorf("Unexpected json %s (%v)", data, err)
+	}
+
+	keys := make([]string, 0)
+	trie.ToCollection().Foreach(func(value int, key string) {
+		keys = append(keys, key)
+	})
+	if !reflect.DeepEqual(keys, []string{"inn", "tea", "ten", "to"}) {
+		t.Errorf("ToCollection should keep lexical order, got %v", keys)
+	}
+}
+
+func TestTriePrefixQueries(t *testing.T) {
+	trie := map_collection.NewTrieFrom(map_collection.NewCollection(map[string]string{
+		"/":              "root",
+		"/api":           "api",
+		"/api/users":     "users",
+		"/api/users/me":  "me",
+		"/api/orders":    "orders",
+		"/static/app.js": "js",
+	}))
+
+	if got := trie.KeysWithPrefix("/api/"); !reflect.DeepEqual(got, []string{"/api/orders", "/api/users", "/api/users/me"}) {
+		t.Errorf("Unexpected prefix keys %v", got)
+	}
+	if got := trie.KeysWithPrefix("/nope"); len(got) != 0 || trie.CountPrefix("/api") != 4 {
+		t.Errorf("Unexpected prefix result %v", got)
+	}
+
+	cases := map[string]string{
+		"/api/users/42":   "/api/users",
+		"/api/users/me":   "/api/users/me",
+		"/apix":           "/api",
+		"/static/app.css": "/",
+	}
+	for path, expected := range cases {
+		if key, _, ok := trie.LongestPrefixOf(path); !ok || key != expected {
+			t.Errorf("LongestPrefixOf(%q): expected %q, got %q (%v)", path, expected, key, ok)
+		}
+	}
+	if _, _, ok := trie.LongestPrefixOf("api"); ok {
+		t.Error("Expected no prefix match")
+	}
+
+	first := make([]string, 0)
+	trie.WalkPrefix("/api", func(value string, key string) bool {
+		first = append(first, value)
+		return len(first) < 2
+	})
+	if !reflect.DeepEqual(first, []string{"api", "orders"}) {
+		t.Errorf("Unexpected walk result %v", first)
+	}
+
+	if n := trie.DeletePrefix("/api/users"); n != 2 {
+		t.Errorf("Expected 2 deleted keys, got %d", n)
+	}
+	if !reflect.DeepEqual(trie.Keys(), []string{"/", "/api", "/api/orders", "/static/app.js"}) || trie.Count() != 4 {
+		t.Errorf("Unexpected keys after DeletePrefix %v", trie.Keys())
+	}
+	if n := trie.DeletePrefix("/zzz"); n != 0 {
+		t.Errorf("Expected nothing deleted, got %d", n)
+	}
+	if n := trie.DeletePrefix(""); n != 4 || !trie.IsEmpty() {
+		t.Errorf("DeletePrefix(\"\") should clear the trie, deleted %d", n)
+	}
+}
+
+func TestTrieRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	trie := map_collection.NewTrie[map[string]int](nil)
+	model := map[string]int{}
+	randKey := func() string {
+		b := make([]byte, r.Intn(5))
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+		return string(b)
+	}
+
+	for step := 0; step < 3000; step++ {
+		key := randKey()
+		switch r.Intn(4) {
+		case 0, 1:
+			trie.Set(key, step)
+			model[key] = step
+		case 2:
+			trie.Remove(key)
+			delete(model, key)
+		default:
+			removed := trie.DeletePrefix(key)
+			expected := 0
+			for k := range model {
+				if strings.HasPrefix(k, key) {
+					delete(model, k)
+					expected++
+				}
+			}
+			if removed != expected {
+				t.Fatalf("DeletePrefix(%q) at step %d: expected %d, got %d", key, step, expected, removed)
+			}
+		}
+
+		prefix := randKey()
+		expected := make([]string, 0)
+		for k := range model {
+			if strings.HasPrefix(k, prefix) {
+				expected = append(expected, k)
+			}
+		}
+		sort.Strings(expected)
+		if got := trie.KeysWithPrefix(prefix); !reflect.DeepEqual(got, expected) || trie.CountPrefix(prefix) != len(expected) {
+			t.Fatalf("KeysWithPrefix(%q) at step %d: expected %v, got %v", prefix, step, expected, got)
+		}
+	}
+	if !reflect.DeepEqual(trie.All(), model) || trie.Count() != len(model) {
+		t.Error("Trie diverged from model")
+	}
+}
@@ -0,0 +1,3 @@
+// Package tree 将带父 ID 的扁平记录（如数据库中的组织架构、分类菜单、评论）构建为森林，
+// 报告孤儿节点与环，并提供带深度与路径的遍历、先序/后序展开、祖先/后代查询与子树聚合。
+package tree
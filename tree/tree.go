@@ -0,0 +1,356 @@
+package tree
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+// Node 树中的节点
+type Node[K comparable, T any] struct {
+	ID       K             `json:"id"`
+	Item     T             `json:"item"`
+	Depth    int           `json:"depth"` // 根节点为 0
+	Parent   *Node[K, T]   `json:"-"`
+	Children []*Node[K, T] `json:"children,omitempty"`
+	parentID K
+}
+
+// IsRoot 是否为根节点（包括孤儿节点）
+func (n *Node[K, T]) IsRoot() bool {
+	return n.Parent == nil
+}
+
+// IsLeaf 是否为叶子节点
+func (n *Node[K, T]) IsLeaf() bool {
+	return len(n.Children) == 0
+}
+
+// Forest 由扁平记录构建的森林
+// 父 ID 为根标记（默认为 K 的零值）或等于自身 ID 的记录为根节点；父 ID 不存在的记录为孤儿，
+// 孤儿作为子树的根记录在 Orphans 中，不在 Roots 中；环上的节点及其后代无法挂到任何根下，记录在 Cycles 与 Detached 中
+type Forest[K comparable, T any] struct {
+	Roots      []*Node[K, T] // 根节点，按输入顺序排列
+	Orphans    []*Node[K, T] // 父节点不存在的节点，按输入顺序排列，其子树完整保留
+	Cycles     [][]K         // 每个环上的节点 ID，按父子关系排列（后一个是前一个的父节点）
+	Detached   []K           // 因环而无法挂到树上的节点（环上的节点及其后代），按输入顺序排列
+	Duplicates []K           // 重复出现的 ID，只保留第一条记录
+	nodes      map[K]*Node[K, T]
+}
+
+// Option 构建选项
+type Option[K comparable, T any] func(*options[K, T])
+
+type options[K comparable, T any] struct {
+	rootParents []K
+	less        func(a, b T) bool
+}
+
+// WithRootParent 设置表示“没有父节点”的父 ID，默认为 K 的零值（如 0、""）
+func WithRootParent[K comparable, T any](ids ...K) Option[K, T] {
+	return func(o *options[K, T]) {
+		o.rootParents = ids
+	}
+}
+
+// WithChildOrder 按 less 对兄弟节点稳定排序，未设置时按输入顺序排列
+func WithChildOrder[K comparable, T any](less func(a, b T) bool) Option[K, T] {
+	return func(o *options[K, T]) {
+		o.less = less
+	}
+}
+
+// BuildTree 使用 idFn 与 parentIdFn 将 Collection 中的扁平记录构建为森林，不修改原 Collection
+//
+// 使用示例：
+//
+//	forest := tree.BuildTree(categories, func(c Category) int { return c.ID }, func(c Category) int { return c.ParentID })
+//	forest.Walk(func(n *tree.Node[int, Category], path []int) bool {
+//	    fmt.Println(strings.Repeat("  ", n.Depth), n.Item.Name)
+//	    return true
+//	})
+func BuildTree[K comparable, T any](c *slice_collcection.Collection[T], idFn func(item T) K, parentIdFn func(item T) K, opts ...Option[K, T]) *Forest[K, T] {
+	o := options[K, T]{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rootParents == nil {
+		var zero K
+		o.rootParents = []K{zero}
+	}
+	isRootParent := func(id K) bool {
+		for _, r := range o.rootParents {
+			if r == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	f := &Forest[K, T]{nodes: make(map[K]*Node[K, T])}
+	var items []T
+	if c != nil {
+		items = c.Values()
+	}
+	order := make([]*Node[K, T], 0, len(items))
+	for _, item := range items {
+		id := idFn(item)
+		if _, ok := f.nodes[id]; ok {
+			f.Duplicates = append(f.Duplicates, id)
+			continue
+		}
+		n := &Node[K, T]{ID: id, Item: item, parentID: parentIdFn(item)}
+		f.nodes[id] = n
+		order = append(order, n)
+	}
+
+	for _, n := range order {
+		switch parent, ok := f.nodes[n.parentID]; {
+		case isRootParent(n.parentID) || n.parentID == n.ID:
+			f.Roots = append(f.Roots, n)
+		case !ok:
+			f.Orphans = append(f.Orphans, n)
+		default:
+			parent.Children = append(parent.Children, n)
+		}
+	}
+
+	// 从根与孤儿出发设置 Parent 与 Depth，未访问到的节点都处于环上或挂在环上
+	attached := make(map[K]bool, len(order))
+	for _, roots := range [][]*Node[K, T]{f.Roots, f.Orphans} {
+		for _, root := range roots {
+			attach(root, nil, attached, o.less)
+		}
+	}
+	if len(attached) < len(order) {
+		f.detach(order, attached)
+	}
+	if o.less != nil {
+		sortNodes(f.Roots, o.less)
+		sortNodes(f.Orphans, o.less)
+	}
+
+	return f
+}
+
+// attach 先序设置子树的 Parent 与 Depth，并按需排序子节点
+func attach[K comparable, T any](n, parent *Node[K, T], attached map[K]bool, less func(a, b T) bool) {
+	stack := []*Node[K, T]{n}
+	n.Parent = parent
+	if parent != nil {
+		n.Depth = parent.Depth + 1
+	}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		attached[cur.ID] = true
+		if less != nil {
+			sortNodes(cur.Children, less)
+		}
+		for _, child := range cur.Children {
+			child.Parent = cur
+			child.Depth = cur.Depth + 1
+			stack = append(stack, child)
+		}
+	}
+}
+
+// detach 找出未挂到树上的节点中的环，并断开这些节点之间的父子引用，避免遍历时死循环
+func (f *Forest[K, T]) detach(order []*Node[K, T], attached map[K]bool) {
+	state := make(map[K]int, len(order)) // 0 未处理，1 处理中，2 已处理
+	for _, n := range order {
+		if attached[n.ID] || state[n.ID] != 0 {
+			continue
+		}
+		// 沿父 ID 上溯，遇到处理中的节点即找到一个新的环
+		path := make([]*Node[K, T], 0)
+		cur := n
+		for state[cur.ID] == 0 {
+			state[cur.ID] = 1
+			path = append(path, cur)
+			cur = f.nodes[cur.parentID]
+		}
+		if state[cur.ID] == 1 {
+			for i, p := range path {
+				if p == cur {
+					cycle := make([]K, 0, len(path)-i)
+					for _, c := range path[i:] {
+						cycle = append(cycle, c.ID)
+					}
+					f.Cycles = append(f.Cycles, cycle)
+					break
+				}
+			}
+		}
+		for _, p := range path {
+			state[p.ID] = 2
+		}
+	}
+
+	for _, n := range order {
+		if !attached[n.ID] {
+			f.Detached = append(f.Detached, n.ID)
+			n.Children, n.Parent, n.Depth = nil, nil, 0
+			delete(f.nodes, n.ID)
+		}
+	}
+}
+
+// sortNodes 按 less 稳定排序
+func sortNodes[K comparable, T any](nodes []*Node[K, T], less func(a, b T) bool) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return less(nodes[i].Item, nodes[j].Item)
+	})
+}
+
+// HasProblems 是否存在孤儿、环或重复 ID
+func (f *Forest[K, T]) HasProblems() bool {
+	return len(f.Orphans) > 0 || len(f.Cycles) > 0 || len(f.Duplicates) > 0
+}
+
+// Count 树中的节点数量（包括孤儿子树，不包括 Detached 的节点）
+func (f *Forest[K, T]) Count() int {
+	return len(f.nodes)
+}
+
+// Get 返回 ID 对应的节点，Detached 的节点不可获取
+func (f *Forest[K, T]) Get(id K) (*Node[K, T], bool) {
+	n, ok := f.nodes[id]
+	return n, ok
+}
+
+// Walk 先序遍历 Roots 下的所有节点（不含孤儿子树），fn 接收节点与从根到该节点的 ID 路径（含自身），返回 false 时终止
+// path 在遍历过程中会被复用，需要保存时请复制
+func (f *Forest[K, T]) Walk(fn func(n *Node[K, T], path []K) bool) {
+	path := make([]K, 0)
+	for _, root := range f.Roots {
+		if !walk(root, path, fn) {
+			return
+		}
+	}
+}
+
+// WalkFrom 先序遍历 id 对应的子树，path 从该节点的根开始；id 不存在时返回 errorx.NotFoundError
+func (f *Forest[K, T]) WalkFrom(id K, fn func(n *Node[K, T], path []K) bool) error {
+	n, err := f.lookup(id)
+	if err != nil {
+		return err
+	}
+	path := make([]K, 0, n.Depth+1)
+	for p := n.Parent; p != nil; p = p.Parent {
+		path = append(path, p.ID)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	walk(n, path, fn)
+	return nil
+}
+
+func walk[K comparable, T any](n *Node[K, T], path []K, fn func(n *Node[K, T], path []K) bool) bool {
+	path = append(path, n.ID)
+	if !fn(n, path) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !walk(child, path, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Order 展开顺序
+type Order int
+
+const (
+	// PreOrder 先序：父节点在子节点之前
+	PreOrder Order = iota
+	// PostOrder 后序：子节点在父节点之前
+	PostOrder
+)
+
+// Flatten 按先序或后序将 Roots 下的所有节点展开为记录（不含孤儿子树）
+func (f *Forest[K, T]) Flatten(order Order) *slice_collcection.Collection[T] {
+	res := make([]T, 0, len(f.nodes))
+	for _, root := range f.Roots {
+		res = flatten(root, order, res)
+	}
+	return slice_collcection.NewCollection(res)
+}
+
+func flatten[K comparable, T any](n *Node[K, T], order Order, res []T) []T {
+	if order == PreOrder {
+		res = append(res, n.Item)
+	}
+	for _, child := range n.Children {
+		res = flatten(child, order, res)
+	}
+	if order == PostOrder {
+		res = append(res, n.Item)
+	}
+	return res
+}
+
+// Ancestors 返回 id 的所有祖先记录，从父节点到根节点排列；id 不存在时返回 errorx.NotFoundError
+func (f *Forest[K, T]) Ancestors(id K) (*slice_collcection.Collection[T], error) {
+	n, err := f.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]T, 0, n.Depth)
+	for p := n.Parent; p != nil; p = p.Parent {
+		res = append(res, p.Item)
+	}
+	return slice_collcection.NewCollection(res), nil
+}
+
+// Descendants 按先序返回 id 的所有后代记录（不含自身）；id 不存在时返回 errorx.NotFoundError
+func (f *Forest[K, T]) Descendants(id K) (*slice_collcection.Collection[T], error) {
+	n, err := f.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]T, 0)
+	for _, child := range n.Children {
+		res = flatten(child, PreOrder, res)
+	}
+	return slice_collcection.NewCollection(res), nil
+}
+
+// Aggregate 自底向上聚合每个子树：节点的结果为 init(节点记录) 依次与各子节点的结果 merge，
+// 返回所有节点（包括孤儿子树）的 ID 到聚合结果的映射
+//
+// 使用示例：
+//
+//	totals := tree.Aggregate(forest, func(d Dept) float64 { return d.Budget }, func(acc, child float64) float64 {
+//	    return acc + child
+//	})
+func Aggregate[K comparable, T any, R any](f *Forest[K, T], init func(item T) R, merge func(acc R, child R) R) map[K]R {
+	res := make(map[K]R, len(f.nodes))
+	var visit func(n *Node[K, T]) R
+	visit = func(n *Node[K, T]) R {
+		acc := init(n.Item)
+		for _, child := range n.Children {
+			acc = merge(acc, visit(child))
+		}
+		res[n.ID] = acc
+		return acc
+	}
+	for _, roots := range [][]*Node[K, T]{f.Roots, f.Orphans} {
+		for _, root := range roots {
+			visit(root)
+		}
+	}
+	return res
+}
+
+func (f *Forest[K, T]) lookup(id K) (*Node[K, T], error) {
+	n, ok := f.nodes[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: node %v", errorx.NotFoundError, id)
+	}
+	return n, nil
+}
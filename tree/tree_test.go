@@ -0,0 +1,157 @@
+package tree
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+	"github.com/ZHOUXING1997/collection/slice_collcection"
+)
+
+type dept struct {
+	ID       int
+	ParentID int
+	Name     string
+	Budget   float64
+}
+
+func buildDepts(opts ...Option[int, dept]) *Forest[int, dept] {
+	c := slice_collcection.NewCollection([]dept{
+		{ID: 1, Name: "HQ", Budget: 10},
+		{ID: 3, ParentID: 1, Name: "Sales", Budget: 5},
+		{ID: 2, ParentID: 1, Name: "Engineering", Budget: 20},
+		{ID: 4, ParentID: 2, Name: "Backend", Budget: 8},
+		{ID: 5, ParentID: 2, Name: "Frontend", Budget: 6},
+		{ID: 6, ParentID: 99, Name: "Lost", Budget: 1},
+		{ID: 7, ParentID: 6, Name: "Lost child", Budget: 1},
+		{ID: 8, ParentID: 9, Name: "Loop A"},
+		{ID: 9, ParentID: 8, Name: "Loop B"},
+		{ID: 10, ParentID: 9, Name: "Hanging"},
+		{ID: 11, Name: "Subsidiary", Budget: 3},
+		{ID: 4, ParentID: 1, Name: "Duplicate"},
+	})
+	return BuildTree(c, func(d dept) int { return d.ID }, func(d dept) int { return d.ParentID }, opts...)
+}
+
+func names(c *slice_collcection.Collection[dept]) []string {
+	res := make([]string, 0, c.Count())
+	c.Foreach(func(item dept, key int) {
+		res = append(res, item.Name)
+	})
+	return res
+}
+
+func TestBuildTree(t *testing.T) {
+	f := buildDepts()
+
+	if len(f.Roots) != 2 || f.Roots[0].ID != 1 || f.Roots[1].ID != 11 {
+		t.Fatalf("Unexpected roots %+v", f.Roots)
+	}
+	if len(f.Orphans) != 1 || f.Orphans[0].ID != 6 || f.Orphans[0].Children[0].ID != 7 {
+		t.Errorf("Unexpected orphans %+v", f.Orphans)
+	}
+	if !reflect.DeepEqual(f.Cycles, [][]int{{8, 9}}) || !reflect.DeepEqual(f.Detached, []int{8, 9, 10}) {
+		t.Errorf("Unexpected cycles %v / detached %v", f.Cycles, f.Detached)
+	}
+	if !reflect.DeepEqual(f.Duplicates, []int{4}) || !f.HasProblems() {
+		t.Errorf("Unexpected duplicates %v", f.Duplicates)
+	}
+	if f.Count() != 8 {
+		t.Errorf("Expected 8 attached nodes, got %d", f.Count())
+	}
+	if _, ok := f.Get(8); ok {
+		t.Error("Detached nodes should not be accessible")
+	}
+
+	backend, ok := f.Get(4)
+	if !ok || backend.Item.Name != "Backend" || backend.Depth != 2 || backend.Parent.ID != 2 || !backend.IsLeaf() || backend.IsRoot() {
+		t.Errorf("Unexpected node %+v", backend)
+	}
+
+	// 子节点保持输入顺序
+	if got := names(f.Flatten(PreOrder)); !reflect.DeepEqual(got, []string{"HQ", "Sales", "Engineering", "Backend", "Frontend", "Subsidiary"}) {
+		t.Errorf("Unexpected pre-order %v", got)
+	}
+	if got := names(f.Flatten(PostOrder)); !reflect.DeepEqual(got, []string{"Sales", "Backend", "Frontend", "Engineering", "HQ", "Subsidiary"}) {
+		t.Errorf("Unexpected post-order %v", got)
+	}
+
+	sorted := buildDepts(WithChildOrder[int](func(a, b dept) bool { return a.Name < b.Name }))
+	if got := names(sorted.Flatten(PreOrder)); !reflect.DeepEqual(got, []string{"HQ", "Engineering", "Backend", "Frontend", "Sales", "Subsidiary"}) {
+		t.Errorf("Unexpected sorted pre-order %v", got)
+	}
+}
+
+func TestWalkAndQueries(t *testing.T) {
+	f := buildDepts()
+
+	paths := make([][]int, 0)
+	f.Walk(func(n *Node[int, dept], path []int) bool {
+		paths = append(paths, append([]int(nil), path...))
+		return n.ID != 4
+	})
+	if !reflect.DeepEqual(paths, [][]int{{1}, {1, 3}, {1, 2}, {1, 2, 4}}) {
+		t.Errorf("Unexpected walk paths %v", paths)
+	}
+
+	paths = paths[:0]
+	if err := f.WalkFrom(2, func(n *Node[int, dept], path []int) bool {
+		paths = append(paths, append([]int(nil), path...))
+		return true
+	}); err != nil {
+		t.Fatalf("WalkFrom returned error: %v", err)
+	}
+	if !reflect.DeepEqual(paths, [][]int{{1, 2}, {1, 2, 4}, {1, 2, 5}}) {
+		t.Errorf("Unexpected subtree paths %v", paths)
+	}
+
+	ancestors, err := f.Ancestors(5)
+	if err != nil || !reflect.DeepEqual(names(ancestors), []string{"Engineering", "HQ"}) {
+		t.Errorf("Unexpected ancestors %v (%v)", names(ancestors), err)
+	}
+	descendants, err := f.Descendants(1)
+	if err != nil || !reflect.DeepEqual(names(descendants), []string{"Sales", "Engineering", "Backend", "Frontend"}) {
+		t.Errorf("Unexpected descendants %v (%v)", names(descendants), err)
+	}
+	if _, err := f.Descendants(8); !errors.Is(err, errorx.NotFoundError) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+}
+
+func TestAggregateAndJSON(t *testing.T) {
+	f := buildDepts()
+	totals := Aggregate(f, func(d dept) float64 { return d.Budget }, func(acc, child float64) float64 {
+		return acc + child
+	})
+	expected := map[int]float64{1: 49, 2: 34, 3: 5, 4: 8, 5: 6, 6: 2, 7: 1, 11: 3}
+	if !reflect.DeepEqual(totals, expected) {
+		t.Errorf("Expected %v, got %v", expected, totals)
+	}
+
+	heights := Aggregate(f, func(d dept) int { return 0 }, func(acc, child int) int { return max(acc, child+1) })
+	if heights[1] != 2 || heights[4] != 0 {
+		t.Errorf("Unexpected heights %v", heights)
+	}
+
+	type menu struct {
+		ID     string
+		Parent string
+	}
+	items := slice_collcection.NewCollection([]menu{{"home", "-"}, {"docs", "home"}, {"api", "docs"}})
+	menus := BuildTree(items, func(m menu) string { return m.ID }, func(m menu) string { return m.Parent }, WithRootParent[string, menu]("-"))
+	data, err := json.Marshal(menus.Roots)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	want := `[{"id":"home","item":{"ID":"home","Parent":"-"},"depth":0,"children":[{"id":"docs","item":{"ID":"docs","Parent":"home"},"depth":1,"children":[{"id":"api","item":{"ID":"api","Parent":"docs"},"depth":2}]}]}]`
+	if string(data) != want {
+		t.Errorf("Unexpected json %s", data)
+	}
+
+	self := BuildTree(slice_collcection.NewCollection([]menu{{"a", "a"}}), func(m menu) string { return m.ID }, func(m menu) string { return m.Parent })
+	if len(self.Roots) != 1 || self.HasProblems() {
+		t.Errorf("A record that is its own parent should be a root")
+	}
+}
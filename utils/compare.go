@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ZHOUXING1997/collection/errorx"
+)
+
+// CompareAny 比较两个动态类型的值, -1 小于，0 等于，1 大于
+// 支持数值（不同数值类型之间按数值比较）、字符串、bool 与 time.Time，其余类型返回 errorx.NoComparableError
+func CompareAny(a, b any) (int, error) {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb), nil
+		}
+		return 0, errorx.NoComparableError
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return 0, errorx.NoComparableError
+	}
+
+	switch {
+	case va.Kind() == reflect.String && vb.Kind() == reflect.String:
+		return strings.Compare(va.String(), vb.String()), nil
+	case va.Kind() == reflect.Bool && vb.Kind() == reflect.Bool:
+		return compareOrdered(boolToInt(va.Bool()), boolToInt(vb.Bool())), nil
+	case va.CanInt() && vb.CanInt():
+		return compareOrdered(va.Int(), vb.Int()), nil
+	case va.CanUint() && vb.CanUint():
+		return compareOrdered(va.Uint(), vb.Uint()), nil
+	case IsComputableKind(va.Kind()) && IsComputableKind(vb.Kind()):
+		return compareOrdered(toFloat64(va), toFloat64(vb)), nil
+	default:
+		return 0, errorx.NoComparableError
+	}
+}
+
+// compareOrdered 比较两个有序值
+func compareOrdered[T int | int64 | uint64 | float64](a, b T) int {
+	if a > b {
+		return 1
+	} else if a < b {
+		return -1
+	}
+	return 0
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// toFloat64 将数值类型的 reflect.Value 转换为 float64
+func toFloat64(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
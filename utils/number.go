@@ -0,0 +1,8 @@
+package utils
+
+// Number 可进行算术运算的数值类型约束
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
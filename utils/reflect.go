@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // IsComputableKind 检查类型是否可计算
@@ -52,7 +54,42 @@ func Any2Float(val interface{}) (float64, error) {
 	}
 }
 
+// NewCompareFuncOf 根据类型创建比较函数, -1 小于，0 等于，1 大于
+// 在 NewCompareFunc 的基础上支持字符串、time.Time（按时间先后比较）以及底层为数值或字符串的命名类型（如 type ID int），
+// typ 为 nil 或不支持的类型时返回 nil
+func NewCompareFuncOf(typ reflect.Type) func(any, any) int {
+	if typ == nil {
+		return nil
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return func(a, b any) int {
+			return a.(time.Time).Compare(b.(time.Time))
+		}
+	}
+
+	kind := typ.Kind()
+	if typ.PkgPath() == "" && typ.Name() == kind.String() {
+		// 预声明的基础类型可以直接断言
+		if kind == reflect.String {
+			return func(a, b any) int {
+				return strings.Compare(a.(string), b.(string))
+			}
+		}
+		return NewCompareFunc(kind)
+	}
+	if kind == reflect.String || IsComputableKind(kind) {
+		// 命名类型无法断言为基础类型，按底层的值比较
+		return func(a, b any) int {
+			res, _ := CompareAny(a, b)
+			return res
+		}
+	}
+
+	return nil
+}
+
 // NewCompareFunc 创建比较函数, -1 小于，0 等于，1 大于
+// 只根据 kind 判断，无法识别 time.Time 等结构体类型，需要时使用 NewCompareFuncOf
 func NewCompareFunc(kind reflect.Kind) func(any, any) int {
 	switch kind {
 	case reflect.Int: